@@ -0,0 +1,23 @@
+// Package batch, defines the batch decision service, which evaluates the decisions of
+// one identity and policy context for many resource contexts in a single call.
+//
+//	{
+//	  "identity_context": {"type": "IDENTITY_TYPE_SUB", "identity": "..."},
+//	  "policy_context": {"path": "policy.documents", "decisions": ["can_read"]},
+//	  "resource_contexts": [{"id": "doc1"}, {"id": "doc2"}],
+//	  "concurrency": 0,
+//	  "strict": false
+//	}
+package batch
+
+// AnnotationBatchID, decision log annotation containing the batch id shared by the decisions of a batch.
+const AnnotationBatchID = "batch_id"
+
+// Limit, returns the batch concurrency, the request concurrency can lower, but not raise, the server concurrency.
+func (r *IsRequest) Limit(concurrency int) int {
+	if r.GetConcurrency() == 0 {
+		return concurrency
+	}
+
+	return min(int(r.GetConcurrency()), concurrency)
+}
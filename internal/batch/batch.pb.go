@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: aserto/topaz/batch/v1/batch.proto
+
+package batch
+
+import (
+	api "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// IsRequest, the decisions of the policy context, evaluated for the identity context and each resource context.
+type IsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// identity context of the decisions.
+	IdentityContext *api.IdentityContext `protobuf:"bytes,1,opt,name=identity_context,json=identityContext,proto3" json:"identity_context,omitempty"`
+	// policy context, the policy path and decisions to evaluate.
+	PolicyContext *api.PolicyContext `protobuf:"bytes,2,opt,name=policy_context,json=policyContext,proto3" json:"policy_context,omitempty"`
+	// policy instance, selects the policy instance by name.
+	PolicyInstance *api.PolicyInstance `protobuf:"bytes,3,opt,name=policy_instance,json=policyInstance,proto3" json:"policy_instance,omitempty"`
+	// resource contexts, the decisions are evaluated for each resource context.
+	ResourceContexts []*structpb.Struct `protobuf:"bytes,4,rep,name=resource_contexts,json=resourceContexts,proto3" json:"resource_contexts,omitempty"`
+	// maximum number of concurrent evaluations, lowers, but does not raise, the server concurrency,
+	// the server concurrency when 0.
+	Concurrency uint32 `protobuf:"varint,5,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	// only accept boolean decision outcomes, a decision returning an object fails its result.
+	Strict        bool `protobuf:"varint,6,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsRequest) Reset() {
+	*x = IsRequest{}
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsRequest) ProtoMessage() {}
+
+func (x *IsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsRequest.ProtoReflect.Descriptor instead.
+func (*IsRequest) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_batch_v1_batch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IsRequest) GetIdentityContext() *api.IdentityContext {
+	if x != nil {
+		return x.IdentityContext
+	}
+	return nil
+}
+
+func (x *IsRequest) GetPolicyContext() *api.PolicyContext {
+	if x != nil {
+		return x.PolicyContext
+	}
+	return nil
+}
+
+func (x *IsRequest) GetPolicyInstance() *api.PolicyInstance {
+	if x != nil {
+		return x.PolicyInstance
+	}
+	return nil
+}
+
+func (x *IsRequest) GetResourceContexts() []*structpb.Struct {
+	if x != nil {
+		return x.ResourceContexts
+	}
+	return nil
+}
+
+func (x *IsRequest) GetConcurrency() uint32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *IsRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+// IsResponse, the results of the batch, in the order of the request resource contexts.
+type IsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// batch id, shared by the decision log entries of the batch.
+	BatchId string `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	// results, in the order of the request resource contexts.
+	Results       []*Result `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsResponse) Reset() {
+	*x = IsResponse{}
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsResponse) ProtoMessage() {}
+
+func (x *IsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsResponse.ProtoReflect.Descriptor instead.
+func (*IsResponse) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_batch_v1_batch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IsResponse) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *IsResponse) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Result, the decisions, and structured outcomes, of a resource context, or the error of its evaluation.
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// boolean decisions keyed by decision name.
+	Decisions map[string]bool `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// structured outcomes keyed by decision name.
+	Outcomes map[string]*structpb.Struct `protobuf:"bytes,2,rep,name=outcomes,proto3" json:"outcomes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// error of the evaluation of the resource context.
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_batch_v1_batch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_batch_v1_batch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Result) GetDecisions() map[string]bool {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *Result) GetOutcomes() map[string]*structpb.Struct {
+	if x != nil {
+		return x.Outcomes
+	}
+	return nil
+}
+
+func (x *Result) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_aserto_topaz_batch_v1_batch_proto protoreflect.FileDescriptor
+
+const file_aserto_topaz_batch_v1_batch_proto_rawDesc = "" +
+	"\n" +
+	"!aserto/topaz/batch/v1/batch.proto\x12\x15aserto.topaz.batch.v1\x1a/aserto/authorizer/v2/api/identity_context.proto\x1a-aserto/authorizer/v2/api/policy_context.proto\x1a.aserto/authorizer/v2/api/policy_instance.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\x84\x03\n" +
+	"\tIsRequest\x12T\n" +
+	"\x10identity_context\x18\x01 \x01(\v2).aserto.authorizer.v2.api.IdentityContextR\x0fidentityContext\x12N\n" +
+	"\x0epolicy_context\x18\x02 \x01(\v2'.aserto.authorizer.v2.api.PolicyContextR\rpolicyContext\x12Q\n" +
+	"\x0fpolicy_instance\x18\x03 \x01(\v2(.aserto.authorizer.v2.api.PolicyInstanceR\x0epolicyInstance\x12D\n" +
+	"\x11resource_contexts\x18\x04 \x03(\v2\x17.google.protobuf.StructR\x10resourceContexts\x12 \n" +
+	"\vconcurrency\x18\x05 \x01(\rR\vconcurrency\x12\x16\n" +
+	"\x06strict\x18\x06 \x01(\bR\x06strict\"`\n" +
+	"\n" +
+	"IsResponse\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x127\n" +
+	"\aresults\x18\x02 \x03(\v2\x1d.aserto.topaz.batch.v1.ResultR\aresults\"\xc7\x02\n" +
+	"\x06Result\x12J\n" +
+	"\tdecisions\x18\x01 \x03(\v2,.aserto.topaz.batch.v1.Result.DecisionsEntryR\tdecisions\x12G\n" +
+	"\boutcomes\x18\x02 \x03(\v2+.aserto.topaz.batch.v1.Result.OutcomesEntryR\boutcomes\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x1a<\n" +
+	"\x0eDecisionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\x1aT\n" +
+	"\rOutcomesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05value:\x028\x012u\n" +
+	"\x05Batch\x12l\n" +
+	"\x02Is\x12 .aserto.topaz.batch.v1.IsRequest\x1a!.aserto.topaz.batch.v1.IsResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/v2/authz/is/batchB2Z0github.com/aserto-dev/topaz/internal/batch;batchb\x06proto3"
+
+var (
+	file_aserto_topaz_batch_v1_batch_proto_rawDescOnce sync.Once
+	file_aserto_topaz_batch_v1_batch_proto_rawDescData []byte
+)
+
+func file_aserto_topaz_batch_v1_batch_proto_rawDescGZIP() []byte {
+	file_aserto_topaz_batch_v1_batch_proto_rawDescOnce.Do(func() {
+		file_aserto_topaz_batch_v1_batch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aserto_topaz_batch_v1_batch_proto_rawDesc), len(file_aserto_topaz_batch_v1_batch_proto_rawDesc)))
+	})
+	return file_aserto_topaz_batch_v1_batch_proto_rawDescData
+}
+
+var file_aserto_topaz_batch_v1_batch_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_aserto_topaz_batch_v1_batch_proto_goTypes = []any{
+	(*IsRequest)(nil),           // 0: aserto.topaz.batch.v1.IsRequest
+	(*IsResponse)(nil),          // 1: aserto.topaz.batch.v1.IsResponse
+	(*Result)(nil),              // 2: aserto.topaz.batch.v1.Result
+	nil,                         // 3: aserto.topaz.batch.v1.Result.DecisionsEntry
+	nil,                         // 4: aserto.topaz.batch.v1.Result.OutcomesEntry
+	(*api.IdentityContext)(nil), // 5: aserto.authorizer.v2.api.IdentityContext
+	(*api.PolicyContext)(nil),   // 6: aserto.authorizer.v2.api.PolicyContext
+	(*api.PolicyInstance)(nil),  // 7: aserto.authorizer.v2.api.PolicyInstance
+	(*structpb.Struct)(nil),     // 8: google.protobuf.Struct
+}
+var file_aserto_topaz_batch_v1_batch_proto_depIdxs = []int32{
+	5, // 0: aserto.topaz.batch.v1.IsRequest.identity_context:type_name -> aserto.authorizer.v2.api.IdentityContext
+	6, // 1: aserto.topaz.batch.v1.IsRequest.policy_context:type_name -> aserto.authorizer.v2.api.PolicyContext
+	7, // 2: aserto.topaz.batch.v1.IsRequest.policy_instance:type_name -> aserto.authorizer.v2.api.PolicyInstance
+	8, // 3: aserto.topaz.batch.v1.IsRequest.resource_contexts:type_name -> google.protobuf.Struct
+	2, // 4: aserto.topaz.batch.v1.IsResponse.results:type_name -> aserto.topaz.batch.v1.Result
+	3, // 5: aserto.topaz.batch.v1.Result.decisions:type_name -> aserto.topaz.batch.v1.Result.DecisionsEntry
+	4, // 6: aserto.topaz.batch.v1.Result.outcomes:type_name -> aserto.topaz.batch.v1.Result.OutcomesEntry
+	8, // 7: aserto.topaz.batch.v1.Result.OutcomesEntry.value:type_name -> google.protobuf.Struct
+	0, // 8: aserto.topaz.batch.v1.Batch.Is:input_type -> aserto.topaz.batch.v1.IsRequest
+	1, // 9: aserto.topaz.batch.v1.Batch.Is:output_type -> aserto.topaz.batch.v1.IsResponse
+	9, // [9:10] is the sub-list for method output_type
+	8, // [8:9] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_aserto_topaz_batch_v1_batch_proto_init() }
+func file_aserto_topaz_batch_v1_batch_proto_init() {
+	if File_aserto_topaz_batch_v1_batch_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aserto_topaz_batch_v1_batch_proto_rawDesc), len(file_aserto_topaz_batch_v1_batch_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aserto_topaz_batch_v1_batch_proto_goTypes,
+		DependencyIndexes: file_aserto_topaz_batch_v1_batch_proto_depIdxs,
+		MessageInfos:      file_aserto_topaz_batch_v1_batch_proto_msgTypes,
+	}.Build()
+	File_aserto_topaz_batch_v1_batch_proto = out.File
+	file_aserto_topaz_batch_v1_batch_proto_goTypes = nil
+	file_aserto_topaz_batch_v1_batch_proto_depIdxs = nil
+}
@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: aserto/topaz/batch/v1/batch.proto
+
+package batch
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Batch_Is_FullMethodName = "/aserto.topaz.batch.v1.Batch/Is"
+)
+
+// BatchClient is the client API for Batch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Batch, evaluates the decisions of one identity and policy context for many resource contexts in a single call.
+type BatchClient interface {
+	// Is, evaluates the decisions of the policy context for the identity context and each resource context.
+	Is(ctx context.Context, in *IsRequest, opts ...grpc.CallOption) (*IsResponse, error)
+}
+
+type batchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBatchClient(cc grpc.ClientConnInterface) BatchClient {
+	return &batchClient{cc}
+}
+
+func (c *batchClient) Is(ctx context.Context, in *IsRequest, opts ...grpc.CallOption) (*IsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsResponse)
+	err := c.cc.Invoke(ctx, Batch_Is_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BatchServer is the server API for Batch service.
+// All implementations should embed UnimplementedBatchServer
+// for forward compatibility.
+//
+// Batch, evaluates the decisions of one identity and policy context for many resource contexts in a single call.
+type BatchServer interface {
+	// Is, evaluates the decisions of the policy context for the identity context and each resource context.
+	Is(context.Context, *IsRequest) (*IsResponse, error)
+}
+
+// UnimplementedBatchServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBatchServer struct{}
+
+func (UnimplementedBatchServer) Is(context.Context, *IsRequest) (*IsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Is not implemented")
+}
+func (UnimplementedBatchServer) testEmbeddedByValue() {}
+
+// UnsafeBatchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BatchServer will
+// result in compilation errors.
+type UnsafeBatchServer interface {
+	mustEmbedUnimplementedBatchServer()
+}
+
+func RegisterBatchServer(s grpc.ServiceRegistrar, srv BatchServer) {
+	// If the following call pancis, it indicates UnimplementedBatchServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Batch_ServiceDesc, srv)
+}
+
+func _Batch_Is_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BatchServer).Is(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Batch_Is_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BatchServer).Is(ctx, req.(*IsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Batch_ServiceDesc is the grpc.ServiceDesc for Batch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Batch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aserto.topaz.batch.v1.Batch",
+	HandlerType: (*BatchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Is",
+			Handler:    _Batch_Is_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aserto/topaz/batch/v1/batch.proto",
+}
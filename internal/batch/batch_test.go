@@ -0,0 +1,26 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/batch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency uint32
+		expected    int
+	}{
+		{"server default", 0, 8},
+		{"lowered", 2, 2},
+		{"not raised", 64, 8},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, (&batch.IsRequest{Concurrency: tc.concurrency}).Limit(8))
+		})
+	}
+}
@@ -18,7 +18,11 @@ import (
 	"github.com/rs/zerolog"
 )
 
-const fileModeOwnerRW = 0o600
+const (
+	fileModeOwnerRW = 0o600
+	devOrganization = "Aserto, Inc."
+	devCASuffix     = "-ca"
+)
 
 // Generator generates certs without any external dependencies.
 type Generator struct {
@@ -31,6 +35,7 @@ type CertGenConfig struct {
 	CertKeyPath      string
 	CertPath         string
 	CertCAPath       string
+	CertCAKeyPath    string
 	DefaultTLSGenDir string
 	DNSNames         []string
 }
@@ -85,20 +90,20 @@ func newGenerator(logger *zerolog.Logger, cfg *CertGenConfig) (*generator, error
 	ca := &x509.Certificate{
 		SerialNumber: snCA,
 		Subject: pkix.Name{
-			Organization:  []string{"Aserto, Inc."},
+			Organization:  []string{devOrganization},
 			Country:       []string{"US"},
 			Province:      []string{"WA"},
 			Locality:      []string{"Seattle"},
 			StreetAddress: []string{"-"},
 			PostalCode:    []string{"-"},
-			CommonName:    cfg.CommonName + "-ca",
+			CommonName:    cfg.CommonName + devCASuffix,
 		},
 		NotBefore:             time.Now(),
 		NotAfter:              time.Now().AddDate(1, 0, 0),
 		IsCA:                  true,
 		BasicConstraintsValid: true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 	}
 
 	ipAddresses := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv4(0, 0, 0, 0), net.IPv6loopback} //nolint:mnd
@@ -115,7 +120,7 @@ func newGenerator(logger *zerolog.Logger, cfg *CertGenConfig) (*generator, error
 	cert := &x509.Certificate{
 		SerialNumber: big.NewInt(certSerialNumber),
 		Subject: pkix.Name{
-			Organization:  []string{"Aserto, Inc."},
+			Organization:  []string{devOrganization},
 			Country:       []string{"US"},
 			Province:      []string{"WA"},
 			Locality:      []string{"Seattle"},
@@ -176,6 +181,13 @@ func (g *generator) generate() error {
 		return errors.Wrap(err, "failed to write ca cert")
 	}
 
+	// the ca key is only persisted when requested, to issue client certificates from the ca.
+	if g.cfg.CertCAKeyPath != "" {
+		if err := writeKey(g.cfg.CertCAKeyPath, caPrivKey); err != nil {
+			return errors.Wrap(err, "failed to write ca key")
+		}
+	}
+
 	return nil
 }
 
@@ -199,19 +211,27 @@ func (g *generator) signCert(certPrivKey, caPrivKey *rsa.PrivateKey) error {
 		return errors.Wrap(err, "failed to encode cert")
 	}
 
-	certPrivKeyPEM := new(bytes.Buffer)
-	if err := pem.Encode(certPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(certPrivKey),
-	}); err != nil {
-		return errors.Wrap(err, "failed to encode key")
+	if err := writeKey(g.cfg.CertKeyPath, certPrivKey); err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(g.cfg.CertKeyPath, certPrivKeyPEM.Bytes(), fileModeOwnerRW); err != nil {
+	if err := os.WriteFile(g.cfg.CertPath, certPEM.Bytes(), fileModeOwnerRW); err != nil {
 		return errors.Wrap(err, "failed to write key")
 	}
 
-	if err := os.WriteFile(g.cfg.CertPath, certPEM.Bytes(), fileModeOwnerRW); err != nil {
+	return nil
+}
+
+func writeKey(keyPath string, privKey *rsa.PrivateKey) error {
+	keyPEM := new(bytes.Buffer)
+	if err := pem.Encode(keyPEM, &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privKey),
+	}); err != nil {
+		return errors.Wrap(err, "failed to encode key")
+	}
+
+	if err := os.WriteFile(keyPath, keyPEM.Bytes(), fileModeOwnerRW); err != nil {
 		return errors.Wrap(err, "failed to write key")
 	}
 
@@ -239,7 +259,7 @@ func (g *generator) checkDir() error {
 	keyDir := filepath.Dir(g.cfg.CertKeyPath)
 	caCertDir := filepath.Dir(g.cfg.CertCAPath)
 
-	if certDir != keyDir || certDir != caCertDir {
+	if certDir != keyDir || certDir != caCertDir || (g.cfg.CertCAKeyPath != "" && certDir != filepath.Dir(g.cfg.CertCAKeyPath)) {
 		return errors.New("output directory for all configured certificates and keys must be the same")
 	}
 
@@ -0,0 +1,170 @@
+package certs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRegenerateCerts, the dev certificates predate client certificate authentication.
+var ErrRegenerateCerts = errors.New("regenerate the dev certs using 'topaz certs generate --force'")
+
+// ClientCertGenConfig contains details about the client certificate issued from a dev CA.
+type ClientCertGenConfig struct {
+	CommonName     string
+	DNSNames       []string
+	URIs           []string
+	EmailAddresses []string
+	CertPath       string
+	CertKeyPath    string
+	CACertPath     string
+	CAKeyPath      string
+	ValidFor       time.Duration
+}
+
+// MakeClientCert issues a client certificate and private key, signed by the CA certificate and
+// key of the config, and persists them in the cert and key paths.
+func (c *Generator) MakeClientCert(cfg *ClientCertGenConfig) error {
+	c.logger.Info().Str("common-name", cfg.CommonName).Str("cert-path", cfg.CertPath).Msg("generating client certificate")
+
+	ca, err := tls.LoadX509KeyPair(cfg.CACertPath, cfg.CAKeyPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to load ca certificate and key")
+	}
+
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to parse ca certificate")
+	}
+
+	caKey, ok := ca.PrivateKey.(crypto.Signer)
+	if !ok {
+		return errors.New("unsupported ca key")
+	}
+
+	uris := make([]*url.URL, 0, len(cfg.URIs))
+
+	for _, value := range cfg.URIs {
+		uri, err := url.Parse(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid uri %q", value)
+		}
+
+		uris = append(uris, uri)
+	}
+
+	sn, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), caSerialNumberBits))
+	if err != nil {
+		return errors.Wrap(err, "failed to generate serial number")
+	}
+
+	validFor := cfg.ValidFor
+	if validFor == 0 {
+		validFor = time.Until(caCert.NotAfter)
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber: sn,
+		Subject: pkix.Name{
+			Organization: []string{devOrganization},
+			CommonName:   cfg.CommonName,
+		},
+		DNSNames:       cfg.DNSNames,
+		URIs:           uris,
+		EmailAddresses: cfg.EmailAddresses,
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(validFor),
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+	}
+
+	certPrivKey, err := rsa.GenerateKey(rand.Reader, privateKeyBits)
+	if err != nil {
+		return err
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, caCert, &certPrivKey.PublicKey, caKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign client certificate")
+	}
+
+	certPEM := new(bytes.Buffer)
+	if err := pem.Encode(certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: certBytes}); err != nil {
+		return errors.Wrap(err, "failed to encode cert")
+	}
+
+	if err := writeKey(cfg.CertKeyPath, certPrivKey); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(cfg.CertPath, certPEM.Bytes(), fileModeOwnerRW); err != nil {
+		return errors.Wrap(err, "failed to write cert")
+	}
+
+	return nil
+}
+
+// CheckClientCA verifies the CA certificates of the PEM file can verify client certificates, the
+// CA must allow client authentication, and the key of a dev CA must be persisted alongside the CA
+// certificate, to issue client certificates. Dev certificates generated before client certificate
+// authentication are rejected with ErrRegenerateCerts.
+func CheckClientCA(caCertPath string) error {
+	buf, err := os.ReadFile(caCertPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %q", caCertPath)
+	}
+
+	for block, rest := pem.Decode(buf); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse %q", caCertPath)
+		}
+
+		if !allowsClientAuth(ca) {
+			return errors.Wrapf(ErrRegenerateCerts, "ca certificate %q does not allow client authentication", caCertPath)
+		}
+
+		if !isDevCA(ca) {
+			continue
+		}
+
+		caKeyPath := strings.TrimSuffix(caCertPath, filepath.Ext(caCertPath)) + ".key"
+		if _, err := os.Stat(caKeyPath); err != nil {
+			return errors.Wrapf(ErrRegenerateCerts, "dev ca key %q not found", caKeyPath)
+		}
+	}
+
+	return nil
+}
+
+// allowsClientAuth, a certificate without extended key usages allows any usage.
+func allowsClientAuth(cert *x509.Certificate) bool {
+	return len(cert.ExtKeyUsage) == 0 ||
+		slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageClientAuth) ||
+		slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageAny)
+}
+
+// isDevCA, returns true when the CA certificate is generated by the dev cert generator.
+func isDevCA(cert *x509.Certificate) bool {
+	return cert.IsCA &&
+		slices.Equal(cert.Subject.Organization, []string{devOrganization}) &&
+		strings.HasSuffix(cert.Subject.CommonName, devCASuffix)
+}
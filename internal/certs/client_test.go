@@ -0,0 +1,86 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/certs"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// TestCheckClientCA validates dev certs without a persisted CA key, and CAs without the client
+// authentication usage, are rejected.
+func TestCheckClientCA(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "grpc-ca.crt")
+	caKeyPath := filepath.Join(dir, "grpc-ca.key")
+
+	logger := zerolog.Nop()
+	if err := certs.NewGenerator(logger.WithContext(context.Background())).MakeDevCert(&certs.CertGenConfig{
+		CommonName:    "topaz-grpc",
+		CertKeyPath:   filepath.Join(dir, "grpc.key"),
+		CertPath:      filepath.Join(dir, "grpc.crt"),
+		CertCAPath:    caPath,
+		CertCAKeyPath: caKeyPath,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := certs.CheckClientCA(caPath); err != nil {
+		t.Fatalf("expected dev ca to verify client certificates: %v", err)
+	}
+
+	if err := os.Remove(caKeyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := certs.CheckClientCA(caPath); !errors.Is(err, certs.ErrRegenerateCerts) {
+		t.Errorf("expected regenerate error without ca key, got %v", err)
+	}
+
+	serverCAPath := filepath.Join(dir, "server-ca.crt")
+	writeCA(t, serverCAPath, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+
+	if err := certs.CheckClientCA(serverCAPath); !errors.Is(err, certs.ErrRegenerateCerts) {
+		t.Errorf("expected regenerate error for server auth ca, got %v", err)
+	}
+}
+
+func writeCA(t *testing.T, caPath string, usages []x509.ExtKeyUsage) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "server-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		ExtKeyUsage:           usages,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, ca, ca, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
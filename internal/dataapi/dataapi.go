@@ -0,0 +1,141 @@
+// Package dataapi, implements the OPA compatible data API (POST /v1/data/{path}), the HTTP
+// requests are served by the authorizer, which evaluates data.<path> against the runtime of
+// the policy instance.
+package dataapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/topdown"
+	"github.com/open-policy-agent/opa/v1/topdown/lineage"
+	"github.com/pkg/errors"
+)
+
+// Request, the evaluation request of the data API.
+type Request struct {
+	// Path, the slash separated path of the evaluated document, relative to data.
+	Path string `json:"path"`
+	// Input, the input document, nil when not provided.
+	Input *any `json:"input,omitempty"`
+	// Explain, the explanation mode, notes, fails, full or debug, empty when off.
+	Explain types.ExplainModeV1 `json:"explain,omitempty"`
+	// Metrics, return the evaluation metrics.
+	Metrics bool `json:"metrics,omitempty"`
+	// Pretty, return a human readable explanation.
+	Pretty bool `json:"pretty,omitempty"`
+	// Instance, the policy instance, when empty the instance is selected by the path prefix.
+	Instance string `json:"instance,omitempty"`
+}
+
+// Response, the evaluation response of the data API, using the layout of the OPA data API response.
+type Response struct {
+	DecisionID  string          `json:"decision_id,omitempty"`
+	Explanation json.RawMessage `json:"explanation,omitempty"`
+	Metrics     map[string]any  `json:"metrics,omitempty"`
+	Result      *any            `json:"result,omitempty"`
+}
+
+// PolicyPath, returns the dot separated policy path of the request path.
+func (r *Request) PolicyPath() string {
+	return strings.Join(r.segments(), ".")
+}
+
+// Query, returns the query body evaluating the document of the request path.
+func (r *Request) Query() ast.Body {
+	ref := ast.DefaultRootRef.Copy()
+	for _, segment := range r.segments() {
+		ref = append(ref, ast.StringTerm(segment))
+	}
+
+	return ast.NewBody(ast.NewExpr(ast.NewTerm(ref)))
+}
+
+func (r *Request) segments() []string {
+	segments := []string{}
+
+	for segment := range strings.SplitSeq(r.Path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// Evaluate, evaluates the document of the request path, using the compiler and store of the
+// runtime, the result is nil when the document is undefined.
+func Evaluate(ctx context.Context, compiler *ast.Compiler, store storage.Store, req *Request) (*Response, error) {
+	m := metrics.New()
+
+	opts := []func(*rego.Rego){
+		rego.Compiler(compiler),
+		rego.Store(store),
+		rego.ParsedQuery(req.Query()),
+		rego.Metrics(m),
+	}
+
+	if req.Input != nil {
+		opts = append(opts, rego.Input(*req.Input))
+	}
+
+	var tracer *topdown.BufferTracer
+	if req.Explain != "" && req.Explain != types.ExplainOffV1 {
+		tracer = topdown.NewBufferTracer()
+		opts = append(opts, rego.QueryTracer(tracer))
+	}
+
+	rs, err := rego.New(opts...).Eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Response{}
+
+	if len(rs) > 0 && len(rs[0].Expressions) > 0 {
+		resp.Result = &rs[0].Expressions[0].Value
+	}
+
+	if req.Metrics {
+		resp.Metrics = m.All()
+	}
+
+	if tracer != nil {
+		explanation, err := explain(req.Explain, *tracer, req.Pretty)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Explanation = explanation
+	}
+
+	return resp, nil
+}
+
+func explain(mode types.ExplainModeV1, trace []*topdown.Event, pretty bool) (json.RawMessage, error) {
+	switch mode {
+	case types.ExplainNotesV1:
+		trace = lineage.Notes(trace)
+	case types.ExplainFailsV1:
+		trace = lineage.Fails(trace)
+	case types.ExplainFullV1:
+		trace = lineage.Full(trace)
+	case types.ExplainDebugV1:
+		trace = lineage.Debug(trace)
+	default:
+		return nil, errors.Errorf("invalid explain mode %q", mode)
+	}
+
+	explanation, err := types.NewTraceV1(trace, pretty)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format explanation")
+	}
+
+	return json.RawMessage(explanation), nil
+}
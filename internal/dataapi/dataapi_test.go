@@ -0,0 +1,157 @@
+package dataapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/dataapi"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const policy = `package todo.GET.todos
+
+default allowed = false
+
+allowed {
+	input.user.role == "admin"
+}
+`
+
+func compile(t *testing.T) *ast.Compiler {
+	t.Helper()
+
+	module, err := ast.ParseModuleWithOpts("todo.rego", policy, ast.ParserOptions{RegoVersion: ast.RegoV0})
+	require.NoError(t, err)
+
+	compiler := ast.NewCompiler()
+	compiler.Compile(map[string]*ast.Module{"todo.rego": module})
+	require.False(t, compiler.Failed(), compiler.Errors)
+
+	return compiler
+}
+
+func TestEvaluate(t *testing.T) {
+	compiler := compile(t)
+	store := inmem.NewFromObject(map[string]any{"roles": map[string]any{"admin": true}})
+
+	var input any = map[string]any{"user": map[string]any{"role": "admin"}}
+
+	resp, err := dataapi.Evaluate(context.Background(), compiler, store, &dataapi.Request{
+		Path:    "todo/GET/todos/allowed",
+		Input:   &input,
+		Metrics: true,
+		Explain: "notes",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Result)
+	assert.Equal(t, true, *resp.Result)
+	assert.Contains(t, resp.Metrics, "timer_rego_query_eval_ns")
+	assert.NotEmpty(t, resp.Explanation)
+
+	resp, err = dataapi.Evaluate(context.Background(), compiler, store, &dataapi.Request{Path: "roles"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"admin": true}, *resp.Result)
+
+	resp, err = dataapi.Evaluate(context.Background(), compiler, store, &dataapi.Request{Path: "todo/POST/todos/allowed"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Result)
+}
+
+func TestRequestPath(t *testing.T) {
+	req := &dataapi.Request{Path: "/todo/GET/todos/"}
+	assert.Equal(t, "todo.GET.todos", req.PolicyPath())
+	assert.Equal(t, `data.todo.GET.todos`, req.Query().String())
+}
+
+type fakeEvaluator struct {
+	req  *dataapi.Request
+	resp *dataapi.Response
+	err  error
+}
+
+func (e *fakeEvaluator) DataEvaluate(_ context.Context, req *dataapi.Request) (*dataapi.Response, error) {
+	e.req = req
+
+	return e.resp, e.err
+}
+
+func serve(t *testing.T, evaluator dataapi.Evaluator, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(dataapi.PathPrefix, dataapi.Handler(evaluator))
+	mux.Handle(dataapi.PathPrefix+"/", dataapi.Handler(evaluator))
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandlerPost(t *testing.T) {
+	var result any = true
+
+	evaluator := &fakeEvaluator{resp: &dataapi.Response{DecisionID: "42", Result: &result}}
+
+	w := serve(t, evaluator, http.MethodPost, "/v1/data/todo/GET/todos/allowed?metrics&explain=fails&pretty=true",
+		`{"input": {"user": {"role": "admin"}}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, "todo/GET/todos/allowed", evaluator.req.Path)
+	assert.Equal(t, map[string]any{"user": map[string]any{"role": "admin"}}, *evaluator.req.Input)
+	assert.True(t, evaluator.req.Metrics)
+	assert.True(t, evaluator.req.Pretty)
+	assert.Equal(t, "fails", string(evaluator.req.Explain))
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]any{"decision_id": "42", "result": true}, resp)
+}
+
+func TestHandlerGet(t *testing.T) {
+	evaluator := &fakeEvaluator{resp: &dataapi.Response{}}
+
+	w := serve(t, evaluator, http.MethodGet, `/v1/data?input={"a":1}`, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Empty(t, evaluator.req.Path)
+	assert.Equal(t, map[string]any{"a": float64(1)}, *evaluator.req.Input)
+	assert.JSONEq(t, `{}`, w.Body.String())
+}
+
+func TestHandlerErrors(t *testing.T) {
+	evaluator := &fakeEvaluator{err: status.Error(codes.PermissionDenied, "not allowed")}
+
+	w := serve(t, evaluator, http.MethodPost, "/v1/data/todo", `{"input":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_parameter")
+
+	w = serve(t, evaluator, http.MethodPost, "/v1/data/todo", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"code": "unauthorized", "message": "not allowed"}`, w.Body.String())
+
+	w = serve(t, evaluator, http.MethodDelete, "/v1/data/todo", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandlerBodyTooLarge(t *testing.T) {
+	evaluator := &fakeEvaluator{resp: &dataapi.Response{}}
+
+	body := `{"input": "` + strings.Repeat("a", dataapi.MaxBodySize) + `"}`
+
+	w := serve(t, evaluator, http.MethodPost, "/v1/data/todo", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_parameter")
+	assert.Nil(t, evaluator.req)
+}
@@ -0,0 +1,170 @@
+package dataapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// PathPrefix, the path of the data API root document, the documents below it are served on the
+	// sub-paths, the requests are authenticated as calls of the path prefix.
+	PathPrefix = "/v1/data"
+	// ParamInstance, the name of the HTTP URL parameter selecting the policy instance.
+	ParamInstance = "instance"
+	// ParamInput, the name of the HTTP URL parameter holding the JSON encoded input of a GET request.
+	ParamInput = "input"
+	// MaxBodySize, the maximum size of the body of a data API request, the default maximum
+	// message size received by the gRPC server.
+	MaxBodySize = 4 << 20
+)
+
+// Evaluator, evaluates the document of the data API request.
+type Evaluator interface {
+	DataEvaluate(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Handler, returns the HTTP handler of the data API, of the root document and of the documents
+// below it, the request is evaluated in-process by the evaluator, the errors of the evaluator are
+// mapped to the HTTP status and the OPA error code of their gRPC status.
+func Handler(evaluator Evaluator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pretty := boolParam(r, types.ParamPrettyV1)
+
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, types.NewErrorV1(types.CodeInvalidOperation, "method not allowed"), pretty)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBodySize)
+
+		req, err := newRequest(r, strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, PathPrefix), "/"))
+		if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, types.NewErrorV1(types.CodeInvalidParameter,
+				"request body exceeds the limit of %d bytes", maxErr.Limit), pretty)
+
+			return
+		}
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, types.NewErrorV1(types.CodeInvalidParameter, "%s", err.Error()), pretty)
+			return
+		}
+
+		resp, err := evaluator.DataEvaluate(r.Context(), req)
+		if err != nil {
+			st := status.Convert(err)
+			httpStatus, code := errorCode(st.Code())
+			writeError(w, httpStatus, types.NewErrorV1(code, "%s", st.Message()), pretty)
+
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp, pretty)
+	})
+}
+
+// newRequest, returns the data request of the HTTP request, the input is read from the body
+// of a POST request, bounded by MaxBodySize, and from the input URL parameter of a GET request.
+func newRequest(r *http.Request, path string) (*Request, error) {
+	req := &Request{
+		Path:     path,
+		Explain:  explainMode(r),
+		Metrics:  boolParam(r, types.ParamMetricsV1),
+		Pretty:   boolParam(r, types.ParamPrettyV1),
+		Instance: r.URL.Query().Get(ParamInstance),
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if value := r.URL.Query().Get(ParamInput); value != "" {
+			var input any
+			if err := json.Unmarshal([]byte(value), &input); err != nil {
+				return nil, errors.New("input parameter contains malformed input document")
+			}
+
+			req.Input = &input
+		}
+	default:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(strings.TrimSpace(string(body))) > 0 {
+			var data types.DataRequestV1
+			if err := json.Unmarshal(body, &data); err != nil {
+				return nil, errors.New("body contains malformed input document")
+			}
+
+			req.Input = data.Input
+		}
+	}
+
+	return req, nil
+}
+
+// explainMode, returns the explanation mode of the explain URL parameter, unknown modes turn explanations off.
+func explainMode(r *http.Request) types.ExplainModeV1 {
+	switch mode := types.ExplainModeV1(r.URL.Query().Get(types.ParamExplainV1)); mode {
+	case types.ExplainNotesV1, types.ExplainFailsV1, types.ExplainFullV1, types.ExplainDebugV1:
+		return mode
+	default:
+		return types.ExplainOffV1
+	}
+}
+
+// boolParam, returns true when the URL parameter is present without a value or with the value true.
+func boolParam(r *http.Request, name string) bool {
+	values, ok := r.URL.Query()[name]
+	if !ok {
+		return false
+	}
+
+	for _, value := range values {
+		if value == "" || strings.EqualFold(value, "true") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorCode, returns the HTTP status and the OPA error code of the gRPC status code.
+func errorCode(code codes.Code) (int, string) {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest, types.CodeInvalidParameter
+	case codes.NotFound:
+		return http.StatusNotFound, types.CodeResourceNotFound
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, types.CodeUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden, types.CodeUnauthorized
+	default:
+		return http.StatusInternalServerError, types.CodeInternal
+	}
+}
+
+func writeError(w http.ResponseWriter, httpStatus int, err *types.ErrorV1, pretty bool) {
+	writeJSON(w, httpStatus, err, pretty)
+}
+
+func writeJSON(w http.ResponseWriter, httpStatus int, v any, pretty bool) {
+	enc := json.NewEncoder(w)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+
+	_ = enc.Encode(v)
+}
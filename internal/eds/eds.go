@@ -2,18 +2,29 @@ package eds
 
 import (
 	"context"
+	"slices"
 
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	v3 "github.com/aserto-dev/topaz/internal/eds/pkg/directory/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 )
 
 func New(ctx context.Context, config *directory.Config, logger *zerolog.Logger) (*directory.Directory, error) {
 	newLogger := logger.With().Str("component", "edge-ds").Logger()
 
-	ds, err := directory.New(ctx, config, &newLogger)
+	dir, err := directory.New(ctx, config, &newLogger)
 	if err != nil {
 		return nil, err
 	}
 
-	return ds, nil
+	return dir, nil
+}
+
+// Collectors, returns the directory metrics collectors, of the checks, the writer and importer
+// operations, and the BoltDB store.
+func Collectors() []prometheus.Collector {
+	return slices.Concat(ds.Collectors(), v3.Collectors(), bdb.Collectors())
 }
@@ -30,6 +30,7 @@ type Config struct {
 	RequestTimeout time.Duration
 	MaxBatchSize   int           `json:"-"` // obsolete bbolt configuration value.
 	MaxBatchDelay  time.Duration `json:"-"` // obsolete bbolt configuration value.
+	Encryption     *EncryptionConfig
 }
 
 // BoltDB based key-value store.
@@ -72,8 +73,15 @@ func (s *BoltDB) Open() error {
 		return errors.Wrapf(err, "failed to open directory '%s'", s.config.DBPath)
 	}
 
+	if err := s.openCipher(db); err != nil {
+		_ = db.Close()
+		return err
+	}
+
 	s.db = db
 
+	stores.Store(s.config.DBPath, struct{}{})
+
 	return nil
 }
 
@@ -81,11 +89,37 @@ func (s *BoltDB) Open() error {
 func (s *BoltDB) Close() {
 	if s.db != nil {
 		s.logger.Info().Str("db_path", s.config.DBPath).Msg("close")
+		ciphers.Delete(s.db)
+		stores.Delete(s.config.DBPath)
 		s.db.Close()
 		s.db = nil
 	}
 }
 
+// openCipher, registers the value cipher for the database when encryption is configured.
+// An existing plaintext store is encrypted in place on first open.
+func (s *BoltDB) openCipher(db *bolt.DB) error {
+	if !s.config.Encryption.Enabled() {
+		return nil
+	}
+
+	kek, err := s.config.Encryption.Key()
+	if err != nil {
+		return err
+	}
+
+	c, err := openDataKey(db, kek)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().Str("db_path", s.config.DBPath).Msg("encryption enabled")
+
+	ciphers.Store(db, c)
+
+	return nil
+}
+
 func (s *BoltDB) DB() *bolt.DB {
 	return s.db
 }
@@ -218,7 +252,12 @@ func SetKey(tx *bolt.Tx, path Path, key, value []byte) error {
 		return ErrPathNotFound
 	}
 
-	return b.Put(key, value)
+	buf, err := cipherFor(tx, path).Seal(value)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, buf)
 }
 
 // DeleteKey, delete key and value in path specified bucket, when it exists. None existing keys will not raise an error.
@@ -251,7 +290,7 @@ func GetKey(tx *bolt.Tx, path Path, key []byte) ([]byte, error) {
 		return []byte{}, ErrKeyNotFound
 	}
 
-	return v, nil
+	return cipherFor(tx, path).Open(v)
 }
 
 // KeyExists, check if the key exists in the path specified bucket.
@@ -0,0 +1,445 @@
+package bdb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strings"
+
+	cerr "github.com/aserto-dev/errors"
+	"github.com/aserto-dev/topaz/internal/tsync"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+)
+
+//nolint:lll // single line readability more important.
+var (
+	ErrEncryptionKeyMissing = cerr.NewAsertoError("E20056", codes.FailedPrecondition, http.StatusPreconditionFailed, "directory store is encrypted, no encryption key configured")
+	ErrEncryptionKeyInvalid = cerr.NewAsertoError("E20057", codes.FailedPrecondition, http.StatusPreconditionFailed, "invalid directory encryption key")
+	ErrDecryptValue         = cerr.NewAsertoError("E20058", codes.DataLoss, http.StatusInternalServerError, "failed to decrypt directory value")
+)
+
+const (
+	keySize int = 32 // AES-256.
+)
+
+var (
+	// sealedMarker prefixes every encrypted value, a leading 0x00 byte is never produced
+	// by the protobuf (field number 0 is invalid) or JSON encoded values stored in the buckets.
+	sealedMarker = []byte{0x00, 0x01}
+
+	// DataKeyKey, _system.dek key, holds the data encryption key wrapped by the key encryption key.
+	DataKeyKey = []byte("dek")
+
+	// ciphers, registry of value ciphers keyed by the bolt database instance.
+	ciphers tsync.Map[*bolt.DB, *Cipher]
+)
+
+// EncryptionConfig, configures envelope encryption of bucket values.
+// The key encryption key (KEK) is a base64 encoded 32 byte key, read from either KeyFile or the KeyEnv environment variable.
+type EncryptionConfig struct {
+	KeyFile string `json:"key_file"`
+	KeyEnv  string `json:"key_env"`
+}
+
+// Enabled, returns true when a key encryption key source has been configured.
+func (c *EncryptionConfig) Enabled() bool {
+	return c != nil && (c.KeyFile != "" || c.KeyEnv != "")
+}
+
+// Key, reads and decodes the key encryption key from the configured source.
+func (c *EncryptionConfig) Key() ([]byte, error) {
+	var encoded string
+
+	switch {
+	case c.KeyFile != "":
+		buf, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read key file '%s'", c.KeyFile)
+		}
+
+		encoded = string(buf)
+
+	case c.KeyEnv != "":
+		val, ok := os.LookupEnv(c.KeyEnv)
+		if !ok {
+			return nil, ErrEncryptionKeyInvalid.Msgf("environment variable %s not set", c.KeyEnv)
+		}
+
+		encoded = val
+
+	default:
+		return nil, ErrEncryptionKeyMissing
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, ErrEncryptionKeyInvalid.Err(err).Msg("key is not base64 encoded")
+	}
+
+	if len(key) != keySize {
+		return nil, ErrEncryptionKeyInvalid.Msgf("key length must be %d bytes", keySize)
+	}
+
+	return key, nil
+}
+
+// NewKey, generates a new random 32 byte key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Cipher, AES-GCM based value cipher.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrEncryptionKeyInvalid.Err(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, ErrEncryptionKeyInvalid.Err(err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal, encrypts the value, the result is formatted as [marker][nonce][ciphertext].
+// A nil cipher returns the value unmodified.
+func (c *Cipher) Seal(value []byte) ([]byte, error) {
+	if c == nil {
+		return value, nil
+	}
+
+	nonceSize := c.aead.NonceSize()
+
+	buf := make([]byte, len(sealedMarker)+nonceSize, len(sealedMarker)+nonceSize+len(value)+c.aead.Overhead())
+	copy(buf, sealedMarker)
+
+	nonce := buf[len(sealedMarker):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(buf, nonce, value, nil), nil
+}
+
+// Open, decrypts a sealed value, plaintext values are returned unmodified.
+func (c *Cipher) Open(value []byte) ([]byte, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	if c == nil {
+		return nil, ErrEncryptionKeyMissing
+	}
+
+	nonceSize := c.aead.NonceSize()
+
+	buf := value[len(sealedMarker):]
+	if len(buf) < nonceSize {
+		return nil, ErrDecryptValue.Msg("value too short")
+	}
+
+	plain, err := c.aead.Open(nil, buf[:nonceSize], buf[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrDecryptValue.Err(err)
+	}
+
+	return plain, nil
+}
+
+// IsSealed, returns true when the value is encrypted.
+func IsSealed(value []byte) bool {
+	return bytes.HasPrefix(value, sealedMarker)
+}
+
+// cipherOf, returns the value cipher registered for the database of the transaction, nil when the store is not encrypted.
+func cipherOf(tx *bolt.Tx) *Cipher {
+	if c, ok := ciphers.Load(tx.DB()); ok {
+		return c
+	}
+
+	return nil
+}
+
+// cipherFor, returns the value cipher for the path, values in the _system bucket are never encrypted.
+func cipherFor(tx *bolt.Tx, path Path) *Cipher {
+	if isSystemPath(path) {
+		return nil
+	}
+
+	return cipherOf(tx)
+}
+
+func isSystemPath(path Path) bool {
+	return len(path) > 0 && path[0] == SystemPath[0]
+}
+
+// IsEncrypted, returns true when the store contains a wrapped data encryption key.
+func IsEncrypted(db *bolt.DB) (bool, error) {
+	encrypted := false
+
+	err := db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(SystemPath[0]))
+		encrypted = b != nil && b.Get(DataKeyKey) != nil
+
+		return nil
+	})
+
+	return encrypted, err
+}
+
+// openDataKey, unwraps the data encryption key using the key encryption key.
+// When the store does not contain a data encryption key, a new key is generated
+// and all existing values are encrypted in place.
+func openDataKey(db *bolt.DB, kek []byte) (*Cipher, error) {
+	kekCipher, err := NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataCipher *Cipher
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := CreateBucket(tx, SystemPath)
+		if err != nil {
+			return err
+		}
+
+		if wrapped := b.Get(DataKeyKey); wrapped != nil {
+			dataCipher, err = unwrapDataKey(kekCipher, wrapped)
+			return err
+		}
+
+		dataCipher, err = newDataKey(b, kekCipher)
+		if err != nil {
+			return err
+		}
+
+		return transformValues(tx, nil, dataCipher)
+	})
+
+	return dataCipher, err
+}
+
+// Rekey, rotates the key encryption key, when rotateDataKey is set, a new data encryption key
+// is generated and all values are re-encrypted. A plaintext store is encrypted in place.
+func Rekey(db *bolt.DB, oldKEK, newKEK []byte, rotateDataKey bool) error {
+	newKEKCipher, err := NewCipher(newKEK)
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := CreateBucket(tx, SystemPath)
+		if err != nil {
+			return err
+		}
+
+		wrapped := b.Get(DataKeyKey)
+
+		// plaintext store, generate data key and encrypt in place.
+		if wrapped == nil {
+			dataCipher, err := newDataKey(b, newKEKCipher)
+			if err != nil {
+				return err
+			}
+
+			return transformValues(tx, nil, dataCipher)
+		}
+
+		if oldKEK == nil {
+			return ErrEncryptionKeyMissing
+		}
+
+		oldKEKCipher, err := NewCipher(oldKEK)
+		if err != nil {
+			return err
+		}
+
+		dek, err := oldKEKCipher.Open(wrapped)
+		if err != nil {
+			return ErrEncryptionKeyInvalid.Err(err).Msg("unable to unwrap data key")
+		}
+
+		if !rotateDataKey {
+			return wrapDataKey(b, newKEKCipher, dek)
+		}
+
+		oldDataCipher, err := NewCipher(dek)
+		if err != nil {
+			return err
+		}
+
+		newDataCipher, err := newDataKey(b, newKEKCipher)
+		if err != nil {
+			return err
+		}
+
+		return transformValues(tx, oldDataCipher, newDataCipher)
+	})
+}
+
+// Decrypt, decrypts all values of an encrypted store in place, and removes the wrapped data key,
+// used by the schema migrations, which operate on plaintext values, the store is encrypted again,
+// with a new data key, by Rekey.
+func Decrypt(db *bolt.DB, kek []byte) error {
+	kekCipher, err := NewCipher(kek)
+	if err != nil {
+		return err
+	}
+
+	return db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(SystemPath[0]))
+		if b == nil || b.Get(DataKeyKey) == nil {
+			return nil
+		}
+
+		dataCipher, err := unwrapDataKey(kekCipher, b.Get(DataKeyKey))
+		if err != nil {
+			return err
+		}
+
+		if err := transformValues(tx, dataCipher, nil); err != nil {
+			return err
+		}
+
+		return b.Delete(DataKeyKey)
+	})
+}
+
+func newDataKey(b *bolt.Bucket, kekCipher *Cipher) (*Cipher, error) {
+	dek, err := NewKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wrapDataKey(b, kekCipher, dek); err != nil {
+		return nil, err
+	}
+
+	return NewCipher(dek)
+}
+
+func wrapDataKey(b *bolt.Bucket, kekCipher *Cipher, dek []byte) error {
+	wrapped, err := kekCipher.Seal(dek)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(DataKeyKey, wrapped)
+}
+
+func unwrapDataKey(kekCipher *Cipher, wrapped []byte) (*Cipher, error) {
+	dek, err := kekCipher.Open(wrapped)
+	if err != nil {
+		return nil, ErrEncryptionKeyInvalid.Err(err).Msg("unable to unwrap data key")
+	}
+
+	return NewCipher(dek)
+}
+
+// transformBatchSize, number of values re-written before the cursor is repositioned.
+const transformBatchSize int = 1000
+
+// transformValues, re-writes all values outside the _system bucket, decrypting with src (nil when plaintext)
+// and encrypting with dst. Keys are left untouched, to keep prefix scans operational.
+func transformValues(tx *bolt.Tx, src, dst *Cipher) error {
+	return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		if string(name) == SystemPath[0] {
+			return nil
+		}
+
+		return transformBucket(b, src, dst)
+	})
+}
+
+func transformBucket(b *bolt.Bucket, src, dst *Cipher) error {
+	type kv struct {
+		key   []byte
+		value []byte
+	}
+
+	var (
+		batch   = make([]kv, 0, transformBatchSize)
+		buckets [][]byte
+		seek    []byte
+	)
+
+	c := b.Cursor()
+
+	for {
+		batch = batch[:0]
+
+		k, v := c.First()
+		if seek != nil {
+			// reposition cursor after last processed key, mutations invalidate the cursor.
+			if k, v = c.Seek(seek); k != nil && bytes.Equal(k, seek) {
+				k, v = c.Next()
+			}
+		}
+
+		for ; k != nil && len(batch) < transformBatchSize; k, v = c.Next() {
+			if v == nil {
+				// nested bucket, processed after values of the current bucket have been transformed.
+				seek = bytes.Clone(k)
+				buckets = append(buckets, seek)
+
+				continue
+			}
+
+			seek = bytes.Clone(k)
+
+			if IsSealed(v) && src == nil {
+				// already encrypted (resumed encryption of a plaintext store).
+				continue
+			}
+
+			plain, err := src.Open(v)
+			if err != nil {
+				return err
+			}
+
+			sealed, err := dst.Seal(plain)
+			if err != nil {
+				return err
+			}
+
+			batch = append(batch, kv{key: seek, value: sealed})
+		}
+
+		for _, e := range batch {
+			if err := b.Put(e.key, e.value); err != nil {
+				return err
+			}
+		}
+
+		if k == nil {
+			break
+		}
+	}
+
+	for _, name := range buckets {
+		if err := transformBucket(b.Bucket(name), src, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
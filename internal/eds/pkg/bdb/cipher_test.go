@@ -0,0 +1,220 @@
+package bdb_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+
+	"github.com/rs/zerolog"
+	bolt "go.etcd.io/bbolt"
+)
+
+const objCount = 2500
+
+// TestEncryptInPlace validates a plaintext store is encrypted on open and remains readable after key rotation.
+func TestEncryptInPlace(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	kek1, kek2 := newKey(t), newKey(t)
+	t.Setenv("TEST_KEK", kek1)
+
+	// create plaintext store.
+	store := openStore(t, dbPath, nil)
+	if err := store.DB().Update(func(tx *bolt.Tx) error {
+		if _, err := bdb.CreateBucket(tx, bdb.ObjectsPath); err != nil {
+			return err
+		}
+
+		for i := range objCount {
+			obj := &dsc.Object{Type: "user", Id: fmt.Sprintf("user-%05d", i)}
+			if _, err := bdb.Set(ctx, tx, bdb.ObjectsPath, []byte("user:"+obj.GetId()), obj); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSealed(t, store, false)
+	store.Close()
+
+	// open with encryption, encrypts values in place.
+	store = openStore(t, dbPath, &bdb.EncryptionConfig{KeyEnv: "TEST_KEK"})
+	assertSealed(t, store, true)
+	assertReadable(t, store)
+
+	// rotate key encryption key and data encryption key.
+	t.Setenv("TEST_KEK", kek2)
+
+	if err := bdb.Rekey(store.DB(), decode(t, kek1), decode(t, kek2), true); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Close()
+
+	store = openStore(t, dbPath, &bdb.EncryptionConfig{KeyEnv: "TEST_KEK"})
+	assertSealed(t, store, true)
+	assertReadable(t, store)
+	store.Close()
+
+	// without a key, reads must fail.
+	store = openStore(t, dbPath, nil)
+	defer store.Close()
+
+	if err := store.DB().View(func(tx *bolt.Tx) error {
+		_, err := bdb.Get[dsc.Object](ctx, tx, bdb.ObjectsPath, []byte("user:user-00000"))
+		return err
+	}); err == nil {
+		t.Error("expected error reading encrypted store without key")
+	}
+}
+
+// TestRekeyInvalidKey validates the current key is verified before rotating.
+func TestRekeyInvalidKey(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	kek1 := newKey(t)
+	t.Setenv("TEST_KEK", kek1)
+
+	store := openStore(t, dbPath, &bdb.EncryptionConfig{KeyEnv: "TEST_KEK"})
+	defer store.Close()
+
+	if err := bdb.Rekey(store.DB(), decode(t, newKey(t)), decode(t, newKey(t)), false); err == nil {
+		t.Error("expected error rotating with invalid key")
+	}
+}
+
+// TestScanUndecodable validates the scan iterators stop on a value which cannot be decrypted and return the error.
+func TestScanUndecodable(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	t.Setenv("TEST_KEK", newKey(t))
+
+	store := openStore(t, dbPath, &bdb.EncryptionConfig{KeyEnv: "TEST_KEK"})
+	if err := store.DB().Update(func(tx *bolt.Tx) error {
+		if _, err := bdb.CreateBucket(tx, bdb.ObjectsPath); err != nil {
+			return err
+		}
+
+		_, err := bdb.Set(ctx, tx, bdb.ObjectsPath, []byte("user:beth"), &dsc.Object{Type: "user", Id: "beth"})
+
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Close()
+
+	// without a key, values cannot be decrypted.
+	store = openStore(t, dbPath, nil)
+	defer store.Close()
+
+	_ = store.DB().View(func(tx *bolt.Tx) error {
+		iter, err := bdb.NewScanIterator[dsc.Object](ctx, tx, bdb.ObjectsPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for iter.Next() {
+			_ = iter.Value()
+		}
+
+		if iter.Err() == nil {
+			t.Error("expected scan iterator error")
+		}
+
+		pager, err := bdb.NewPageIterator[dsc.Object](ctx, tx, bdb.ObjectsPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pager.Next()
+
+		if pager.Err() == nil || len(pager.Value()) != 0 {
+			t.Errorf("expected page iterator error without values, got %d values", len(pager.Value()))
+		}
+
+		return nil
+	})
+}
+
+func openStore(t *testing.T, dbPath string, enc *bdb.EncryptionConfig) *bdb.BoltDB {
+	t.Helper()
+
+	logger := zerolog.Nop()
+
+	store, err := bdb.New(&bdb.Config{DBPath: dbPath, Encryption: enc}, &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func assertSealed(t *testing.T, store *bdb.BoltDB, sealed bool) {
+	t.Helper()
+
+	_ = store.DB().View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(bdb.ObjectsPath[0])).ForEach(func(k, v []byte) error {
+			if bdb.IsSealed(v) != sealed {
+				t.Fatalf("key %s: expected sealed=%v", k, sealed)
+			}
+
+			return nil
+		})
+	})
+}
+
+func assertReadable(t *testing.T, store *bdb.BoltDB) {
+	t.Helper()
+
+	if err := store.DB().View(func(tx *bolt.Tx) error {
+		objs, err := bdb.Scan[dsc.Object](context.Background(), tx, bdb.ObjectsPath, []byte("user:"))
+		if err != nil {
+			return err
+		}
+
+		if len(objs) != objCount {
+			t.Errorf("expected %d objects, got %d", objCount, len(objs))
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := bdb.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
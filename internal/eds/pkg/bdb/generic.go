@@ -64,10 +64,16 @@ func List[T any, M Message[T]](ctx context.Context, tx *bolt.Tx, path Path) ([]M
 		return result, err
 	}
 
+	vc := cipherFor(tx, path)
 	c := b.Cursor()
 
 	for key, value := c.First(); key != nil; key, value = c.Next() {
-		i, err := unmarshal[T, M](value)
+		buf, err := vc.Open(value)
+		if err != nil {
+			return []M{}, err
+		}
+
+		i, err := unmarshal[T, M](buf)
 		if err != nil {
 			return []M{}, err
 		}
@@ -0,0 +1,90 @@
+package bdb
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	txView   = "view"
+	txUpdate = "update"
+	txBatch  = "batch"
+)
+
+var (
+	txDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "topaz_bolt_transaction_duration_seconds",
+			Help:    "Duration of the BoltDB transactions, by type (view, update, batch) and result (success, error).",
+			Buckets: metrics.LatencyBuckets,
+		},
+		[]string{"type", "result"},
+	)
+
+	fileSize = prometheus.NewDesc(
+		"topaz_bolt_file_size_bytes",
+		"Size of the BoltDB database file, by path.",
+		[]string{"path"}, nil,
+	)
+
+	// stores, the open stores, which file size is reported.
+	stores sync.Map
+)
+
+// Collectors, returns the BoltDB metrics collectors.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{txDuration, &fileSizeCollector{}}
+}
+
+// View, executes the function in a read-only transaction, recording the duration of the transaction.
+func (s *BoltDB) View(fn func(*bolt.Tx) error) error {
+	return observeTx(txView, func() error { return s.db.View(fn) })
+}
+
+// Update, executes the function in a read-write transaction, recording the duration of the transaction.
+func (s *BoltDB) Update(fn func(*bolt.Tx) error) error {
+	return observeTx(txUpdate, func() error { return s.db.Update(fn) })
+}
+
+// Batch, executes the function as part of a batch, recording the duration of the transaction.
+func (s *BoltDB) Batch(fn func(*bolt.Tx) error) error {
+	return observeTx(txBatch, func() error { return s.db.Batch(fn) })
+}
+
+func observeTx(txType string, tx func() error) error {
+	start := time.Now()
+
+	err := tx()
+
+	txDuration.WithLabelValues(txType, metrics.Result(err)).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// fileSizeCollector, reports the file size of the open stores.
+type fileSizeCollector struct{}
+
+func (*fileSizeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fileSize
+}
+
+func (*fileSizeCollector) Collect(ch chan<- prometheus.Metric) {
+	stores.Range(func(key, _ any) bool {
+		dbPath, _ := key.(string)
+
+		fi, err := os.Stat(dbPath)
+		if err != nil {
+			return true
+		}
+
+		ch <- prometheus.MustNewConstMetric(fileSize, prometheus.GaugeValue, float64(fi.Size()), dbPath)
+
+		return true
+	})
+}
@@ -181,6 +181,9 @@ func create(config *bdb.Config, log *zerolog.Logger, version *semver.Version) er
 	return nil
 }
 
+// migrate, migrates the store to the next version, the migration steps operate on plaintext values,
+// an encrypted store is decrypted for the migration, and encrypted again after it, with a new data key,
+// the backup of the store included, also when the migration fails.
 func migrate(config *bdb.Config, log *zerolog.Logger, curVersion, nextVersion *semver.Version) error {
 	rwDB, err := common.OpenDB(config)
 	if err != nil {
@@ -197,6 +200,64 @@ func migrate(config *bdb.Config, log *zerolog.Logger, curVersion, nextVersion *s
 		rwDB = nil
 	}()
 
+	kek, err := storeKey(config, rwDB)
+	if err != nil {
+		return err
+	}
+
+	if kek == nil {
+		return migrateStep(config, log, rwDB, curVersion, nextVersion)
+	}
+
+	if err := bdb.Decrypt(rwDB, kek); err != nil {
+		return err
+	}
+
+	migErr := migrateStep(config, log, rwDB, curVersion, nextVersion)
+
+	if err := bdb.Rekey(rwDB, nil, kek, false); err != nil {
+		return err
+	}
+
+	if err := encryptBackup(config, curVersion, kek); err != nil {
+		return err
+	}
+
+	return migErr
+}
+
+// storeKey, returns the key encryption key of an encrypted store, nil when the store is not encrypted.
+func storeKey(config *bdb.Config, db *bolt.DB) ([]byte, error) {
+	encrypted, err := bdb.IsEncrypted(db)
+	if err != nil || !encrypted {
+		return nil, err
+	}
+
+	if !config.Encryption.Enabled() {
+		return nil, bdb.ErrEncryptionKeyMissing
+	}
+
+	return config.Encryption.Key()
+}
+
+// encryptBackup, encrypts the backup of the decrypted store, when it was written.
+func encryptBackup(config *bdb.Config, version *semver.Version, kek []byte) error {
+	path := common.BackupFilename(config.DBPath, version)
+	if !fs.FileExists(path) {
+		return nil
+	}
+
+	db, err := bolt.Open(path, fs.FileModeOwnerRW, &bolt.Options{Timeout: config.RequestTimeout})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return bdb.Rekey(db, nil, kek, false)
+}
+
+func migrateStep(config *bdb.Config, log *zerolog.Logger, rwDB *bolt.DB, curVersion, nextVersion *semver.Version) error {
 	if err := common.Backup(rwDB, curVersion); err != nil {
 		return err
 	}
@@ -0,0 +1,100 @@
+package migrate_test
+
+import (
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb/migrations/common"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb/migrations/migrate"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TestMigrateEncrypted validates the schema migration of an encrypted store, the migrated values,
+// and the backup of the store, are encrypted.
+func TestMigrateEncrypted(t *testing.T) {
+	logger := zerolog.Nop()
+	dbPath := filepath.Join(t.TempDir(), "eds.db")
+
+	kek, err := bdb.NewKey()
+	require.NoError(t, err)
+	t.Setenv("TEST_KEK", base64.StdEncoding.EncodeToString(kek))
+
+	cfg := &bdb.Config{
+		DBPath:         dbPath,
+		RequestTimeout: time.Second,
+		Encryption:     &bdb.EncryptionConfig{KeyEnv: "TEST_KEK"},
+	}
+
+	curVersion := semver.MustParse("0.0.9")
+
+	// create a plaintext store at the previous version, with a value migrated by the next version.
+	ok, err := migrate.CheckSchemaVersion(&bdb.Config{DBPath: dbPath, RequestTimeout: time.Second}, &logger, curVersion)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	obj, err := proto.Marshal(&dsc.Object{Type: "user", Id: "beth", DisplayName: "Beth", Properties: &structpb.Struct{}}) //nolint:staticcheck // migrated field.
+	require.NoError(t, err)
+
+	db, err := bolt.Open(dbPath, 0o600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(bdb.ObjectsPath[0])).Put([]byte("user:beth"), obj)
+	}))
+	require.NoError(t, bdb.Rekey(db, nil, kek, false))
+	require.NoError(t, db.Close())
+
+	require.NoError(t, migrate.Migrate(cfg, &logger, semver.MustParse("0.0.10")))
+
+	ok, err = migrate.CheckSchemaVersion(cfg, &logger, semver.MustParse("0.0.10"))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	store, err := bdb.New(cfg, &logger)
+	require.NoError(t, err)
+	require.NoError(t, store.Open())
+
+	t.Cleanup(store.Close)
+
+	assertSealed(t, store.DB())
+
+	require.NoError(t, store.DB().View(func(tx *bolt.Tx) error {
+		objs, err := bdb.Scan[dsc.Object](context.Background(), tx, bdb.ObjectsPath, []byte("user:"))
+		require.NoError(t, err)
+		require.Len(t, objs, 1)
+		assert.Equal(t, "Beth", objs[0].GetProperties().GetFields()["display_name"].GetStringValue())
+
+		return nil
+	}))
+
+	backup, err := bolt.Open(common.BackupFilename(dbPath, curVersion), 0o600, &bolt.Options{ReadOnly: true})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = backup.Close() })
+
+	encrypted, err := bdb.IsEncrypted(backup)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	assertSealed(t, backup)
+}
+
+func assertSealed(t *testing.T, db *bolt.DB) {
+	t.Helper()
+
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(bdb.ObjectsPath[0])).ForEach(func(k, v []byte) error {
+			assert.True(t, bdb.IsSealed(v), "key %s not sealed", k)
+			return nil
+		})
+	}))
+}
@@ -9,6 +9,7 @@ const (
 
 var (
 	SystemPath        Path = []string{"_system"}
+	CountersPath      Path = []string{"_system", "counters"}                        // object and relation counters path
 	ManifestPath      Path = ManifestPathV2                                         // current path
 	ManifestPathV1    Path = []string{"_manifest", manifestName, manifestVersionV1} // migration path V1, OBSOLETE per migration 0.0.8
 	ManifestPathV2    Path = []string{"_manifest", manifestName}                    // migration path V2
@@ -15,20 +15,23 @@ import (
 type Iterator[T any, M Message[T]] interface {
 	Next() bool       // move cursor to next element.
 	RawKey() []byte   // return raw key ([]byte).
-	RawValue() []byte // return raw value ([]byte).
+	RawValue() []byte // return raw value ([]byte), encrypted when the store is encrypted.
 	Key() string      // return key (string).
-	Value() M         // return typed value (M).
+	Value() M         // return typed value (M), the zero value when the value cannot be decoded.
 	Delete() error    // delete element underneath cursor.
+	Err() error       // return the error which stopped the iteration.
 }
 
 type ScanIterator[T any, M Message[T]] struct {
-	ctx   context.Context
-	tx    *bolt.Tx
-	c     *bolt.Cursor
-	args  *ScanArgs
-	init  bool
-	key   []byte
-	value []byte
+	ctx    context.Context
+	tx     *bolt.Tx
+	c      *bolt.Cursor
+	cipher *Cipher
+	args   *ScanArgs
+	init   bool
+	key    []byte
+	value  []byte
+	err    error
 }
 
 type ScanOption func(*ScanArgs)
@@ -72,10 +75,14 @@ func NewScanIterator[T any, M Message[T]](ctx context.Context, tx *bolt.Tx, path
 		return nil, errors.Wrapf(ErrPathNotFound, "path [%s]", path)
 	}
 
-	return &ScanIterator[T, M]{ctx: ctx, tx: tx, c: b.Cursor(), args: args, init: false}, nil
+	return &ScanIterator[T, M]{ctx: ctx, tx: tx, c: b.Cursor(), cipher: cipherFor(tx, path), args: args, init: false}, nil
 }
 
 func (s *ScanIterator[T, M]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
 	if s.init {
 		s.key, s.value = s.c.Next()
 	}
@@ -105,16 +112,30 @@ func (s *ScanIterator[T, M]) Key() string {
 	return string(s.key)
 }
 
+// Value, returns the decoded value underneath the cursor, a value which cannot be decrypted or
+// unmarshaled stops the iteration, the error is returned by Err.
 func (s *ScanIterator[T, M]) Value() M {
-	msg, err := unmarshal[T, M](s.value)
+	var result M
+
+	buf, err := s.cipher.Open(s.value)
 	if err != nil {
-		var result M
+		s.err = errors.Wrapf(err, "key [%s]", s.key)
+		return result
+	}
+
+	msg, err := unmarshal[T, M](buf)
+	if err != nil {
+		s.err = errors.Wrapf(err, "key [%s]", s.key)
 		return result
 	}
 
 	return msg
 }
 
+func (s *ScanIterator[T, M]) Err() error {
+	return s.err
+}
+
 func (s *ScanIterator[T, M]) Delete() error {
 	if s.key != nil {
 		log.Trace().Str("key", s.Key()).Msg("delete")
@@ -128,6 +149,7 @@ type PagedIterator[T any, M Message[T]] interface {
 	Next() bool
 	Value() []M
 	NextToken() string
+	Err() error
 }
 
 type PageIterator[T any, M Message[T]] struct {
@@ -148,7 +170,12 @@ func NewPageIterator[T any, M Message[T]](ctx context.Context, tx *bolt.Tx, path
 func (p *PageIterator[T, M]) Next() bool {
 	results := []M{}
 	for p.iter.Next() {
-		results = append(results, p.iter.Value())
+		value := p.iter.Value()
+		if p.iter.Err() != nil {
+			break
+		}
+
+		results = append(results, value)
 
 		if len(results) == int(p.iter.args.pageSize) {
 			break
@@ -173,18 +200,28 @@ func (p *PageIterator[T, M]) NextToken() string {
 	return string(p.nextToken)
 }
 
+func (p *PageIterator[T, M]) Err() error {
+	return p.iter.Err()
+}
+
 func Scan[T any, M Message[T]](ctx context.Context, tx *bolt.Tx, path Path, keyFilter []byte) ([]M, error) {
 	b, err := SetBucket(tx, path)
 	if err != nil {
 		return nil, errors.Wrapf(ErrPathNotFound, "path [%s]", path)
 	}
 
+	vc := cipherFor(tx, path)
 	c := b.Cursor()
 
 	var results []M
 
 	for k, v := c.Seek(keyFilter); k != nil && bytes.HasPrefix(k, keyFilter); k, v = c.Next() {
-		m, err := unmarshal[T, M](v)
+		buf, err := vc.Open(v)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := unmarshal[T, M](buf)
 		if err != nil {
 			return nil, err
 		}
@@ -209,6 +246,7 @@ func ScanWithFilter(
 		return errors.Wrapf(ErrPathNotFound, "path [%s]", path)
 	}
 
+	vc := cipherFor(tx, path)
 	c := b.Cursor()
 
 	if valueFilter == nil {
@@ -218,8 +256,13 @@ func ScanWithFilter(
 	results := *out
 
 	for k, v := c.Seek(keyFilter); k != nil && bytes.HasPrefix(k, keyFilter); k, v = c.Next() {
+		buf, err := vc.Open(v)
+		if err != nil {
+			return err
+		}
+
 		m := pool.Get()
-		if err := unmarshalTo(v, m); err != nil {
+		if err := unmarshalTo(buf, m); err != nil {
 			return err
 		}
 
@@ -6,9 +6,11 @@ import (
 
 	dse "github.com/aserto-dev/go-directory/aserto/directory/exporter/v3"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 
 	cuckoo "github.com/panmari/cuckoofilter"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -80,6 +82,16 @@ func newSync(c *Client, o *Options) *Sync {
 func (s *Sync) Run(ctx context.Context, conn *grpc.ClientConn) error {
 	s.logger.Info().Str("mode", s.options.Mode.String()).Msg(syncRun)
 
+	ctx, span := telemetry.Start(ctx, "datasync.run", attribute.String("sync.mode", s.options.Mode.String()))
+
+	err := s.run(ctx, conn)
+
+	telemetry.End(span, err)
+
+	return err
+}
+
+func (s *Sync) run(ctx context.Context, conn *grpc.ClientConn) error {
 	if Has(s.options.Mode, Manifest) {
 		if err := s.syncManifest(ctx, conn); err != nil {
 			return err
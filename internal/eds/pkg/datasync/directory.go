@@ -166,7 +166,7 @@ func (s *Sync) subscriber(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	batchErr := s.store.DB().Batch(func(tx *bolt.Tx) error {
+	batchErr := s.store.Batch(func(tx *bolt.Tx) error {
 		for {
 			msg, ok := <-s.exportChan
 			if !ok {
@@ -238,7 +238,7 @@ func (s *Sync) diff(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	batchErr := s.store.DB().Batch(func(tx *bolt.Tx) error {
+	batchErr := s.store.Batch(func(tx *bolt.Tx) error {
 		// objects
 		{
 			iter, err := bdb.NewScanIterator[dsc.Object](ctx, tx, bdb.ObjectsPath)
@@ -248,6 +248,9 @@ func (s *Sync) diff(ctx context.Context) error {
 
 			for iter.Next() {
 				obj := iter.Value()
+				if iter.Err() != nil {
+					return iter.Err()
+				}
 
 				if !s.filter.Lookup(getObjectKey(obj)) {
 					s.logger.Trace().Str("key", string(getObjectKey(obj))).Msg("delete")
@@ -274,6 +277,9 @@ func (s *Sync) diff(ctx context.Context) error {
 
 			for iter.Next() {
 				rel := iter.Value()
+				if iter.Err() != nil {
+					return iter.Err()
+				}
 
 				if !s.filter.Lookup(getRelationKey(rel)) {
 					s.logger.Trace().Str("key", string(getRelationKey(rel))).Msg("delete")
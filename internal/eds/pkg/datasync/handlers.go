@@ -43,7 +43,7 @@ func (s *Sync) objectSetHandler(ctx context.Context, tx *bolt.Tx, req *dsc.Objec
 
 	updReq.Etag = etag
 
-	if _, err := bdb.Set[dsc.Object](ctx, tx, bdb.ObjectsPath, ds.Object(updReq).Key(), updReq); err != nil {
+	if _, err := ds.SetObject(ctx, tx, updReq); err != nil {
 		return derr.ErrInvalidObject.Msg("set")
 	}
 
@@ -66,7 +66,7 @@ func (s *Sync) objectDeleteHandler(ctx context.Context, tx *bolt.Tx, req *dsc.Ob
 		return err
 	}
 
-	if err := bdb.Delete(ctx, tx, bdb.ObjectsPath, obj.Key()); err != nil {
+	if err := ds.DeleteObject(ctx, tx, &dsc.ObjectIdentifier{ObjectType: req.GetType(), ObjectId: req.GetId()}); err != nil {
 		return derr.ErrInvalidObject.Msg("delete")
 	}
 
@@ -103,11 +103,7 @@ func (s *Sync) relationSetHandler(ctx context.Context, tx *bolt.Tx, req *dsc.Rel
 
 	updReq.Etag = etag
 
-	if _, err := bdb.Set[dsc.Relation](ctx, tx, bdb.RelationsObjPath, rel.ObjKey(), updReq); err != nil {
-		return derr.ErrInvalidRelation.Msg("set")
-	}
-
-	if _, err := bdb.Set[dsc.Relation](ctx, tx, bdb.RelationsSubPath, rel.SubKey(), updReq); err != nil {
+	if _, err := ds.SetRelation(ctx, tx, updReq); err != nil {
 		return derr.ErrInvalidRelation.Msg("set")
 	}
 
@@ -130,11 +126,7 @@ func (s *Sync) relationDeleteHandler(ctx context.Context, tx *bolt.Tx, req *dsc.
 		return err
 	}
 
-	if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, rel.ObjKey()); err != nil {
-		return derr.ErrInvalidRelation.Msg("delete")
-	}
-
-	if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, rel.SubKey()); err != nil {
+	if err := ds.DeleteRelation(ctx, tx, req); err != nil {
 		return derr.ErrInvalidRelation.Msg("delete")
 	}
 
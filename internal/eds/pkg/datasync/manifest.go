@@ -43,7 +43,7 @@ func (s *Sync) syncManifest(ctx context.Context, conn *grpc.ClientConn) error {
 			localReader io.Reader
 		)
 
-		err := s.store.DB().View(func(tx *bolt.Tx) error {
+		err := s.store.View(func(tx *bolt.Tx) error {
 			md := &dsm.Metadata{UpdatedAt: timestamppb.Now(), Etag: ""}
 			manifest, err := ds.Manifest(md).Get(ctx, tx)
 
@@ -110,7 +110,7 @@ func (s *Sync) setManifest(ctx context.Context, remoteBuf []byte) (*model.Model,
 		return nil, derr.ErrInvalidArgument.Msg(err.Error())
 	}
 
-	if err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	if err := s.store.Update(func(tx *bolt.Tx) error {
 		stats, err := ds.CalculateStats(ctx, tx)
 		if err != nil {
 			return derr.ErrUnknown.Msgf("failed to calculate stats: %s", err.Error())
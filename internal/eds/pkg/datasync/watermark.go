@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
 
 	bolt "go.etcd.io/bbolt"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -100,18 +101,13 @@ func (s *Sync) setWatermark(ts *timestamppb.Timestamp) error {
 	wm.Timestamp = newTS
 	wm.LastUpdated = newTS.AsTime().Format(time.RFC3339Nano)
 
-	objStats, err := s.dbStats(bdb.ObjectsPath)
+	objCount, relCount, err := s.dbCounts()
 	if err != nil {
 		return err
 	}
 
-	relStats, err := s.dbStats(bdb.RelationsObjPath)
-	if err != nil {
-		return err
-	}
-
-	wm.ObjectCount = uint(objStats.KeyN)
-	wm.RelationCount = uint(relStats.KeyN)
+	wm.ObjectCount = objCount
+	wm.RelationCount = relCount
 
 	wm.TotalCount = wm.ObjectCount + wm.RelationCount
 
@@ -135,15 +131,22 @@ func (s *Sync) syncFilename() string {
 	return filepath.Join(dir, fmt.Sprintf("%s.%s", file, "sync"))
 }
 
-func (s *Sync) dbStats(path bdb.Path) (bolt.BucketStats, error) {
-	var stats bolt.BucketStats
+// dbCounts, returns the object and relation counts, using the counters when maintained by the store,
+// otherwise using the bucket stats.
+func (s *Sync) dbCounts() (objCount, relCount uint, err error) {
+	err = s.store.View(func(tx *bolt.Tx) error {
+		if objects, relations, ok := ds.CountTotals(tx); ok {
+			objCount, relCount = uint(objects), uint(relations) //nolint:gosec // counters are never negative.
+			return nil
+		}
+
+		objCount = uint(bucketStats(tx, bdb.ObjectsPath).KeyN)
+		relCount = uint(bucketStats(tx, bdb.RelationsObjPath).KeyN)
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
-		stats = bucketStats(tx, path)
 		return nil
 	})
 
-	return stats, err
+	return objCount, relCount, err
 }
 
 func bucketStats(tx *bolt.Tx, path bdb.Path) bolt.BucketStats {
@@ -17,9 +17,12 @@ import (
 	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb/migrations/migrate"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/datasync"
 	v3 "github.com/aserto-dev/topaz/internal/eds/pkg/directory/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/rs/zerolog"
+	bolt "go.etcd.io/bbolt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -31,10 +34,13 @@ const (
 )
 
 type Config struct {
-	DBPath         string        `json:"db_path"`
-	RequestTimeout time.Duration `json:"request_timeout"`
-	Seed           bool          `json:"seed_metadata"`
-	EnableV2       bool          `json:"enable_v2"`
+	DBPath         string                `json:"db_path"`
+	RequestTimeout time.Duration         `json:"request_timeout"`
+	Seed           bool                  `json:"seed_metadata"`
+	EnableV2       bool                  `json:"enable_v2"`
+	Encryption     *bdb.EncryptionConfig `json:"encryption,omitempty"`
+	// ReportConcurrency, maximum number of concurrent graph searches per access report.
+	ReportConcurrency int `json:"report_concurrency,omitempty"`
 }
 
 type Directory struct {
@@ -47,6 +53,7 @@ type Directory struct {
 	reader3   dsr.ReaderServer
 	writer3   dsw.WriterServer
 	access1   dsa.AccessServer
+	report1   report.ReportServer
 }
 
 var (
@@ -72,12 +79,14 @@ func New(ctx context.Context, config *Config, logger *zerolog.Logger) (*Director
 	return directory, err
 }
 
-func newDirectory(_ context.Context, config *Config, logger *zerolog.Logger) (*Directory, error) {
+func newDirectory(ctx context.Context, config *Config, logger *zerolog.Logger) (*Directory, error) {
 	newLogger := logger.With().Str("component", "directory").Logger()
 
+	// the migrations decrypt an encrypted store, and encrypt it again after the migration.
 	cfg := bdb.Config{
 		DBPath:         config.DBPath,
 		RequestTimeout: config.RequestTimeout,
+		Encryption:     config.Encryption,
 	}
 
 	if ok, err := migrate.CheckSchemaVersion(&cfg, logger, semver.MustParse(schemaVersion)); !ok {
@@ -97,9 +106,11 @@ func newDirectory(_ context.Context, config *Config, logger *zerolog.Logger) (*D
 		}
 	}
 
+	// when encryption is enabled, opening the store encrypts an existing plaintext store in place.
 	store, err := bdb.New(&bdb.Config{
 		DBPath:         config.DBPath,
 		RequestTimeout: config.RequestTimeout,
+		Encryption:     config.Encryption,
 	},
 		&newLogger,
 	)
@@ -117,6 +128,7 @@ func newDirectory(_ context.Context, config *Config, logger *zerolog.Logger) (*D
 	importer3 := v3.NewImporter(logger, store)
 
 	access1 := v3.NewAccess(logger, reader3)
+	report1 := v3.NewReport(logger, reader3, config.ReportConcurrency)
 
 	dir := &Directory{
 		config:    config,
@@ -128,12 +140,25 @@ func newDirectory(_ context.Context, config *Config, logger *zerolog.Logger) (*D
 		exporter3: exporter3,
 		importer3: importer3,
 		access1:   access1,
+		report1:   report1,
 	}
 
 	if err := store.LoadModel(); err != nil {
 		return nil, err
 	}
 
+	// one time computation of the object and relation counters, when not maintained by the store yet.
+	if err := store.Update(func(tx *bolt.Tx) error {
+		computed, err := ds.InitCounters(ctx, tx)
+		if computed {
+			newLogger.Info().Msg("initialized object and relation counters")
+		}
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
 	return dir, nil
 }
 
@@ -168,6 +193,10 @@ func (s *Directory) Access1() dsa.AccessServer {
 	return s.access1
 }
 
+func (s *Directory) Report1() report.ReportServer {
+	return s.report1
+}
+
 func (s *Directory) Logger() *zerolog.Logger {
 	return s.logger
 }
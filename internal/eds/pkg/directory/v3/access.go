@@ -1,12 +1,27 @@
 package v3
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/aserto-dev/azm/cache"
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
 	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/x"
 	dsa "github.com/authzen/access.go/api/access/v1"
 	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// ContextIncludeRelations, action search request context option, when set to true,
+	// the assignable relations are returned in addition to the permissions.
+	ContextIncludeRelations string = "include_relations"
+	// ContextExplain, evaluation request context (or evaluations options) option, when set to true,
+	// the relation paths explaining the decision are returned in the response context.
+	ContextExplain string = "explain"
 )
 
 type Access struct {
@@ -55,6 +70,8 @@ func extractCheck(req *dsa.EvaluationRequest) *dsr.CheckRequest {
 		checkReq.SubjectId = sub.GetId()
 	}
 
+	checkReq.Trace = contextBool(req.GetContext(), ContextExplain)
+
 	return checkReq
 }
 
@@ -93,6 +110,8 @@ func extractChecks(req *dsa.EvaluationsRequest) (*dsr.CheckRequest, []*dsr.Check
 		check.ObjectId = res.GetId()
 	}
 
+	check.Trace = contextBool(req.GetOptions(), ContextExplain) || contextBool(req.GetContext(), ContextExplain)
+
 	checks := make([]*dsr.CheckRequest, len(req.GetEvaluations()))
 
 	for k, v := range req.GetEvaluations() {
@@ -137,7 +156,9 @@ func (s *Access) SubjectSearch(ctx context.Context, req *dsa.SubjectSearchReques
 		return resp, err
 	}
 
-	for _, oid := range graphResp.GetResults() {
+	results, page := paginate(graphResp.GetResults(), objectKey, req.GetPage())
+
+	for _, oid := range results {
 		sub := &dsa.Subject{
 			Type: oid.GetObjectType(),
 			Id:   oid.GetObjectId(),
@@ -145,6 +166,8 @@ func (s *Access) SubjectSearch(ctx context.Context, req *dsa.SubjectSearchReques
 		resp.Results = append(resp.GetResults(), sub)
 	}
 
+	resp.Page = page
+
 	return resp, nil
 }
 
@@ -186,7 +209,9 @@ func (s *Access) ResourceSearch(ctx context.Context, req *dsa.ResourceSearchRequ
 		return resp, err
 	}
 
-	for _, oid := range graphResp.GetResults() {
+	results, page := paginate(graphResp.GetResults(), objectKey, req.GetPage())
+
+	for _, oid := range results {
 		res := &dsa.Resource{
 			Type: oid.GetObjectType(),
 			Id:   oid.GetObjectId(),
@@ -195,6 +220,8 @@ func (s *Access) ResourceSearch(ctx context.Context, req *dsa.ResourceSearchRequ
 		resp.Results = append(resp.GetResults(), res)
 	}
 
+	resp.Page = page
+
 	return resp, nil
 }
 
@@ -232,7 +259,7 @@ func (s *Access) ActionSearch(ctx context.Context, req *dsa.ActionSearchRequest)
 	}
 
 	graphReq := extractActionSearch(req)
-	inclRelations := false
+	inclRelations := contextBool(req.GetContext(), ContextIncludeRelations)
 
 	assignable := []cache.RelationName{}
 
@@ -276,15 +303,33 @@ func (s *Access) ActionSearch(ctx context.Context, req *dsa.ActionSearchRequest)
 		return resp, err
 	}
 
+	actions := []string{}
+
 	for i, chk := range checksResp.GetChecks() {
 		if chk.GetCheck() {
-			resp.Results = append(resp.GetResults(), &dsa.Action{Name: assignable[i].String()})
+			actions = append(actions, assignable[i].String())
 		}
 	}
 
+	results, page := paginate(actions, func(name string) string { return name }, req.GetPage())
+
+	for _, name := range results {
+		resp.Results = append(resp.GetResults(), &dsa.Action{Name: name})
+	}
+
+	resp.Page = page
+
 	return resp, nil
 }
 
+func contextBool(reqCtx *structpb.Struct, key string) bool {
+	if v, ok := reqCtx.GetFields()[key]; ok {
+		return v.GetBoolValue()
+	}
+
+	return false
+}
+
 func extractActionSearch(req *dsa.ActionSearchRequest) *dsr.GetGraphRequest {
 	resp := &dsr.GetGraphRequest{}
 	if res := req.GetResource(); res != nil {
@@ -302,3 +347,73 @@ func extractActionSearch(req *dsa.ActionSearchRequest) *dsr.GetGraphRequest {
 
 	return resp
 }
+
+// paginate, returns the page of results starting at the page token, in the order of their keys.
+//
+// The search APIs evaluate the graph as a whole, so each page re-runs the search, and the page is
+// selected from the complete results: the results before the page token are skipped, and only the
+// results of the page are sorted. The page token is the key of the first result of the page, it is
+// a position in the key order, not a snapshot, results added or removed in between requests before
+// the token are not returned, after the token they are. The page size is capped at x.MaxPageSize,
+// when no page is requested, all results are returned.
+func paginate[T any](results []T, key func(T) string, page *dsa.PaginationRequest) ([]T, *dsa.PaginationResponse) {
+	total := int64(len(results))
+
+	if page == nil {
+		slices.SortFunc(results, func(a, b T) int {
+			return cmp.Compare(key(a), key(b))
+		})
+
+		return results, &dsa.PaginationResponse{Count: &total, Total: &total}
+	}
+
+	limit := int(page.GetLimit())
+	if limit <= 0 || limit > int(x.MaxPageSize) {
+		limit = int(x.MaxPageSize)
+	}
+
+	// the results from the page token onward, the next page token is the key of the result following the page.
+	remaining := make([]T, 0, min(len(results), limit+1))
+	for _, r := range results {
+		if key(r) < page.GetToken() {
+			continue
+		}
+
+		remaining = insertSorted(remaining, r, key, limit+1)
+	}
+
+	resp := &dsa.PaginationResponse{Total: &total}
+
+	if len(remaining) > limit {
+		resp.NextToken = key(remaining[limit])
+		remaining = remaining[:limit]
+	}
+
+	count := int64(len(remaining))
+	resp.Count = &count
+
+	return remaining, resp
+}
+
+// insertSorted, inserts r into the sorted results, keeping at most size results with the lowest keys.
+func insertSorted[T any](results []T, r T, key func(T) string, size int) []T {
+	k := key(r)
+
+	i, _ := slices.BinarySearchFunc(results, k, func(e T, k string) int {
+		return strings.Compare(key(e), k)
+	})
+
+	if i >= size {
+		return results
+	}
+
+	if len(results) == size {
+		results = results[:size-1]
+	}
+
+	return slices.Insert(results, i, r)
+}
+
+func objectKey(oid *dsc.ObjectIdentifier) string {
+	return oid.GetObjectType() + ":" + oid.GetObjectId()
+}
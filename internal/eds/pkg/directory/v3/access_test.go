@@ -0,0 +1,93 @@
+package v3 //nolint:testpackage // paginate is unexported.
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/x"
+	dsa "github.com/authzen/access.go/api/access/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestPaginate validates walking all pages using the next token returns every result exactly once, in order.
+func TestPaginate(t *testing.T) {
+	const count = 250
+
+	results := make([]*dsc.ObjectIdentifier, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		results = append(results, &dsc.ObjectIdentifier{ObjectType: "user", ObjectId: fmt.Sprintf("user-%03d", i)})
+	}
+
+	seen := []string{}
+	page := &dsa.PaginationRequest{Limit: proto.Int32(100)}
+
+	for {
+		values, resp := paginate(results, objectKey, page)
+
+		if resp.GetTotal() != count {
+			t.Fatalf("expected total %d, got %d", count, resp.GetTotal())
+		}
+
+		if resp.GetCount() != int64(len(values)) {
+			t.Fatalf("expected count %d, got %d", len(values), resp.GetCount())
+		}
+
+		for _, v := range values {
+			seen = append(seen, v.GetObjectId())
+		}
+
+		if resp.GetNextToken() == "" {
+			break
+		}
+
+		page.Token = proto.String(resp.GetNextToken())
+	}
+
+	if len(seen) != count {
+		t.Fatalf("expected %d results, got %d", count, len(seen))
+	}
+
+	for i, id := range seen {
+		if expected := fmt.Sprintf("user-%03d", i); id != expected {
+			t.Fatalf("expected %s at %d, got %s", expected, i, id)
+		}
+	}
+}
+
+// TestPaginateNoPage validates all results are returned when no page is requested.
+func TestPaginateNoPage(t *testing.T) {
+	results := []string{"can_write", "can_read", "can_delete"}
+
+	values, resp := paginate(results, func(s string) string { return s }, nil)
+	if len(values) != len(results) || resp.GetNextToken() != "" {
+		t.Fatalf("expected all results, got %v next %q", values, resp.GetNextToken())
+	}
+
+	if values[0] != "can_delete" {
+		t.Fatalf("expected sorted results, got %v", values)
+	}
+}
+
+// TestPaginateLimit validates the page size is capped, and the token is a position in the key order.
+func TestPaginateLimit(t *testing.T) {
+	results := make([]string, 0, 2*x.MaxPageSize)
+	for i := range 2 * x.MaxPageSize {
+		results = append(results, fmt.Sprintf("rel-%03d", 2*x.MaxPageSize-1-i))
+	}
+
+	values, resp := paginate(results, func(s string) string { return s }, &dsa.PaginationRequest{Limit: proto.Int32(1000)})
+	if len(values) != int(x.MaxPageSize) || values[0] != "rel-000" || resp.GetNextToken() != fmt.Sprintf("rel-%03d", x.MaxPageSize) {
+		t.Fatalf("expected a page of %d results, got %d next %q", x.MaxPageSize, len(values), resp.GetNextToken())
+	}
+
+	// a token which is not a result key starts the page at the next key.
+	values, resp = paginate(results, func(s string) string { return s }, &dsa.PaginationRequest{
+		Limit: proto.Int32(2),
+		Token: proto.String("rel-0105"),
+	})
+	if !slices.Equal(values, []string{"rel-011", "rel-012"}) || resp.GetNextToken() != "rel-013" {
+		t.Fatalf("expected [rel-011 rel-012] next rel-013, got %v next %q", values, resp.GetNextToken())
+	}
+}
@@ -30,7 +30,7 @@ func NewExporter(logger *zerolog.Logger, store *bdb.BoltDB) *Exporter {
 func (s *Exporter) Export(req *dse.ExportRequest, stream dse.Exporter_ExportServer) error {
 	logger := s.logger.With().Str("method", "Export").Interface("req", req).Logger()
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		// stats mode, short circuits when enabled
 		if req.GetOptions()&uint32(dse.Option_OPTION_STATS) != 0 {
 			if err := exportStats(tx, stream, req.GetOptions()); err != nil {
@@ -68,13 +68,18 @@ func exportObjects(tx *bolt.Tx, stream dse.Exporter_ExportServer) error {
 	}
 
 	for iter.Next() {
-		obj := ds.PatchObjectRead(iter.Value())
+		obj := iter.Value()
+		if iter.Err() != nil {
+			break
+		}
+
+		obj = ds.PatchObjectRead(obj)
 		if err := stream.Send(&dse.ExportResponse{Msg: &dse.ExportResponse_Object{Object: obj}}); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return iter.Err()
 }
 
 func exportRelations(tx *bolt.Tx, stream dse.Exporter_ExportServer) error {
@@ -84,12 +89,17 @@ func exportRelations(tx *bolt.Tx, stream dse.Exporter_ExportServer) error {
 	}
 
 	for iter.Next() {
-		if err := stream.Send(&dse.ExportResponse{Msg: &dse.ExportResponse_Relation{Relation: iter.Value()}}); err != nil {
+		rel := iter.Value()
+		if iter.Err() != nil {
+			break
+		}
+
+		if err := stream.Send(&dse.ExportResponse{Msg: &dse.ExportResponse_Relation{Relation: rel}}); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return iter.Err()
 }
 
 func exportStats(tx *bolt.Tx, stream dse.Exporter_ExportServer, opts uint32) error {
@@ -48,7 +48,7 @@ func (s *Importer) Import(stream dsi.Importer_ImportServer) error {
 		relation: {Type: relation},
 	}
 
-	importErr := s.store.DB().Batch(func(tx *bolt.Tx) error {
+	importErr := s.store.Batch(func(tx *bolt.Tx) error {
 		for {
 			select {
 			case <-ctx.Done(): // exit if context is done
@@ -177,7 +177,7 @@ func (s *Importer) objectSetHandler(ctx context.Context, tx *bolt.Tx, req *dsc.O
 
 	updReq.Etag = etag
 
-	if _, err := bdb.Set[dsc.Object](ctx, tx, bdb.ObjectsPath, ds.Object(updReq).Key(), updReq); err != nil {
+	if _, err := ds.SetObject(ctx, tx, updReq); err != nil {
 		return derr.ErrInvalidObject.Msg("set")
 	}
 
@@ -200,7 +200,7 @@ func (s *Importer) objectDeleteHandler(ctx context.Context, tx *bolt.Tx, req *ds
 		return modelValidateError(err)
 	}
 
-	if err := bdb.Delete(ctx, tx, bdb.ObjectsPath, obj.Key()); err != nil {
+	if err := ds.DeleteObject(ctx, tx, &dsc.ObjectIdentifier{ObjectType: req.GetType(), ObjectId: req.GetId()}); err != nil {
 		return derr.ErrInvalidObject.Msg("delete")
 	}
 
@@ -223,7 +223,7 @@ func (s *Importer) objectDeleteWithRelationsHandler(ctx context.Context, tx *bol
 		return modelValidateError(err)
 	}
 
-	if err := bdb.Delete(ctx, tx, bdb.ObjectsPath, obj.Key()); err != nil {
+	if err := ds.DeleteObject(ctx, tx, &dsc.ObjectIdentifier{ObjectType: req.GetType(), ObjectId: req.GetId()}); err != nil {
 		return derr.ErrInvalidObject.Msg("delete")
 	}
 
@@ -250,17 +250,17 @@ func (*Importer) deleteObjectRelations(ctx context.Context, tx *bolt.Tx, path bd
 	}
 
 	for iter.Next() {
-		rel := ds.Relation(iter.Value())
-		if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, rel.ObjKey()); err != nil {
-			return err
+		rel := iter.Value()
+		if iter.Err() != nil {
+			break
 		}
 
-		if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, rel.SubKey()); err != nil {
+		if err := ds.DeleteRelation(ctx, tx, rel); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return iter.Err()
 }
 
 func (s *Importer) relationSetHandler(ctx context.Context, tx *bolt.Tx, req *dsc.Relation) error {
@@ -293,11 +293,7 @@ func (s *Importer) relationSetHandler(ctx context.Context, tx *bolt.Tx, req *dsc
 
 	updReq.Etag = etag
 
-	if _, err := bdb.Set[dsc.Relation](ctx, tx, bdb.RelationsObjPath, ds.Relation(updReq).ObjKey(), updReq); err != nil {
-		return derr.ErrInvalidRelation.Msg("set")
-	}
-
-	if _, err := bdb.Set[dsc.Relation](ctx, tx, bdb.RelationsSubPath, ds.Relation(updReq).SubKey(), updReq); err != nil {
+	if _, err := ds.SetRelation(ctx, tx, updReq); err != nil {
 		return derr.ErrInvalidRelation.Msg("set")
 	}
 
@@ -320,11 +316,7 @@ func (s *Importer) relationDeleteHandler(ctx context.Context, tx *bolt.Tx, req *
 		return modelValidateError(err)
 	}
 
-	if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, rel.ObjKey()); err != nil {
-		return derr.ErrInvalidRelation.Msg("delete")
-	}
-
-	if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, rel.SubKey()); err != nil {
+	if err := ds.DeleteRelation(ctx, tx, req); err != nil {
 		return derr.ErrInvalidRelation.Msg("delete")
 	}
 
@@ -332,6 +324,8 @@ func (s *Importer) relationDeleteHandler(ctx context.Context, tx *bolt.Tx, req *
 }
 
 func updateCounter(c *dsi.ImportCounter, opCode dsi.Opcode, err error) *dsi.ImportCounter {
+	observeImport(c.GetType(), opCode, err)
+
 	c.Recv++
 
 	switch {
@@ -0,0 +1,48 @@
+package v3
+
+import (
+	"strings"
+
+	dsi "github.com/aserto-dev/go-directory/aserto/directory/importer/v3"
+	"github.com/aserto-dev/topaz/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	opSetObject      = "set_object"
+	opDeleteObject   = "delete_object"
+	opSetRelation    = "set_relation"
+	opDeleteRelation = "delete_relation"
+)
+
+var (
+	writeOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_directory_writes_total",
+			Help: "Number of directory writer operations, by operation (set_object, delete_object, set_relation, delete_relation) and result (success, error).",
+		},
+		[]string{"operation", "result"},
+	)
+
+	importOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_directory_imports_total",
+			Help: "Number of directory import operations, by type (object, relation), op code (set, delete, delete_with_relations) and result (success, error).",
+		},
+		[]string{"type", "op_code", "result"},
+	)
+)
+
+// Collectors, returns the directory writer and importer metrics collectors.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{writeOperations, importOperations}
+}
+
+func observeWrite(operation string, err error) {
+	writeOperations.WithLabelValues(operation, metrics.Result(err)).Inc()
+}
+
+func observeImport(msgType string, opCode dsi.Opcode, err error) {
+	importOperations.WithLabelValues(msgType, strings.ToLower(strings.TrimPrefix(opCode.String(), "OPCODE_")), metrics.Result(err)).Inc()
+}
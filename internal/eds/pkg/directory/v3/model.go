@@ -64,7 +64,7 @@ func (s *Model) GetManifest(req *dsm.GetManifestRequest, stream dsm.Model_GetMan
 
 	md := &dsm.Metadata{UpdatedAt: timestamppb.Now(), Etag: ""}
 
-	modelErr := s.store.DB().View(func(tx *bolt.Tx) error {
+	modelErr := s.store.View(func(tx *bolt.Tx) error {
 		manifest, err := ds.Manifest(md).Get(stream.Context(), tx)
 
 		switch {
@@ -177,7 +177,7 @@ func (s *Model) SetManifest(stream dsm.Model_SetManifestServer) error {
 		return derr.ErrInvalidArgument.Msg(err.Error())
 	}
 
-	if err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	if err := s.store.Update(func(tx *bolt.Tx) error {
 		return s.setManifest(stream, tx, m, md, data)
 	}); err != nil {
 		return err
@@ -210,7 +210,7 @@ func (s *Model) DeleteManifest(ctx context.Context, req *dsm.DeleteManifestReque
 		return resp, derr.ErrInvalidArgument.Msg(err.Error())
 	}
 
-	if err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	if err := s.store.Update(func(tx *bolt.Tx) error {
 		// optimistic concurrency check
 		ifMatchHeader := metautils.ExtractIncoming(ctx).Get(headers.IfMatch)
 		if ifMatchHeader != "" {
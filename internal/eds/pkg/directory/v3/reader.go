@@ -9,12 +9,14 @@ import (
 	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/x"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/pkg/errors"
 
 	"github.com/go-http-utils/headers"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
 	bolt "go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -48,7 +50,7 @@ func (s *Reader) GetObject(ctx context.Context, req *dsr.GetObjectRequest) (*dsr
 		return resp, err
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		obj, err := bdb.Get[dsc.Object](ctx, tx, bdb.ObjectsPath, objIdent.Key())
 		if err != nil {
 			return err
@@ -107,7 +109,7 @@ func (s *Reader) GetObjectMany(ctx context.Context, req *dsr.GetObjectManyReques
 		}
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		for _, i := range req.GetParam() {
 			obj, err := bdb.Get[dsc.Object](ctx, tx, bdb.ObjectsPath, ds.ObjectIdentifier(i).Key())
 			if err != nil {
@@ -149,7 +151,7 @@ func (s *Reader) GetObjects(ctx context.Context, req *dsr.GetObjectsRequest) (*d
 		opts = append(opts, bdb.WithKeyFilter(oid.Key()))
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		iter, err := bdb.NewPageIterator[dsc.Object](ctx, tx, bdb.ObjectsPath, opts...)
 		if err != nil {
 			return err
@@ -157,6 +159,10 @@ func (s *Reader) GetObjects(ctx context.Context, req *dsr.GetObjectsRequest) (*d
 
 		iter.Next()
 
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
 		resp.Results = lo.Map(iter.Value(), func(x *dsc.Object, _ int) *dsc.Object {
 			return ds.PatchObjectRead(x)
 		})
@@ -193,7 +199,7 @@ func (s *Reader) GetRelation(ctx context.Context, req *dsr.GetRelationRequest) (
 		return resp, err
 	}
 
-	err = s.store.DB().View(func(tx *bolt.Tx) error {
+	err = s.store.View(func(tx *bolt.Tx) error {
 		relations, err := bdb.Scan[dsc.Relation](ctx, tx, path, filter.Bytes())
 		if err != nil {
 			return err
@@ -259,18 +265,23 @@ func (s *Reader) GetRelations(ctx context.Context, req *dsr.GetRelationsRequest)
 		bdb.WithKeyFilter(keyFilter.Bytes()),
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		iter, err := bdb.NewScanIterator[dsc.Relation](ctx, tx, path, opts...)
 		if err != nil {
 			return err
 		}
 
 		for iter.Next() {
-			if !valueFilter(iter.Value()) {
+			rel := iter.Value()
+			if iter.Err() != nil {
+				break
+			}
+
+			if !valueFilter(rel) {
 				continue
 			}
 
-			resp.Results = append(resp.GetResults(), iter.Value())
+			resp.Results = append(resp.GetResults(), rel)
 
 			if int64(req.GetPage().GetSize()) == int64(len(resp.GetResults())) {
 				if iter.Next() {
@@ -281,6 +292,10 @@ func (s *Reader) GetRelations(ctx context.Context, req *dsr.GetRelationsRequest)
 			}
 		}
 
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
 		if req.GetWithObjects() {
 			resp.Objects = s.getWithObjects(ctx, tx, resp.GetResults())
 		}
@@ -316,13 +331,16 @@ func (s *Reader) Check(ctx context.Context, req *dsr.CheckRequest) (*dsr.CheckRe
 		return resp, nil
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	viewCtx, span := telemetry.Start(ctx, "bolt.view", attribute.String("db.operation", "check"))
+	err := s.store.View(func(tx *bolt.Tx) error {
 		var err error
 
-		resp, err = check.Exec(ctx, tx, s.store.MC())
+		resp, err = check.Exec(viewCtx, tx, s.store.MC())
 
 		return err
 	})
+	telemetry.End(span, err)
+
 	if err != nil {
 		resp.Context = ds.SetContextWithReason(err)
 	}
@@ -339,7 +357,7 @@ func (s *Reader) Checks(ctx context.Context, req *dsr.ChecksRequest) (*dsr.Check
 		return resp, err
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	err := s.store.View(func(tx *bolt.Tx) error {
 		var err error
 
 		resp, err = checks.Exec(ctx, tx, s.store.MC())
@@ -376,10 +394,11 @@ func (s *Reader) GetGraph(ctx context.Context, req *dsr.GetGraphRequest) (*dsr.G
 		return resp, err
 	}
 
-	err := s.store.DB().View(func(tx *bolt.Tx) error {
+	viewCtx, span := telemetry.Start(ctx, "bolt.view", attribute.String("db.operation", "get_graph"))
+	err := s.store.View(func(tx *bolt.Tx) error {
 		var err error
 
-		results, err := getGraph.Exec(ctx, tx, s.store.MC())
+		results, err := getGraph.Exec(viewCtx, tx, s.store.MC())
 		if err != nil {
 			return err
 		}
@@ -388,6 +407,7 @@ func (s *Reader) GetGraph(ctx context.Context, req *dsr.GetGraphRequest) (*dsr.G
 
 		return nil
 	})
+	telemetry.End(span, err)
 
 	return resp, err
 }
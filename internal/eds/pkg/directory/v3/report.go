@@ -0,0 +1,211 @@
+package v3
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/aserto-dev/azm/cache"
+	"github.com/aserto-dev/azm/model"
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	"github.com/aserto-dev/go-directory/pkg/derr"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// DefaultReportConcurrency, default maximum number of concurrent graph searches per access report.
+const DefaultReportConcurrency int = 4
+
+type Report struct {
+	logger      *zerolog.Logger
+	reader      *Reader
+	concurrency int
+}
+
+var _ report.ReportServer = (*(Report))(nil)
+
+func NewReport(logger *zerolog.Logger, reader *Reader, concurrency int) *Report {
+	if concurrency <= 0 {
+		concurrency = DefaultReportConcurrency
+	}
+
+	return &Report{
+		logger:      logger,
+		reader:      reader,
+		concurrency: concurrency,
+	}
+}
+
+// AccessReport, streams the permissions held on an object (who can access),
+// or the permissions held by a subject (what can the subject access).
+//
+// The request is anchored on an object, when object_type and object_id are set,
+// or on a subject, when subject_type and subject_id are set. The optional relation
+// limits the report to a single permission, the optional subject_type (object anchored)
+// or object_type (subject anchored) limits the report to a single type.
+func (s *Report) AccessReport(req *report.AccessReportRequest, stream grpc.ServerStreamingServer[dsc.RelationIdentifier]) error {
+	ctx := stream.Context()
+	logger := s.logger.With().Str("method", "AccessReport").Interface("req", req).Logger()
+
+	searches, err := s.searches(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(s.limit(req.GetConcurrency()))
+
+	for _, search := range searches {
+		g.Go(func() error {
+			resp, err := s.reader.GetGraph(gCtx, search)
+			if err != nil {
+				logger.Error().Err(err).Interface("search", search).Msg("access_report")
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, oid := range resp.GetResults() {
+				row := &dsc.RelationIdentifier{
+					ObjectType:  search.GetObjectType(),
+					ObjectId:    search.GetObjectId(),
+					Relation:    search.GetRelation(),
+					SubjectType: search.GetSubjectType(),
+					SubjectId:   search.GetSubjectId(),
+				}
+
+				if row.GetObjectId() == "" {
+					row.ObjectId = oid.GetObjectId()
+				} else {
+					row.SubjectId = oid.GetObjectId()
+				}
+
+				if err := stream.Send(row); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// searches, expands the report request into a graph search per (type, permission) combination.
+func (s *Report) searches(ctx context.Context, req *report.AccessReportRequest) ([]*dsr.GetGraphRequest, error) {
+	objectAnchored := req.GetObjectType() != "" && req.GetObjectId() != ""
+	subjectAnchored := req.GetSubjectType() != "" && req.GetSubjectId() != ""
+
+	if objectAnchored == subjectAnchored {
+		return nil, derr.ErrInvalidArgument.Msg("report requires either an object or a subject")
+	}
+
+	types, err := s.objectTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mc := s.reader.store.MC()
+	searches := []*dsr.GetGraphRequest{}
+
+	if objectAnchored {
+		subjectTypes := lo.Ternary(req.GetSubjectType() != "", []string{req.GetSubjectType()}, types)
+
+		for _, st := range subjectTypes {
+			perms, err := s.permissions(mc, req.GetObjectType(), st, req.GetRelation())
+			if err != nil {
+				return nil, err
+			}
+
+			for _, perm := range perms {
+				searches = append(searches, &dsr.GetGraphRequest{
+					ObjectType:  req.GetObjectType(),
+					ObjectId:    req.GetObjectId(),
+					Relation:    perm,
+					SubjectType: st,
+				})
+			}
+		}
+
+		return searches, nil
+	}
+
+	objectTypes := lo.Ternary(req.GetObjectType() != "", []string{req.GetObjectType()}, types)
+
+	for _, ot := range objectTypes {
+		perms, err := s.permissions(mc, ot, req.GetSubjectType(), req.GetRelation())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, perm := range perms {
+			searches = append(searches, &dsr.GetGraphRequest{
+				ObjectType:  ot,
+				Relation:    perm,
+				SubjectType: req.GetSubjectType(),
+				SubjectId:   req.GetSubjectId(),
+			})
+		}
+	}
+
+	return searches, nil
+}
+
+// permissions, returns the sorted permissions the subject type can have on the object type,
+// optionally limited to a single permission.
+func (*Report) permissions(mc *cache.Cache, on, sn, pn string) ([]string, error) {
+	perms, err := mc.AvailablePermissions(cache.ObjectName(on), cache.ObjectName(sn))
+	if err != nil {
+		return nil, err
+	}
+
+	result := lo.FilterMap(perms, func(p cache.RelationName, _ int) (string, bool) {
+		return p.String(), pn == "" || p.String() == pn
+	})
+
+	slices.Sort(result)
+
+	return result, nil
+}
+
+// objectTypes, returns the sorted object type names of the manifest model.
+func (s *Report) objectTypes(ctx context.Context) ([]string, error) {
+	types := []string{}
+
+	err := s.reader.store.View(func(tx *bolt.Tx) error {
+		mod, err := bdb.GetAny[model.Model](ctx, tx, bdb.ManifestPath, bdb.ModelKey)
+		if err != nil {
+			return err
+		}
+
+		for on := range mod.Objects {
+			types = append(types, on.String())
+		}
+
+		return nil
+	})
+
+	slices.Sort(types)
+
+	return types, err
+}
+
+// limit, returns the report concurrency, which can be lowered, but not raised, by the client.
+func (s *Report) limit(concurrency uint32) int {
+	if concurrency == 0 {
+		return s.concurrency
+	}
+
+	return min(int(concurrency), s.concurrency)
+}
@@ -47,7 +47,7 @@ func (s *Writer) SetObject(ctx context.Context, req *dsw.SetObjectRequest) (*dsw
 
 	etag := obj.Hash()
 
-	err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	err := s.store.Update(func(tx *bolt.Tx) error {
 		updObj, err := ds.UpdateMetadataObject(ctx, tx, bdb.ObjectsPath, obj.Key(), req.GetObject())
 		if err != nil {
 			return err
@@ -70,7 +70,7 @@ func (s *Writer) SetObject(ctx context.Context, req *dsw.SetObjectRequest) (*dsw
 
 		updObj.Etag = etag
 
-		objType, err := bdb.Set(ctx, tx, bdb.ObjectsPath, obj.Key(), updObj)
+		objType, err := ds.SetObject(ctx, tx, updObj)
 		if err != nil {
 			return err
 		}
@@ -80,6 +80,8 @@ func (s *Writer) SetObject(ctx context.Context, req *dsw.SetObjectRequest) (*dsw
 		return nil
 	})
 
+	observeWrite(opSetObject, err)
+
 	return resp, err
 }
 
@@ -96,7 +98,7 @@ func (s *Writer) DeleteObject(ctx context.Context, req *dsw.DeleteObjectRequest)
 		return resp, err
 	}
 
-	err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	err := s.store.Update(func(tx *bolt.Tx) error {
 		objIdent := ds.ObjectIdentifier(&dsc.ObjectIdentifier{ObjectType: req.GetObjectType(), ObjectId: req.GetObjectId()})
 
 		// optimistic concurrency check
@@ -114,7 +116,7 @@ func (s *Writer) DeleteObject(ctx context.Context, req *dsw.DeleteObjectRequest)
 			}
 		}
 
-		if err := bdb.Delete(ctx, tx, bdb.ObjectsPath, objIdent.Key()); err != nil {
+		if err := ds.DeleteObject(ctx, tx, objIdent.ObjectIdentifier); err != nil {
 			return err
 		}
 
@@ -134,6 +136,8 @@ func (s *Writer) DeleteObject(ctx context.Context, req *dsw.DeleteObjectRequest)
 		return nil
 	})
 
+	observeWrite(opDeleteObject, err)
+
 	return resp, err
 }
 
@@ -152,7 +156,7 @@ func (s *Writer) SetRelation(ctx context.Context, req *dsw.SetRelationRequest) (
 
 	etag := relation.Hash()
 
-	err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	err := s.store.Update(func(tx *bolt.Tx) error {
 		updRel, err := ds.UpdateMetadataRelation(ctx, tx, bdb.RelationsObjPath, relation.ObjKey(), req.GetRelation())
 		if err != nil {
 			return err
@@ -177,20 +181,18 @@ func (s *Writer) SetRelation(ctx context.Context, req *dsw.SetRelationRequest) (
 
 		updRel.Etag = etag
 
-		objRel, err := bdb.Set(ctx, tx, bdb.RelationsObjPath, relation.ObjKey(), updRel)
+		objRel, err := ds.SetRelation(ctx, tx, updRel)
 		if err != nil {
 			return err
 		}
 
-		if _, err := bdb.Set(ctx, tx, bdb.RelationsSubPath, relation.SubKey(), updRel); err != nil {
-			return err
-		}
-
 		resp.Result = objRel
 
 		return nil
 	})
 
+	observeWrite(opSetRelation, err)
+
 	return resp, err
 }
 
@@ -215,7 +217,7 @@ func (s *Writer) DeleteRelation(ctx context.Context, req *dsw.DeleteRelationRequ
 		return resp, err
 	}
 
-	err := s.store.DB().Update(func(tx *bolt.Tx) error {
+	err := s.store.Update(func(tx *bolt.Tx) error {
 		// optimistic concurrency check
 		ifMatchHeader := metautils.ExtractIncoming(ctx).Get(headers.IfMatch)
 		if ifMatchHeader != "" {
@@ -231,11 +233,7 @@ func (s *Writer) DeleteRelation(ctx context.Context, req *dsw.DeleteRelationRequ
 			}
 		}
 
-		if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, rid.ObjKey()); err != nil {
-			return err
-		}
-
-		if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, rid.SubKey()); err != nil {
+		if err := ds.DeleteRelation(ctx, tx, rel); err != nil {
 			return err
 		}
 
@@ -244,6 +242,8 @@ func (s *Writer) DeleteRelation(ctx context.Context, req *dsw.DeleteRelationRequ
 		return nil
 	})
 
+	observeWrite(opDeleteRelation, err)
+
 	return resp, err
 }
 
@@ -259,15 +259,15 @@ func (*Writer) deleteRelations(ctx context.Context, path bdb.Path, tx *bolt.Tx,
 	}
 
 	for iter.Next() {
-		rel := ds.Relation(iter.Value())
-		if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, rel.ObjKey()); err != nil {
-			return err
+		rel := iter.Value()
+		if iter.Err() != nil {
+			break
 		}
 
-		if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, rel.SubKey()); err != nil {
+		if err := ds.DeleteRelation(ctx, tx, rel); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return iter.Err()
 }
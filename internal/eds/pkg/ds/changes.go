@@ -0,0 +1,56 @@
+package ds
+
+import (
+	"sync"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Change, an object instance, relation instance or manifest change of the directory.
+type Change struct {
+	Object   *dsc.ObjectIdentifier
+	Relation *dsc.Relation
+	Manifest bool
+}
+
+// ChangeFunc, receives the directory changes, the function is called after the
+// write transaction is committed, and must not block.
+type ChangeFunc func(*Change)
+
+var changes = struct {
+	sync.RWMutex
+	next  uint64
+	funcs map[uint64]ChangeFunc
+}{funcs: map[uint64]ChangeFunc{}}
+
+// OnChange, registers a change function, used to invalidate in-process caches
+// of directory data, returns a function to unregister the change function.
+func OnChange(fn ChangeFunc) func() {
+	changes.Lock()
+	defer changes.Unlock()
+
+	id := changes.next
+	changes.next++
+	changes.funcs[id] = fn
+
+	return func() {
+		changes.Lock()
+		defer changes.Unlock()
+
+		delete(changes.funcs, id)
+	}
+}
+
+// notify, calls the change functions when the write transaction is committed, so a read
+// after the invalidation sees the change, and a rolled back change is not notified.
+func notify(tx *bolt.Tx, c *Change) {
+	tx.OnCommit(func() {
+		changes.RLock()
+		defer changes.RUnlock()
+
+		for _, fn := range changes.funcs {
+			fn(c)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package ds
 
 import (
 	"context"
+	"time"
 
 	"github.com/aserto-dev/azm/cache"
 	"github.com/aserto-dev/azm/graph"
@@ -25,14 +26,34 @@ func Check(i *dsr.CheckRequest) *check {
 }
 
 func (i *check) Exec(ctx context.Context, tx *bolt.Tx, mc *cache.Cache) (*dsr.CheckResponse, error) {
+	start := time.Now()
+
 	if err := i.RelationIdentifiersExist(ctx, tx); err != nil {
+		observeCheck(i.ObjectType, i.Relation, start, 0, false, err)
+
 		return &dsr.CheckResponse{
 			Check:   false,
 			Context: SetContextWithReason(err),
 		}, err
 	}
 
-	return mc.Check(i.CheckRequest, getRelations(ctx, tx))
+	lookups := 0
+
+	resp, err := mc.Check(i.CheckRequest, countLookups(getRelations(ctx, tx), &lookups))
+
+	observeCheck(i.ObjectType, i.Relation, start, lookups, resp.GetCheck(), err)
+
+	if err != nil || !i.GetTrace() {
+		return resp, err
+	}
+
+	if resp.GetContext() == nil {
+		resp.Context = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	}
+
+	resp.Context.Fields[ContextPaths] = PathsToValue(Explain(resp.GetTrace(), resp.GetCheck()))
+
+	return resp, nil
 }
 
 func getRelations(ctx context.Context, tx *bolt.Tx) graph.RelationReader {
@@ -46,6 +67,14 @@ func getRelations(ctx context.Context, tx *bolt.Tx) graph.RelationReader {
 	}
 }
 
+// countLookups, counts the relation lookups of the graph traversal, the traversal of a check is sequential.
+func countLookups(reader graph.RelationReader, lookups *int) graph.RelationReader {
+	return func(r *dsc.RelationIdentifier, pool graph.RelationPool, out *[]*dsc.RelationIdentifier) error {
+		*lookups++
+		return reader(r, pool, out)
+	}
+}
+
 func (i *check) RelationIdentifiersExist(ctx context.Context, tx *bolt.Tx) error {
 	if !i.relationIdentifierExist(
 		ctx, tx, bdb.RelationsSubPath,
@@ -50,7 +50,13 @@ func (i *checks) Exec(ctx context.Context, tx *bolt.Tx, mc *cache.Cache) (*dsr.C
 
 	resp := &dsr.ChecksResponse{}
 
+	idx := 0
+
 	for check := range i.CheckRequests() {
+		// propagate trace option, which is not carried over by CheckRequests.
+		check.Trace = i.Checks[idx].GetTrace() || i.GetDefault().GetTrace()
+		idx++
+
 		if err := pool.Produce(check.CheckRequest); err != nil {
 			return resp, err
 		}
@@ -0,0 +1,340 @@
+package ds
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+
+	"github.com/aserto-dev/azm/model"
+	"github.com/aserto-dev/azm/stats"
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// The object and relation counters are kept in the _system/counters bucket, every counter
+// is an 8 byte big endian signed integer, keyed by:
+//
+//	obj|{object_type}
+//	rel|{object_type}|{relation}|{subject_type}[:*]|{subject_relation}
+//
+// The counters are maintained in the same transaction as the write, when the counters
+// bucket exists. When the counters bucket does not exist, the stats fall back to a full scan.
+const (
+	counterSep        string = "|"
+	objCounterPrefix  string = "obj"
+	relCounterPrefix  string = "rel"
+	relCounterParts   int    = 5
+	counterValueSize  int    = 8
+	wildcardSubjectID string = "*"
+)
+
+// CountersEnabled, returns true when the store maintains the object and relation counters.
+func CountersEnabled(tx *bolt.Tx) bool {
+	ok, _ := bdb.BucketExists(tx, bdb.CountersPath)
+	return ok
+}
+
+// InitCounters, computes the counters when the store does not maintain counters yet,
+// returns true when the counters were computed.
+func InitCounters(ctx context.Context, tx *bolt.Tx) (bool, error) {
+	if CountersEnabled(tx) {
+		return false, nil
+	}
+
+	if _, err := RecomputeCounters(ctx, tx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RecomputeCounters, resets the counters from a full scan of the objects and relations buckets.
+func RecomputeCounters(ctx context.Context, tx *bolt.Tx) (*stats.Stats, error) {
+	if err := bdb.DeleteBucket(tx, bdb.CountersPath); err != nil {
+		return nil, err
+	}
+
+	counters := map[string]int64{}
+
+	if err := scanCounters(ctx, tx, bdb.ObjectsPath, func(obj *dsc.Object) string {
+		return objCounterKey(obj.GetType())
+	}, counters); err != nil {
+		return nil, err
+	}
+
+	if err := scanCounters(ctx, tx, bdb.RelationsObjPath, relCounterKey, counters); err != nil {
+		return nil, err
+	}
+
+	b, err := bdb.CreateBucket(tx, bdb.CountersPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, n := range counters {
+		if err := b.Put([]byte(key), counterValue(n)); err != nil {
+			return nil, err
+		}
+	}
+
+	s := NewStats()
+
+	if err := s.loadCounters(tx, objCounterPrefix); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadCounters(tx, relCounterPrefix); err != nil {
+		return nil, err
+	}
+
+	return s.Stats, nil
+}
+
+// scanCounters, counts the instances of the path by counter key, a non existing path has no instances.
+func scanCounters[T any, M bdb.Message[T]](ctx context.Context, tx *bolt.Tx, path bdb.Path, key func(M) string, counters map[string]int64) error {
+	if ok, _ := bdb.BucketExists(tx, path); !ok {
+		return nil
+	}
+
+	iter, err := bdb.NewScanIterator[T, M](ctx, tx, path)
+	if err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		value := iter.Value()
+		if iter.Err() != nil {
+			break
+		}
+
+		counters[key(value)]++
+	}
+
+	return iter.Err()
+}
+
+// ResetCounters, resets all counters to zero, used when the objects and relations buckets are recreated.
+func ResetCounters(tx *bolt.Tx) error {
+	if !CountersEnabled(tx) {
+		return nil
+	}
+
+	if err := bdb.DeleteBucket(tx, bdb.CountersPath); err != nil {
+		return err
+	}
+
+	_, err := bdb.CreateBucket(tx, bdb.CountersPath)
+
+	return err
+}
+
+// CountTotals, returns the total number of objects and relations using the counters,
+// ok is false when the store does not maintain counters.
+func CountTotals(tx *bolt.Tx) (objects, relations int64, ok bool) {
+	b, err := bdb.SetBucket(tx, bdb.CountersPath)
+	if err != nil || b == nil {
+		return 0, 0, false
+	}
+
+	_ = b.ForEach(func(k, v []byte) error {
+		switch {
+		case bytes.HasPrefix(k, []byte(objCounterPrefix+counterSep)):
+			objects += counterInt(v)
+		case bytes.HasPrefix(k, []byte(relCounterPrefix+counterSep)):
+			relations += counterInt(v)
+		}
+
+		return nil
+	})
+
+	return objects, relations, true
+}
+
+// SetObject, persists the object instance, increments the object type counter when the object is created.
+func SetObject(ctx context.Context, tx *bolt.Tx, obj *dsc.Object) (*dsc.Object, error) {
+	key := Object(obj).Key()
+
+	exists, err := bdb.KeyExists(tx, bdb.ObjectsPath, key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := bdb.Set(ctx, tx, bdb.ObjectsPath, key, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	notify(tx, &Change{Object: &dsc.ObjectIdentifier{ObjectType: obj.GetType(), ObjectId: obj.GetId()}})
+
+	if !exists {
+		if err := addCounter(tx, objCounterKey(obj.GetType()), 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteObject, deletes the object instance, decrements the object type counter when the object existed.
+func DeleteObject(ctx context.Context, tx *bolt.Tx, oid *dsc.ObjectIdentifier) error {
+	key := ObjectIdentifier(oid).Key()
+
+	exists, err := bdb.KeyExists(tx, bdb.ObjectsPath, key)
+	if err != nil {
+		return err
+	}
+
+	if err := bdb.Delete(ctx, tx, bdb.ObjectsPath, key); err != nil {
+		return err
+	}
+
+	notify(tx, &Change{Object: oid})
+
+	if !exists {
+		return nil
+	}
+
+	return addCounter(tx, objCounterKey(oid.GetObjectType()), -1)
+}
+
+// SetRelation, persists the relation instance in the object and subject ordered buckets,
+// increments the relation counter when the relation is created.
+func SetRelation(ctx context.Context, tx *bolt.Tx, rel *dsc.Relation) (*dsc.Relation, error) {
+	r := Relation(rel)
+
+	exists, err := bdb.KeyExists(tx, bdb.RelationsObjPath, r.ObjKey())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := bdb.Set(ctx, tx, bdb.RelationsObjPath, r.ObjKey(), rel)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := bdb.Set(ctx, tx, bdb.RelationsSubPath, r.SubKey(), rel); err != nil {
+		return nil, err
+	}
+
+	notify(tx, &Change{Relation: rel})
+
+	if !exists {
+		if err := addCounter(tx, relCounterKey(rel), 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteRelation, deletes the relation instance from the object and subject ordered buckets,
+// decrements the relation counter when the relation existed.
+func DeleteRelation(ctx context.Context, tx *bolt.Tx, rel *dsc.Relation) error {
+	r := Relation(rel)
+
+	exists, err := bdb.KeyExists(tx, bdb.RelationsObjPath, r.ObjKey())
+	if err != nil {
+		return err
+	}
+
+	if err := bdb.Delete(ctx, tx, bdb.RelationsObjPath, r.ObjKey()); err != nil {
+		return err
+	}
+
+	if err := bdb.Delete(ctx, tx, bdb.RelationsSubPath, r.SubKey()); err != nil {
+		return err
+	}
+
+	notify(tx, &Change{Relation: rel})
+
+	if !exists {
+		return nil
+	}
+
+	return addCounter(tx, relCounterKey(rel), -1)
+}
+
+// addCounter, adds delta to the counter, a counter which drops to zero is removed.
+func addCounter(tx *bolt.Tx, key string, delta int64) error {
+	b, err := bdb.SetBucket(tx, bdb.CountersPath)
+	if err != nil || b == nil {
+		// counters are not maintained.
+		return nil //nolint:nilerr
+	}
+
+	n := counterInt(b.Get([]byte(key))) + delta
+	if n <= 0 {
+		return b.Delete([]byte(key))
+	}
+
+	return b.Put([]byte(key), counterValue(n))
+}
+
+// loadCounters, adds the object (prefix obj) or relation (prefix rel) counters to the stats.
+func (s *Stats) loadCounters(tx *bolt.Tx, prefix string) error {
+	b, err := bdb.SetBucket(tx, bdb.CountersPath)
+	if err != nil {
+		return err
+	}
+
+	c := b.Cursor()
+	pfx := []byte(prefix + counterSep)
+
+	for k, v := c.Seek(pfx); k != nil && bytes.HasPrefix(k, pfx); k, v = c.Next() {
+		n := int32(counterInt(v)) //nolint:gosec // stats counters are int32.
+
+		parts := strings.Split(string(k), counterSep)
+
+		switch {
+		case prefix == objCounterPrefix && len(parts) == 2:
+			s.addObject(model.ObjectName(parts[1]), n)
+		case prefix == relCounterPrefix && len(parts) == relCounterParts:
+			s.addRelation(
+				model.ObjectName(parts[1]),
+				model.RelationName(parts[2]),
+				model.ObjectName(parts[3]),
+				model.RelationName(parts[4]),
+				n,
+			)
+		}
+	}
+
+	return nil
+}
+
+func objCounterKey(objType string) string {
+	return objCounterPrefix + counterSep + objType
+}
+
+func relCounterKey(rel *dsc.Relation) string {
+	subType := rel.GetSubjectType()
+	if rel.GetSubjectId() == wildcardSubjectID {
+		subType += string(TypeIDSeparator) + wildcardSubjectID
+	}
+
+	return strings.Join([]string{
+		relCounterPrefix,
+		rel.GetObjectType(),
+		rel.GetRelation(),
+		subType,
+		rel.GetSubjectRelation(),
+	}, counterSep)
+}
+
+func counterValue(n int64) []byte {
+	buf := make([]byte, counterValueSize)
+	binary.BigEndian.PutUint64(buf, uint64(n)) //nolint:gosec // counters are never negative.
+
+	return buf
+}
+
+func counterInt(v []byte) int64 {
+	if len(v) != counterValueSize {
+		return 0
+	}
+
+	return int64(binary.BigEndian.Uint64(v)) //nolint:gosec // counters are never negative.
+}
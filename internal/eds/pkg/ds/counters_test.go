@@ -0,0 +1,163 @@
+package ds_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/aserto-dev/azm/stats"
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+
+	"github.com/rs/zerolog"
+	bolt "go.etcd.io/bbolt"
+)
+
+// TestCounters validates the incrementally maintained counters match a full scan.
+func TestCounters(t *testing.T) {
+	ctx := context.Background()
+	store := openStore(t)
+
+	update(t, store, func(tx *bolt.Tx) error {
+		for _, path := range []bdb.Path{bdb.ObjectsPath, bdb.RelationsObjPath, bdb.RelationsSubPath} {
+			if _, err := bdb.CreateBucket(tx, path); err != nil {
+				return err
+			}
+		}
+
+		_, err := ds.InitCounters(ctx, tx)
+
+		return err
+	})
+
+	update(t, store, func(tx *bolt.Tx) error {
+		for i := range 10 {
+			// set twice, updates must not be counted.
+			for range 2 {
+				if _, err := ds.SetObject(ctx, tx, &dsc.Object{Type: "user", Id: fmt.Sprintf("u%d", i)}); err != nil {
+					return err
+				}
+
+				if _, err := ds.SetRelation(ctx, tx, &dsc.Relation{
+					ObjectType: "group", ObjectId: "g1", Relation: "member", SubjectType: "user", SubjectId: fmt.Sprintf("u%d", i),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := ds.SetRelation(ctx, tx, &dsc.Relation{
+			ObjectType: "group", ObjectId: "g1", Relation: "member", SubjectType: "user", SubjectId: "*",
+		}); err != nil {
+			return err
+		}
+
+		if _, err := ds.SetRelation(ctx, tx, &dsc.Relation{
+			ObjectType: "group", ObjectId: "g2", Relation: "member", SubjectType: "group", SubjectId: "g1", SubjectRelation: "member",
+		}); err != nil {
+			return err
+		}
+
+		if err := ds.DeleteObject(ctx, tx, &dsc.ObjectIdentifier{ObjectType: "user", ObjectId: "u0"}); err != nil {
+			return err
+		}
+
+		// delete of a non existing instance must not be counted.
+		if err := ds.DeleteObject(ctx, tx, &dsc.ObjectIdentifier{ObjectType: "user", ObjectId: "u0"}); err != nil {
+			return err
+		}
+
+		return ds.DeleteRelation(ctx, tx, &dsc.Relation{
+			ObjectType: "group", ObjectId: "g1", Relation: "member", SubjectType: "user", SubjectId: "u1",
+		})
+	})
+
+	counted := calculate(t, store)
+
+	var scanned *stats.Stats
+
+	update(t, store, func(tx *bolt.Tx) error {
+		var err error
+
+		scanned, err = ds.RecomputeCounters(ctx, tx)
+
+		return err
+	})
+
+	assertEqual(t, counted, scanned)
+
+	if ot := counted.ObjectTypes["user"]; ot == nil || ot.ObjCount != 9 {
+		t.Fatalf("expected 9 user objects, got %+v", ot)
+	}
+
+	if ot := counted.ObjectTypes["group"]; ot == nil || ot.Count != 11 {
+		t.Fatalf("expected 11 group relations, got %+v", ot)
+	}
+
+	_ = store.DB().View(func(tx *bolt.Tx) error {
+		if objects, relations, ok := ds.CountTotals(tx); !ok || objects != 9 || relations != 11 {
+			t.Fatalf("unexpected totals objects=%d relations=%d ok=%v", objects, relations, ok)
+		}
+
+		return nil
+	})
+}
+
+func openStore(t *testing.T) *bdb.BoltDB {
+	t.Helper()
+
+	logger := zerolog.Nop()
+
+	store, err := bdb.New(&bdb.Config{DBPath: filepath.Join(t.TempDir(), "test.db")}, &logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(store.Close)
+
+	return store
+}
+
+func update(t *testing.T, store *bdb.BoltDB, fn func(*bolt.Tx) error) {
+	t.Helper()
+
+	if err := store.DB().Update(fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func calculate(t *testing.T, store *bdb.BoltDB) *stats.Stats {
+	t.Helper()
+
+	var s *stats.Stats
+
+	if err := store.DB().View(func(tx *bolt.Tx) error {
+		var err error
+
+		s, err = ds.CalculateStats(context.Background(), tx)
+
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func assertEqual(t *testing.T, expected, actual *stats.Stats) {
+	t.Helper()
+
+	e, _ := json.Marshal(expected)
+	a, _ := json.Marshal(actual)
+
+	if string(e) != string(a) {
+		t.Fatalf("stats mismatch\nexpected: %s\nactual:   %s", e, a)
+	}
+}
@@ -0,0 +1,164 @@
+package ds
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ContextPaths, check response context field containing the explained relation paths.
+const ContextPaths string = "paths"
+
+const (
+	traceSep     string = " = "
+	tracePending string = "?"
+	traceTrue    string = "true"
+	traceCycle   string = "cycle"
+)
+
+type traceNode struct {
+	call     string
+	status   string
+	children []*traceNode
+}
+
+// Explain, derives the relation paths from the check trace.
+// When the check is granted, the paths which granted access are returned, every path starts
+// at the requested check and ends at the relation instance which satisfied the check.
+// When the check is denied, the deepest partial paths explored are returned.
+func Explain(trace []string, granted bool) [][]string {
+	roots := parseTrace(trace)
+
+	paths := [][]string{}
+
+	for _, root := range roots {
+		if granted {
+			grantedPaths(root, []string{}, &paths)
+		} else {
+			allPaths(root, []string{}, &paths)
+		}
+	}
+
+	if granted || len(paths) == 0 {
+		return paths
+	}
+
+	depth := lo.Max(lo.Map(paths, func(p []string, _ int) int { return len(p) }))
+
+	return lo.Filter(paths, func(p []string, _ int) bool { return len(p) == depth })
+}
+
+// parseTrace, reconstructs the call tree from the check trace, the trace is a flattened
+// sequence of "<call> = <status>" entries, where a pending (?) status opens a call,
+// which is closed by the next entry of the same call with a completed status.
+func parseTrace(trace []string) []*traceNode {
+	type frame struct {
+		call string
+		node *traceNode
+	}
+
+	var (
+		roots []*traceNode
+		stack []frame
+	)
+
+	parent := func() *traceNode {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].node != nil {
+				return stack[i].node
+			}
+		}
+
+		return nil
+	}
+
+	add := func(n *traceNode) {
+		if p := parent(); p != nil {
+			p.children = append(p.children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	for _, line := range trace {
+		idx := strings.LastIndex(line, traceSep)
+		if idx < 0 {
+			continue
+		}
+
+		call := strings.TrimSpace(line[:idx])
+		status := strings.TrimSpace(line[idx+len(traceSep):])
+
+		_, open, found := lo.FindLastIndexOf(stack, func(f frame) bool { return f.call == call && f.node != nil })
+
+		if status != tracePending && found {
+			// close the call, including any cycle frames left open above it.
+			stack[open].node.status = status
+			stack = stack[:open]
+
+			continue
+		}
+
+		if status != tracePending {
+			add(&traceNode{call: call, status: status})
+			continue
+		}
+
+		if found {
+			// re-entrant pending call, a cycle.
+			add(&traceNode{call: call, status: traceCycle})
+			stack = append(stack, frame{call: call})
+
+			continue
+		}
+
+		n := &traceNode{call: call, status: tracePending}
+		add(n)
+		stack = append(stack, frame{call: call, node: n})
+	}
+
+	return roots
+}
+
+func grantedPaths(n *traceNode, prefix []string, out *[][]string) {
+	if n.status != traceTrue {
+		return
+	}
+
+	path := append(prefix[:len(prefix):len(prefix)], n.call)
+
+	children := lo.Filter(n.children, func(c *traceNode, _ int) bool { return c.status == traceTrue })
+	if len(children) == 0 {
+		*out = append(*out, path)
+		return
+	}
+
+	for _, c := range children {
+		grantedPaths(c, path, out)
+	}
+}
+
+func allPaths(n *traceNode, prefix []string, out *[][]string) {
+	path := append(prefix[:len(prefix):len(prefix)], n.call)
+
+	if len(n.children) == 0 {
+		*out = append(*out, path)
+		return
+	}
+
+	for _, c := range n.children {
+		allPaths(c, path, out)
+	}
+}
+
+// PathsToValue, converts the explained paths to a list value.
+func PathsToValue(paths [][]string) *structpb.Value {
+	values := lo.Map(paths, func(path []string, _ int) *structpb.Value {
+		return structpb.NewListValue(&structpb.ListValue{
+			Values: lo.Map(path, func(call string, _ int) *structpb.Value { return structpb.NewStringValue(call) }),
+		})
+	})
+
+	return structpb.NewListValue(&structpb.ListValue{Values: values})
+}
@@ -0,0 +1,59 @@
+package ds_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+)
+
+const (
+	canRead = "doc:d1#can_read@user:u1"
+	viewer  = "doc:d1#viewer@user:u1"
+	owner   = "doc:d1#owner@user:u1"
+	member  = "group:g1#member@user:u1"
+)
+
+func trace(outcome string) []string {
+	return strings.Split(strings.Join([]string{
+		canRead + " = ?",
+		"  " + viewer + " = ?",
+		"  " + viewer + " = false",
+		"  " + owner + " = ?",
+		"    " + member + " = ?",
+		"    " + member + " = " + outcome,
+		"  " + owner + " = " + outcome,
+		canRead + " = " + outcome,
+	}, "\n"), "\n")
+}
+
+func TestExplainGranted(t *testing.T) {
+	paths := ds.Explain(trace("true"), true)
+
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 path, got %d: %v", len(paths), paths)
+	}
+
+	if expected := []string{canRead, owner, member}; !slices.Equal(paths[0], expected) {
+		t.Errorf("expected %v, got %v", expected, paths[0])
+	}
+}
+
+func TestExplainDenied(t *testing.T) {
+	paths := ds.Explain(trace("false"), false)
+
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 path, got %d: %v", len(paths), paths)
+	}
+
+	if expected := []string{canRead, owner, member}; !slices.Equal(paths[0], expected) {
+		t.Errorf("expected deepest path %v, got %v", expected, paths[0])
+	}
+}
+
+func TestExplainEmptyTrace(t *testing.T) {
+	if paths := ds.Explain([]string{}, false); len(paths) != 0 {
+		t.Errorf("expected no paths, got %v", paths)
+	}
+}
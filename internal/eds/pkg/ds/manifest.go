@@ -95,6 +95,8 @@ func (m *manifest) SetModel(ctx context.Context, tx *bolt.Tx, mod *model.Model)
 		return err
 	}
 
+	notify(tx, &Change{Manifest: true})
+
 	return nil
 }
 
@@ -104,7 +106,8 @@ func (m *manifest) SetModel(ctx context.Context, tx *bolt.Tx, mod *model.Model)
 //
 // sets the manifest to an empty manifest,
 // updates the model accordingly,
-// deletes and recreates the objects and relations buckets.
+// deletes and recreates the objects and relations buckets,
+// resets the object and relation counters.
 func (m *manifest) Delete(ctx context.Context, tx *bolt.Tx) error {
 	if err := bdb.DeleteBucket(tx, bdb.ManifestPath); err != nil {
 		return err
@@ -138,7 +141,9 @@ func (m *manifest) Delete(ctx context.Context, tx *bolt.Tx) error {
 		return err
 	}
 
-	return nil
+	notify(tx, &Change{Manifest: true})
+
+	return ResetCounters(tx)
 }
 
 func (m *manifest) Hash() string {
@@ -0,0 +1,57 @@
+package ds
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	checkRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_directory_checks_total",
+			Help: "Number of directory checks, by object type, relation and result (true, false, error).",
+		},
+		[]string{"object_type", "relation", "result"},
+	)
+
+	checkDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "topaz_directory_check_duration_seconds",
+			Help:    "Duration of the directory checks, by object type and relation.",
+			Buckets: metrics.LatencyBuckets,
+		},
+		[]string{"object_type", "relation"},
+	)
+
+	checkTraversalDepth = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "topaz_directory_check_traversal_depth",
+			Help:    "Number of relation lookups of the graph traversal of a directory check.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12), //nolint:mnd // 1 .. 2048
+		},
+	)
+)
+
+// Collectors, returns the directory check metrics collectors.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{checkRequests, checkDuration, checkTraversalDepth}
+}
+
+// observeCheck, records the result, duration and traversal depth of a check.
+func observeCheck(objectType, relation string, start time.Time, lookups int, result bool, err error) {
+	objectType = metrics.ObjectType(objectType)
+	relation = metrics.Relation(relation)
+
+	outcome := strconv.FormatBool(result)
+	if err != nil {
+		outcome = "error"
+	}
+
+	checkRequests.WithLabelValues(objectType, relation, outcome).Inc()
+	checkDuration.WithLabelValues(objectType, relation).Observe(time.Since(start).Seconds())
+	checkTraversalDepth.Observe(float64(lookups))
+}
@@ -12,7 +12,8 @@ import (
 	bolt "go.etcd.io/bbolt"
 )
 
-// CalculateStats returns a Stats object with the counts of all objects and relations.
+// CalculateStats returns a Stats object with the counts of all objects and relations,
+// using the counters when maintained by the store, otherwise using a full scan.
 func CalculateStats(ctx context.Context, tx *bolt.Tx) (*stats.Stats, error) {
 	s := NewStats()
 
@@ -36,6 +37,22 @@ func NewStats() *Stats {
 }
 
 func (s *Stats) CountObjects(ctx context.Context, tx *bolt.Tx) error {
+	if CountersEnabled(tx) {
+		return s.loadCounters(tx, objCounterPrefix)
+	}
+
+	return s.scanObjects(ctx, tx)
+}
+
+func (s *Stats) CountRelations(ctx context.Context, tx *bolt.Tx) error {
+	if CountersEnabled(tx) {
+		return s.loadCounters(tx, relCounterPrefix)
+	}
+
+	return s.scanRelations(ctx, tx)
+}
+
+func (s *Stats) scanObjects(ctx context.Context, tx *bolt.Tx) error {
 	iter, err := bdb.NewScanIterator[dsc.Object](ctx, tx, bdb.ObjectsPath)
 	if err != nil {
 		return err
@@ -43,13 +60,17 @@ func (s *Stats) CountObjects(ctx context.Context, tx *bolt.Tx) error {
 
 	for iter.Next() {
 		obj := iter.Value()
+		if iter.Err() != nil {
+			break
+		}
+
 		s.incObject(obj)
 	}
 
-	return nil
+	return iter.Err()
 }
 
-func (s *Stats) CountRelations(ctx context.Context, tx *bolt.Tx) error {
+func (s *Stats) scanRelations(ctx context.Context, tx *bolt.Tx) error {
 	iter, err := bdb.NewScanIterator[dsc.Relation](ctx, tx, bdb.RelationsObjPath)
 	if err != nil {
 		return err
@@ -57,34 +78,49 @@ func (s *Stats) CountRelations(ctx context.Context, tx *bolt.Tx) error {
 
 	for iter.Next() {
 		rel := iter.Value()
+		if iter.Err() != nil {
+			break
+		}
+
 		s.incRelation(rel)
 	}
 
-	return nil
+	return iter.Err()
 }
 
 func (s *Stats) incObject(obj *dsc.Object) {
-	ot, ok := s.ObjectTypes[model.ObjectName(obj.GetType())]
+	s.addObject(model.ObjectName(obj.GetType()), 1)
+}
+
+func (s *Stats) addObject(objType model.ObjectName, n int32) {
+	ot, ok := s.ObjectTypes[objType]
 	if !ok {
 		ot = &stats.ObjectType{
 			Relations: stats.Relations{},
 		}
-		s.ObjectTypes[model.ObjectName(obj.GetType())] = ot
+		s.ObjectTypes[objType] = ot
 	}
 
-	atomic.AddInt32(&ot.ObjCount, 1)
+	atomic.AddInt32(&ot.ObjCount, n)
 }
 
 func (s *Stats) incRelation(rel *dsc.Relation) {
-	objType := model.ObjectName(rel.GetObjectType())
-	relation := model.RelationName(rel.GetRelation())
 	subType := model.ObjectName(rel.GetSubjectType())
-	subRel := model.RelationName(rel.GetSubjectRelation())
 
 	if rel.GetSubjectId() == "*" {
 		subType += ":*"
 	}
 
+	s.addRelation(
+		model.ObjectName(rel.GetObjectType()),
+		model.RelationName(rel.GetRelation()),
+		subType,
+		model.RelationName(rel.GetSubjectRelation()),
+		1,
+	)
+}
+
+func (s *Stats) addRelation(objType model.ObjectName, relation model.RelationName, subType model.ObjectName, subRel model.RelationName, n int32) {
 	// object_types
 	ot := s.ObjectTypes[objType]
 	if ot == nil {
@@ -94,7 +130,7 @@ func (s *Stats) incRelation(rel *dsc.Relation) {
 		s.ObjectTypes[objType] = ot
 	}
 
-	atomic.AddInt32(&ot.Count, 1)
+	atomic.AddInt32(&ot.Count, n)
 
 	// relations
 	re := ot.Relations[relation]
@@ -105,7 +141,7 @@ func (s *Stats) incRelation(rel *dsc.Relation) {
 		ot.Relations[relation] = re
 	}
 
-	atomic.AddInt32(&re.Count, 1)
+	atomic.AddInt32(&re.Count, n)
 
 	// subject_types
 	st := re.SubjectTypes[subType]
@@ -116,7 +152,7 @@ func (s *Stats) incRelation(rel *dsc.Relation) {
 		re.SubjectTypes[subType] = st
 	}
 
-	atomic.AddInt32(&st.Count, 1)
+	atomic.AddInt32(&st.Count, n)
 
 	// subject_relations
 	if subRel != "" {
@@ -126,6 +162,6 @@ func (s *Stats) incRelation(rel *dsc.Relation) {
 			st.SubjectRelations[subRel] = sr
 		}
 
-		atomic.AddInt32(&sr.Count, 1)
+		atomic.AddInt32(&sr.Count, n)
 	}
 }
@@ -0,0 +1,4 @@
+// Package report, the access report service, a server streaming service which reports the
+// permissions held on an object, or held by a subject, generated from
+// proto/aserto/topaz/report/v1/report.proto.
+package report
@@ -0,0 +1,185 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: aserto/topaz/report/v1/report.proto
+
+package report
+
+import (
+	v3 "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AccessReportRequest, the report is anchored on an object, when object_type and object_id are set,
+// or on a subject, when subject_type and subject_id are set.
+type AccessReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// object type of the anchor object, or the object type filter of a subject anchored report.
+	ObjectType string `protobuf:"bytes,1,opt,name=object_type,json=objectType,proto3" json:"object_type,omitempty"`
+	// object id of the anchor object.
+	ObjectId string `protobuf:"bytes,2,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	// permission filter, all permissions when empty.
+	Relation string `protobuf:"bytes,3,opt,name=relation,proto3" json:"relation,omitempty"`
+	// subject type of the anchor subject, or the subject type filter of an object anchored report.
+	SubjectType string `protobuf:"bytes,4,opt,name=subject_type,json=subjectType,proto3" json:"subject_type,omitempty"`
+	// subject id of the anchor subject.
+	SubjectId string `protobuf:"bytes,5,opt,name=subject_id,json=subjectId,proto3" json:"subject_id,omitempty"`
+	// maximum number of concurrent graph searches, lowers, but does not raise, the server concurrency,
+	// the server concurrency when 0.
+	Concurrency   uint32 `protobuf:"varint,6,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessReportRequest) Reset() {
+	*x = AccessReportRequest{}
+	mi := &file_aserto_topaz_report_v1_report_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessReportRequest) ProtoMessage() {}
+
+func (x *AccessReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_report_v1_report_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessReportRequest.ProtoReflect.Descriptor instead.
+func (*AccessReportRequest) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_report_v1_report_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AccessReportRequest) GetObjectType() string {
+	if x != nil {
+		return x.ObjectType
+	}
+	return ""
+}
+
+func (x *AccessReportRequest) GetObjectId() string {
+	if x != nil {
+		return x.ObjectId
+	}
+	return ""
+}
+
+func (x *AccessReportRequest) GetRelation() string {
+	if x != nil {
+		return x.Relation
+	}
+	return ""
+}
+
+func (x *AccessReportRequest) GetSubjectType() string {
+	if x != nil {
+		return x.SubjectType
+	}
+	return ""
+}
+
+func (x *AccessReportRequest) GetSubjectId() string {
+	if x != nil {
+		return x.SubjectId
+	}
+	return ""
+}
+
+func (x *AccessReportRequest) GetConcurrency() uint32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+var File_aserto_topaz_report_v1_report_proto protoreflect.FileDescriptor
+
+const file_aserto_topaz_report_v1_report_proto_rawDesc = "" +
+	"\n" +
+	"#aserto/topaz/report/v1/report.proto\x12\x16aserto.topaz.report.v1\x1a'aserto/directory/common/v3/common.proto\x1a\x1cgoogle/api/annotations.proto\"\xd3\x01\n" +
+	"\x13AccessReportRequest\x12\x1f\n" +
+	"\vobject_type\x18\x01 \x01(\tR\n" +
+	"objectType\x12\x1b\n" +
+	"\tobject_id\x18\x02 \x01(\tR\bobjectId\x12\x1a\n" +
+	"\brelation\x18\x03 \x01(\tR\brelation\x12!\n" +
+	"\fsubject_type\x18\x04 \x01(\tR\vsubjectType\x12\x1d\n" +
+	"\n" +
+	"subject_id\x18\x05 \x01(\tR\tsubjectId\x12 \n" +
+	"\vconcurrency\x18\x06 \x01(\rR\vconcurrency2\x9d\x01\n" +
+	"\x06Report\x12\x92\x01\n" +
+	"\fAccessReport\x12+.aserto.topaz.report.v1.AccessReportRequest\x1a..aserto.directory.common.v3.RelationIdentifier\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/v3/directory/report0\x01B<Z:github.com/aserto-dev/topaz/internal/eds/pkg/report;reportb\x06proto3"
+
+var (
+	file_aserto_topaz_report_v1_report_proto_rawDescOnce sync.Once
+	file_aserto_topaz_report_v1_report_proto_rawDescData []byte
+)
+
+func file_aserto_topaz_report_v1_report_proto_rawDescGZIP() []byte {
+	file_aserto_topaz_report_v1_report_proto_rawDescOnce.Do(func() {
+		file_aserto_topaz_report_v1_report_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aserto_topaz_report_v1_report_proto_rawDesc), len(file_aserto_topaz_report_v1_report_proto_rawDesc)))
+	})
+	return file_aserto_topaz_report_v1_report_proto_rawDescData
+}
+
+var file_aserto_topaz_report_v1_report_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_aserto_topaz_report_v1_report_proto_goTypes = []any{
+	(*AccessReportRequest)(nil),   // 0: aserto.topaz.report.v1.AccessReportRequest
+	(*v3.RelationIdentifier)(nil), // 1: aserto.directory.common.v3.RelationIdentifier
+}
+var file_aserto_topaz_report_v1_report_proto_depIdxs = []int32{
+	0, // 0: aserto.topaz.report.v1.Report.AccessReport:input_type -> aserto.topaz.report.v1.AccessReportRequest
+	1, // 1: aserto.topaz.report.v1.Report.AccessReport:output_type -> aserto.directory.common.v3.RelationIdentifier
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_aserto_topaz_report_v1_report_proto_init() }
+func file_aserto_topaz_report_v1_report_proto_init() {
+	if File_aserto_topaz_report_v1_report_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aserto_topaz_report_v1_report_proto_rawDesc), len(file_aserto_topaz_report_v1_report_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aserto_topaz_report_v1_report_proto_goTypes,
+		DependencyIndexes: file_aserto_topaz_report_v1_report_proto_depIdxs,
+		MessageInfos:      file_aserto_topaz_report_v1_report_proto_msgTypes,
+	}.Build()
+	File_aserto_topaz_report_v1_report_proto = out.File
+	file_aserto_topaz_report_v1_report_proto_goTypes = nil
+	file_aserto_topaz_report_v1_report_proto_depIdxs = nil
+}
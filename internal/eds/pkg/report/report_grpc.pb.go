@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: aserto/topaz/report/v1/report.proto
+
+package report
+
+import (
+	context "context"
+	v3 "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Report_AccessReport_FullMethodName = "/aserto.topaz.report.v1.Report/AccessReport"
+)
+
+// ReportClient is the client API for Report service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Report, reports the permissions held on an object, or held by a subject.
+type ReportClient interface {
+	// AccessReport, streams the permissions held on the request object, or held by the request subject,
+	// every row is a relation identifier, where the relation is the permission held by the subject on the object.
+	AccessReport(ctx context.Context, in *AccessReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v3.RelationIdentifier], error)
+}
+
+type reportClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReportClient(cc grpc.ClientConnInterface) ReportClient {
+	return &reportClient{cc}
+}
+
+func (c *reportClient) AccessReport(ctx context.Context, in *AccessReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[v3.RelationIdentifier], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Report_ServiceDesc.Streams[0], Report_AccessReport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AccessReportRequest, v3.RelationIdentifier]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Report_AccessReportClient = grpc.ServerStreamingClient[v3.RelationIdentifier]
+
+// ReportServer is the server API for Report service.
+// All implementations should embed UnimplementedReportServer
+// for forward compatibility.
+//
+// Report, reports the permissions held on an object, or held by a subject.
+type ReportServer interface {
+	// AccessReport, streams the permissions held on the request object, or held by the request subject,
+	// every row is a relation identifier, where the relation is the permission held by the subject on the object.
+	AccessReport(*AccessReportRequest, grpc.ServerStreamingServer[v3.RelationIdentifier]) error
+}
+
+// UnimplementedReportServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReportServer struct{}
+
+func (UnimplementedReportServer) AccessReport(*AccessReportRequest, grpc.ServerStreamingServer[v3.RelationIdentifier]) error {
+	return status.Errorf(codes.Unimplemented, "method AccessReport not implemented")
+}
+func (UnimplementedReportServer) testEmbeddedByValue() {}
+
+// UnsafeReportServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReportServer will
+// result in compilation errors.
+type UnsafeReportServer interface {
+	mustEmbedUnimplementedReportServer()
+}
+
+func RegisterReportServer(s grpc.ServiceRegistrar, srv ReportServer) {
+	// If the following call pancis, it indicates UnimplementedReportServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Report_ServiceDesc, srv)
+}
+
+func _Report_AccessReport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AccessReportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReportServer).AccessReport(m, &grpc.GenericServerStream[AccessReportRequest, v3.RelationIdentifier]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Report_AccessReportServer = grpc.ServerStreamingServer[v3.RelationIdentifier]
+
+// Report_ServiceDesc is the grpc.ServiceDesc for Report service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Report_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aserto.topaz.report.v1.Report",
+	HandlerType: (*ReportServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AccessReport",
+			Handler:       _Report_AccessReport_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "aserto/topaz/report/v1/report.proto",
+}
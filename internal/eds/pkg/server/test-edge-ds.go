@@ -12,6 +12,7 @@ import (
 
 	"github.com/aserto-dev/topaz/internal/eds"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
 	"github.com/aserto-dev/topaz/internal/grpc/middlewares/gerr"
 	"github.com/rs/zerolog"
 
@@ -30,6 +31,7 @@ type ClientV3 struct {
 	Writer   dsw.WriterClient
 	Importer dsi.ImporterClient
 	Exporter dse.ExporterClient
+	Report   report.ReportClient
 }
 
 const bufferSize int = 1024 * 1024
@@ -55,6 +57,7 @@ func NewTestEdgeServer(ctx context.Context, logger *zerolog.Logger, cfg *directo
 	dsw.RegisterWriterServer(s, edgeDirServer.Writer3())
 	dse.RegisterExporterServer(s, edgeDirServer.Exporter3())
 	dsi.RegisterImporterServer(s, edgeDirServer.Importer3())
+	report.RegisterReportServer(s, edgeDirServer.Report1())
 
 	go func() {
 		if err := s.Serve(listener); err != nil {
@@ -81,6 +84,7 @@ func NewTestEdgeServer(ctx context.Context, logger *zerolog.Logger, cfg *directo
 			Writer:   dsw.NewWriterClient(conn),
 			Importer: dsi.NewImporterClient(conn),
 			Exporter: dse.NewExporterClient(conn),
+			Report:   report.NewReportClient(conn),
 		},
 	}
 
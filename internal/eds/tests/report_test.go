@@ -0,0 +1,117 @@
+package tests_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	dsw "github.com/aserto-dev/go-directory/aserto/directory/writer/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessReport(t *testing.T) {
+	client, cleanup := testInit()
+	t.Cleanup(cleanup)
+
+	ctx := t.Context()
+
+	manifest, err := os.ReadFile("./manifest_v3_test.yaml")
+	require.NoError(t, err)
+
+	require.NoError(t, deleteManifest(client))
+	require.NoError(t, setManifest(client, manifest))
+
+	for _, obj := range []*dsc.Object{
+		{Type: "user", Id: "report-u1"},
+		{Type: "user", Id: "report-u2"},
+		{Type: "user", Id: "report-u3"},
+		{Type: "folder", Id: "report-f1"},
+		{Type: "document", Id: "report-d1"},
+	} {
+		_, err := client.V3.Writer.SetObject(ctx, &dsw.SetObjectRequest{Object: obj})
+		require.NoError(t, err)
+	}
+
+	for _, rel := range []*dsc.Relation{
+		{ObjectType: "document", ObjectId: "report-d1", Relation: "writer", SubjectType: "user", SubjectId: "report-u1"},
+		{ObjectType: "document", ObjectId: "report-d1", Relation: "reader", SubjectType: "user", SubjectId: "report-u2"},
+		{ObjectType: "document", ObjectId: "report-d1", Relation: "parent_folder", SubjectType: "folder", SubjectId: "report-f1"},
+		{ObjectType: "folder", ObjectId: "report-f1", Relation: "owner", SubjectType: "user", SubjectId: "report-u3"},
+	} {
+		_, err := client.V3.Writer.SetRelation(ctx, &dsw.SetRelationRequest{Relation: rel})
+		require.NoError(t, err)
+	}
+
+	t.Run("who-can-access", func(t *testing.T) {
+		rows := accessReport(t, client, &report.AccessReportRequest{
+			ObjectType:  "document",
+			ObjectId:    "report-d1",
+			SubjectType: "user",
+		})
+
+		require.ElementsMatch(t, []string{
+			"document:report-d1#can_only_read@user:report-u2",
+			"document:report-d1#edit@user:report-u1",
+			"document:report-d1#read@user:report-u3",
+			"document:report-d1#view@user:report-u1",
+			"document:report-d1#view@user:report-u2",
+		}, rows)
+	})
+
+	t.Run("what-can-subject-access", func(t *testing.T) {
+		rows := accessReport(t, client, &report.AccessReportRequest{
+			SubjectType: "user",
+			SubjectId:   "report-u3",
+			Concurrency: 1,
+		})
+
+		require.ElementsMatch(t, []string{
+			"document:report-d1#read@user:report-u3",
+			"folder:report-f1#read@user:report-u3",
+		}, rows)
+	})
+
+	t.Run("single-permission", func(t *testing.T) {
+		rows := accessReport(t, client, &report.AccessReportRequest{
+			ObjectType: "document",
+			ObjectId:   "report-d1",
+			Relation:   "edit",
+		})
+
+		require.ElementsMatch(t, []string{"document:report-d1#edit@user:report-u1"}, rows)
+	})
+
+	t.Run("no-anchor", func(t *testing.T) {
+		stream, err := client.V3.Report.AccessReport(ctx, &report.AccessReportRequest{ObjectType: "document"})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		require.Error(t, err)
+	})
+}
+
+func accessReport(t *testing.T, client *server.TestEdgeClient, req *report.AccessReportRequest) []string {
+	t.Helper()
+
+	stream, err := client.V3.Report.AccessReport(t.Context(), req)
+	require.NoError(t, err)
+
+	rows := []string{}
+
+	for {
+		row, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		rows = append(rows, row.GetObjectType()+":"+row.GetObjectId()+"#"+row.GetRelation()+"@"+row.GetSubjectType()+":"+row.GetSubjectId())
+	}
+
+	return rows
+}
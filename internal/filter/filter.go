@@ -0,0 +1,337 @@
+// Package filter, translates the partial evaluation output of a Compile call into a
+// normalized boolean filter, which can be rendered as a SQL (Postgres) where clause or
+// a MongoDB query document.
+//
+// The partial evaluation output is a disjunction (or) of queries, where every query is
+// a conjunction (and) of expressions. Supported expressions are comparisons (==, !=, <,
+// <=, >, >=) and membership (in) between an unknown reference and a constant, negated
+// expressions, and references to a boolean unknown.
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Op string
+
+const (
+	OpTrue  Op = "true"
+	OpFalse Op = "false"
+	OpAnd   Op = "and"
+	OpOr    Op = "or"
+	OpNot   Op = "not"
+	OpEq    Op = "eq"
+	OpNeq   Op = "neq"
+	OpLt    Op = "lt"
+	OpLte   Op = "lte"
+	OpGt    Op = "gt"
+	OpGte   Op = "gte"
+	OpIn    Op = "in"
+)
+
+// Node, a node of the filter, either a constant (true, false), a logical operator (and, or, not)
+// with children, or a comparison (eq, neq, lt, lte, gt, gte, in) of a field with a value.
+type Node struct {
+	Op       Op      `json:"op"`
+	Children []*Node `json:"children,omitempty"`
+	Field    string  `json:"field,omitempty"`
+	Value    any     `json:"value"`
+}
+
+var (
+	ErrUnsupported = errors.New("unsupported expression")
+	ErrInvalid     = errors.New("invalid partial evaluation result")
+)
+
+// builtin operators of the partial evaluation output.
+var operators = map[string]Op{
+	"eq":                OpEq,
+	"equal":             OpEq,
+	"neq":               OpNeq,
+	"lt":                OpLt,
+	"lte":               OpLte,
+	"gt":                OpGt,
+	"gte":               OpGte,
+	"internal.member_2": OpIn,
+}
+
+// flipped, the operator when the operands are swapped.
+var flipped = map[Op]Op{
+	OpEq:  OpEq,
+	OpNeq: OpNeq,
+	OpLt:  OpGt,
+	OpLte: OpGte,
+	OpGt:  OpLt,
+	OpGte: OpLte,
+}
+
+// FromPartial, translates the JSON representation of the partial evaluation result,
+// {"queries": [...], "support": [...]}, into a filter on the fields of the unknowns.
+func FromPartial(result map[string]any, unknowns []string) (*Node, error) {
+	if len(unknowns) == 0 {
+		return nil, errors.Wrap(ErrInvalid, "no unknowns")
+	}
+
+	if support, ok := result["support"].([]any); ok && len(support) > 0 {
+		return nil, errors.Wrap(ErrUnsupported, "partial evaluation result contains support modules")
+	}
+
+	queries, _ := result["queries"].([]any)
+	if len(queries) == 0 {
+		// no query can be satisfied.
+		return &Node{Op: OpFalse}, nil
+	}
+
+	t := &translator{unknowns: unknowns}
+	or := &Node{Op: OpOr}
+
+	for i, query := range queries {
+		exprs, ok := query.([]any)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalid, "query %d", i)
+		}
+
+		if len(exprs) == 0 {
+			// a query without expressions is always satisfied.
+			return &Node{Op: OpTrue}, nil
+		}
+
+		and := &Node{Op: OpAnd}
+
+		for _, expr := range exprs {
+			node, err := t.expr(expr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "query %d", i)
+			}
+
+			and.Children = append(and.Children, node)
+		}
+
+		or.Children = append(or.Children, simplify(and))
+	}
+
+	return simplify(or), nil
+}
+
+// simplify, replaces a logical and/or node with a single child by the child.
+func simplify(n *Node) *Node {
+	if (n.Op == OpAnd || n.Op == OpOr) && len(n.Children) == 1 {
+		return n.Children[0]
+	}
+
+	return n
+}
+
+type translator struct {
+	unknowns []string
+}
+
+func (t *translator) expr(v any) (*Node, error) {
+	expr, ok := v.(map[string]any)
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	node, err := t.terms(expr["terms"])
+	if err != nil {
+		return nil, err
+	}
+
+	if negated, _ := expr["negated"].(bool); negated {
+		return &Node{Op: OpNot, Children: []*Node{node}}, nil
+	}
+
+	return node, nil
+}
+
+func (t *translator) terms(v any) (*Node, error) {
+	switch terms := v.(type) {
+	case map[string]any:
+		// single term expression, a reference to a boolean unknown.
+		field, ok, err := t.field(terms)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, errors.Wrapf(ErrUnsupported, "term %s", termString(terms))
+		}
+
+		return &Node{Op: OpEq, Field: field, Value: true}, nil
+
+	case []any:
+		return t.call(terms)
+
+	default:
+		return nil, ErrInvalid
+	}
+}
+
+// call, translates a builtin call, [operator, operand, operand].
+func (t *translator) call(terms []any) (*Node, error) {
+	const callTerms = 3
+
+	if len(terms) != callTerms {
+		return nil, errors.Wrapf(ErrUnsupported, "call with %d terms", len(terms))
+	}
+
+	operator, _ := terms[0].(map[string]any)
+	name := refName(operator)
+
+	op, ok := operators[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupported, "builtin %q", name)
+	}
+
+	lhs, _ := terms[1].(map[string]any)
+	rhs, _ := terms[2].(map[string]any)
+
+	if op == OpIn {
+		return t.in(lhs, rhs)
+	}
+
+	lField, lok, err := t.field(lhs)
+	if err != nil {
+		return nil, err
+	}
+
+	rField, rok, err := t.field(rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case lok && !rok:
+		value, err := constant(rhs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Node{Op: op, Field: lField, Value: value}, nil
+
+	case rok && !lok:
+		value, err := constant(lhs)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Node{Op: flipped[op], Field: rField, Value: value}, nil
+
+	default:
+		return nil, errors.Wrapf(ErrUnsupported, "%s %s %s", termString(lhs), name, termString(rhs))
+	}
+}
+
+// in, translates internal.member_2(value, collection), where value is an unknown field and collection a constant.
+func (t *translator) in(lhs, rhs map[string]any) (*Node, error) {
+	field, ok, err := t.field(lhs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupported, "%s in %s", termString(lhs), termString(rhs))
+	}
+
+	value, err := constant(rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupported, "in %s", termString(rhs))
+	}
+
+	return &Node{Op: OpIn, Field: field, Value: values}, nil
+}
+
+// field, returns the field name of a reference to an unknown, relative to the unknown,
+// ok is false when the term is not a reference to an unknown.
+func (t *translator) field(term map[string]any) (string, bool, error) {
+	if term["type"] != "ref" {
+		return "", false, nil
+	}
+
+	parts, _ := term["value"].([]any)
+	path := make([]string, 0, len(parts))
+
+	for i, p := range parts {
+		part, _ := p.(map[string]any)
+
+		switch {
+		case i == 0 && part["type"] == "var":
+			path = append(path, fmt.Sprint(part["value"]))
+		case i > 0 && part["type"] == "string":
+			path = append(path, fmt.Sprint(part["value"]))
+		default:
+			return "", false, errors.Wrapf(ErrUnsupported, "reference %s", termString(term))
+		}
+	}
+
+	ref := strings.Join(path, ".")
+
+	for _, unknown := range t.unknowns {
+		if ref == unknown {
+			return path[len(path)-1], true, nil
+		}
+
+		if strings.HasPrefix(ref, unknown+".") {
+			return strings.TrimPrefix(ref, unknown+"."), true, nil
+		}
+	}
+
+	return "", false, errors.Wrapf(ErrUnsupported, "reference %s is not an unknown", ref)
+}
+
+// constant, returns the value of a scalar, array or set term.
+func constant(term map[string]any) (any, error) {
+	switch term["type"] {
+	case "null":
+		return nil, nil
+	case "string", "number", "boolean":
+		return term["value"], nil
+	case "array", "set":
+		items, _ := term["value"].([]any)
+		values := make([]any, 0, len(items))
+
+		for _, item := range items {
+			it, _ := item.(map[string]any)
+
+			v, err := constant(it)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+		}
+
+		return values, nil
+	default:
+		return nil, errors.Wrapf(ErrUnsupported, "term %s", termString(term))
+	}
+}
+
+// refName, returns the dotted name of a builtin operator reference.
+func refName(term map[string]any) string {
+	parts, _ := term["value"].([]any)
+	names := make([]string, 0, len(parts))
+
+	for _, p := range parts {
+		part, _ := p.(map[string]any)
+		names = append(names, fmt.Sprint(part["value"]))
+	}
+
+	return strings.Join(names, ".")
+}
+
+func termString(term map[string]any) string {
+	if term["type"] == "ref" {
+		return refName(term)
+	}
+
+	return fmt.Sprintf("%v", term["value"])
+}
@@ -0,0 +1,381 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: aserto/topaz/filter/v1/filter.proto
+
+package filter
+
+import (
+	v2 "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	api "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FilterRequest, the authorizer compile request, with the rendering options of the filter.
+type FilterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query to compile, e.g. data.policy.allowed == true.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// input document of the query.
+	Input string `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	// unknowns of the partial evaluation, e.g. input.resource.
+	Unknowns []string `protobuf:"bytes,3,rep,name=unknowns,proto3" json:"unknowns,omitempty"`
+	// rules which are not inlined in the compile result.
+	DisableInlining []string `protobuf:"bytes,4,rep,name=disable_inlining,json=disableInlining,proto3" json:"disable_inlining,omitempty"`
+	// query options.
+	Options *v2.QueryOptions `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	// policy context of the query.
+	PolicyContext *api.PolicyContext `protobuf:"bytes,6,opt,name=policy_context,json=policyContext,proto3" json:"policy_context,omitempty"`
+	// identity context of the query.
+	IdentityContext *api.IdentityContext `protobuf:"bytes,7,opt,name=identity_context,json=identityContext,proto3" json:"identity_context,omitempty"`
+	// resource context of the query.
+	ResourceContext *structpb.Struct `protobuf:"bytes,8,opt,name=resource_context,json=resourceContext,proto3" json:"resource_context,omitempty"`
+	// policy instance, selects the policy instance by name.
+	PolicyInstance *api.PolicyInstance `protobuf:"bytes,9,opt,name=policy_instance,json=policyInstance,proto3" json:"policy_instance,omitempty"`
+	// additional rendering of the filter, sql or mongo, none when empty.
+	Dialect string `protobuf:"bytes,10,opt,name=dialect,proto3" json:"dialect,omitempty"`
+	// maps the filter fields to (table qualified) columns or document fields, unmapped fields are used as is.
+	Mapping       map[string]string `protobuf:"bytes,11,rep,name=mapping,proto3" json:"mapping,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterRequest) Reset() {
+	*x = FilterRequest{}
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterRequest) ProtoMessage() {}
+
+func (x *FilterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterRequest.ProtoReflect.Descriptor instead.
+func (*FilterRequest) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_filter_v1_filter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FilterRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *FilterRequest) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *FilterRequest) GetUnknowns() []string {
+	if x != nil {
+		return x.Unknowns
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetDisableInlining() []string {
+	if x != nil {
+		return x.DisableInlining
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetOptions() *v2.QueryOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetPolicyContext() *api.PolicyContext {
+	if x != nil {
+		return x.PolicyContext
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetIdentityContext() *api.IdentityContext {
+	if x != nil {
+		return x.IdentityContext
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetResourceContext() *structpb.Struct {
+	if x != nil {
+		return x.ResourceContext
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetPolicyInstance() *api.PolicyInstance {
+	if x != nil {
+		return x.PolicyInstance
+	}
+	return nil
+}
+
+func (x *FilterRequest) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+func (x *FilterRequest) GetMapping() map[string]string {
+	if x != nil {
+		return x.Mapping
+	}
+	return nil
+}
+
+// FilterResponse, the filter, and its rendering in the requested dialect.
+type FilterResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// filter expression tree, {"op": "and", "children": [{"op": "eq", "field": "owner", "value": "..."}]}.
+	Filter *structpb.Struct `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// SQL rendering, when the requested dialect is sql.
+	Sql *SQLFilter `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`
+	// MongoDB query document, when the requested dialect is mongo.
+	Mongo         *structpb.Struct `protobuf:"bytes,3,opt,name=mongo,proto3" json:"mongo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterResponse) Reset() {
+	*x = FilterResponse{}
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterResponse) ProtoMessage() {}
+
+func (x *FilterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterResponse.ProtoReflect.Descriptor instead.
+func (*FilterResponse) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_filter_v1_filter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FilterResponse) GetFilter() *structpb.Struct {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *FilterResponse) GetSql() *SQLFilter {
+	if x != nil {
+		return x.Sql
+	}
+	return nil
+}
+
+func (x *FilterResponse) GetMongo() *structpb.Struct {
+	if x != nil {
+		return x.Mongo
+	}
+	return nil
+}
+
+// SQLFilter, a Postgres where clause with positional ($n) parameters.
+type SQLFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// where clause.
+	Where string `protobuf:"bytes,1,opt,name=where,proto3" json:"where,omitempty"`
+	// values of the positional parameters.
+	Args          []*structpb.Value `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SQLFilter) Reset() {
+	*x = SQLFilter{}
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SQLFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SQLFilter) ProtoMessage() {}
+
+func (x *SQLFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_filter_v1_filter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SQLFilter.ProtoReflect.Descriptor instead.
+func (*SQLFilter) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_filter_v1_filter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SQLFilter) GetWhere() string {
+	if x != nil {
+		return x.Where
+	}
+	return ""
+}
+
+func (x *SQLFilter) GetArgs() []*structpb.Value {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+var File_aserto_topaz_filter_v1_filter_proto protoreflect.FileDescriptor
+
+const file_aserto_topaz_filter_v1_filter_proto_rawDesc = "" +
+	"\n" +
+	"#aserto/topaz/filter/v1/filter.proto\x12\x16aserto.topaz.filter.v1\x1a/aserto/authorizer/v2/api/identity_context.proto\x1a-aserto/authorizer/v2/api/policy_context.proto\x1a.aserto/authorizer/v2/api/policy_instance.proto\x1a%aserto/authorizer/v2/authorizer.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xa1\x05\n" +
+	"\rFilterRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05input\x18\x02 \x01(\tR\x05input\x12\x1a\n" +
+	"\bunknowns\x18\x03 \x03(\tR\bunknowns\x12)\n" +
+	"\x10disable_inlining\x18\x04 \x03(\tR\x0fdisableInlining\x12<\n" +
+	"\aoptions\x18\x05 \x01(\v2\".aserto.authorizer.v2.QueryOptionsR\aoptions\x12N\n" +
+	"\x0epolicy_context\x18\x06 \x01(\v2'.aserto.authorizer.v2.api.PolicyContextR\rpolicyContext\x12T\n" +
+	"\x10identity_context\x18\a \x01(\v2).aserto.authorizer.v2.api.IdentityContextR\x0fidentityContext\x12B\n" +
+	"\x10resource_context\x18\b \x01(\v2\x17.google.protobuf.StructR\x0fresourceContext\x12Q\n" +
+	"\x0fpolicy_instance\x18\t \x01(\v2(.aserto.authorizer.v2.api.PolicyInstanceR\x0epolicyInstance\x12\x18\n" +
+	"\adialect\x18\n" +
+	" \x01(\tR\adialect\x12L\n" +
+	"\amapping\x18\v \x03(\v22.aserto.topaz.filter.v1.FilterRequest.MappingEntryR\amapping\x1a:\n" +
+	"\fMappingEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa5\x01\n" +
+	"\x0eFilterResponse\x12/\n" +
+	"\x06filter\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06filter\x123\n" +
+	"\x03sql\x18\x02 \x01(\v2!.aserto.topaz.filter.v1.SQLFilterR\x03sql\x12-\n" +
+	"\x05mongo\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x05mongo\"M\n" +
+	"\tSQLFilter\x12\x14\n" +
+	"\x05where\x18\x01 \x01(\tR\x05where\x12*\n" +
+	"\x04args\x18\x02 \x03(\v2\x16.google.protobuf.ValueR\x04args2\x82\x01\n" +
+	"\x06Filter\x12x\n" +
+	"\x06Filter\x12%.aserto.topaz.filter.v1.FilterRequest\x1a&.aserto.topaz.filter.v1.FilterResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v2/authz/filterB4Z2github.com/aserto-dev/topaz/internal/filter;filterb\x06proto3"
+
+var (
+	file_aserto_topaz_filter_v1_filter_proto_rawDescOnce sync.Once
+	file_aserto_topaz_filter_v1_filter_proto_rawDescData []byte
+)
+
+func file_aserto_topaz_filter_v1_filter_proto_rawDescGZIP() []byte {
+	file_aserto_topaz_filter_v1_filter_proto_rawDescOnce.Do(func() {
+		file_aserto_topaz_filter_v1_filter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aserto_topaz_filter_v1_filter_proto_rawDesc), len(file_aserto_topaz_filter_v1_filter_proto_rawDesc)))
+	})
+	return file_aserto_topaz_filter_v1_filter_proto_rawDescData
+}
+
+var file_aserto_topaz_filter_v1_filter_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_aserto_topaz_filter_v1_filter_proto_goTypes = []any{
+	(*FilterRequest)(nil),       // 0: aserto.topaz.filter.v1.FilterRequest
+	(*FilterResponse)(nil),      // 1: aserto.topaz.filter.v1.FilterResponse
+	(*SQLFilter)(nil),           // 2: aserto.topaz.filter.v1.SQLFilter
+	nil,                         // 3: aserto.topaz.filter.v1.FilterRequest.MappingEntry
+	(*v2.QueryOptions)(nil),     // 4: aserto.authorizer.v2.QueryOptions
+	(*api.PolicyContext)(nil),   // 5: aserto.authorizer.v2.api.PolicyContext
+	(*api.IdentityContext)(nil), // 6: aserto.authorizer.v2.api.IdentityContext
+	(*structpb.Struct)(nil),     // 7: google.protobuf.Struct
+	(*api.PolicyInstance)(nil),  // 8: aserto.authorizer.v2.api.PolicyInstance
+	(*structpb.Value)(nil),      // 9: google.protobuf.Value
+}
+var file_aserto_topaz_filter_v1_filter_proto_depIdxs = []int32{
+	4,  // 0: aserto.topaz.filter.v1.FilterRequest.options:type_name -> aserto.authorizer.v2.QueryOptions
+	5,  // 1: aserto.topaz.filter.v1.FilterRequest.policy_context:type_name -> aserto.authorizer.v2.api.PolicyContext
+	6,  // 2: aserto.topaz.filter.v1.FilterRequest.identity_context:type_name -> aserto.authorizer.v2.api.IdentityContext
+	7,  // 3: aserto.topaz.filter.v1.FilterRequest.resource_context:type_name -> google.protobuf.Struct
+	8,  // 4: aserto.topaz.filter.v1.FilterRequest.policy_instance:type_name -> aserto.authorizer.v2.api.PolicyInstance
+	3,  // 5: aserto.topaz.filter.v1.FilterRequest.mapping:type_name -> aserto.topaz.filter.v1.FilterRequest.MappingEntry
+	7,  // 6: aserto.topaz.filter.v1.FilterResponse.filter:type_name -> google.protobuf.Struct
+	2,  // 7: aserto.topaz.filter.v1.FilterResponse.sql:type_name -> aserto.topaz.filter.v1.SQLFilter
+	7,  // 8: aserto.topaz.filter.v1.FilterResponse.mongo:type_name -> google.protobuf.Struct
+	9,  // 9: aserto.topaz.filter.v1.SQLFilter.args:type_name -> google.protobuf.Value
+	0,  // 10: aserto.topaz.filter.v1.Filter.Filter:input_type -> aserto.topaz.filter.v1.FilterRequest
+	1,  // 11: aserto.topaz.filter.v1.Filter.Filter:output_type -> aserto.topaz.filter.v1.FilterResponse
+	11, // [11:12] is the sub-list for method output_type
+	10, // [10:11] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_aserto_topaz_filter_v1_filter_proto_init() }
+func file_aserto_topaz_filter_v1_filter_proto_init() {
+	if File_aserto_topaz_filter_v1_filter_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aserto_topaz_filter_v1_filter_proto_rawDesc), len(file_aserto_topaz_filter_v1_filter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aserto_topaz_filter_v1_filter_proto_goTypes,
+		DependencyIndexes: file_aserto_topaz_filter_v1_filter_proto_depIdxs,
+		MessageInfos:      file_aserto_topaz_filter_v1_filter_proto_msgTypes,
+	}.Build()
+	File_aserto_topaz_filter_v1_filter_proto = out.File
+	file_aserto_topaz_filter_v1_filter_proto_goTypes = nil
+	file_aserto_topaz_filter_v1_filter_proto_depIdxs = nil
+}
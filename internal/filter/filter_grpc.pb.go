@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: aserto/topaz/filter/v1/filter.proto
+
+package filter
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Filter_Filter_FullMethodName = "/aserto.topaz.filter.v1.Filter/Filter"
+)
+
+// FilterClient is the client API for Filter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Filter, translates the partial evaluation of a query into a data filter.
+type FilterClient interface {
+	// Filter, compiles the request query with the request unknowns, and returns the result as a filter,
+	// optionally rendered as a SQL where clause or MongoDB query.
+	Filter(ctx context.Context, in *FilterRequest, opts ...grpc.CallOption) (*FilterResponse, error)
+}
+
+type filterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilterClient(cc grpc.ClientConnInterface) FilterClient {
+	return &filterClient{cc}
+}
+
+func (c *filterClient) Filter(ctx context.Context, in *FilterRequest, opts ...grpc.CallOption) (*FilterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FilterResponse)
+	err := c.cc.Invoke(ctx, Filter_Filter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FilterServer is the server API for Filter service.
+// All implementations should embed UnimplementedFilterServer
+// for forward compatibility.
+//
+// Filter, translates the partial evaluation of a query into a data filter.
+type FilterServer interface {
+	// Filter, compiles the request query with the request unknowns, and returns the result as a filter,
+	// optionally rendered as a SQL where clause or MongoDB query.
+	Filter(context.Context, *FilterRequest) (*FilterResponse, error)
+}
+
+// UnimplementedFilterServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFilterServer struct{}
+
+func (UnimplementedFilterServer) Filter(context.Context, *FilterRequest) (*FilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Filter not implemented")
+}
+func (UnimplementedFilterServer) testEmbeddedByValue() {}
+
+// UnsafeFilterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilterServer will
+// result in compilation errors.
+type UnsafeFilterServer interface {
+	mustEmbedUnimplementedFilterServer()
+}
+
+func RegisterFilterServer(s grpc.ServiceRegistrar, srv FilterServer) {
+	// If the following call pancis, it indicates UnimplementedFilterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Filter_ServiceDesc, srv)
+}
+
+func _Filter_Filter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilterServer).Filter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Filter_Filter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilterServer).Filter(ctx, req.(*FilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Filter_ServiceDesc is the grpc.ServiceDesc for Filter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Filter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aserto.topaz.filter.v1.Filter",
+	HandlerType: (*FilterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Filter",
+			Handler:    _Filter_Filter_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aserto/topaz/filter/v1/filter.proto",
+}
@@ -0,0 +1,176 @@
+package filter_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partial, the compile result of:
+//
+//	allowed if { input.resource.owner == input.user.id }
+//	allowed if { input.resource.public; not input.resource.archived }
+//	allowed if { 3 < input.resource.level; input.resource.status in {"active", "pending"} }
+//
+// with input.user.id = "euang" and unknowns [input.resource].
+const partial = `{
+  "queries": [
+    [{"index": 0, "terms": [
+      {"type": "ref", "value": [{"type": "var", "value": "eq"}]},
+      {"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "owner"}]},
+      {"type": "string", "value": "euang"}
+    ]}],
+    [
+      {"index": 0, "terms": {"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "public"}]}},
+      {"index": 1, "negated": true, "terms": {"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "archived"}]}}
+    ],
+    [
+      {"index": 0, "terms": [
+        {"type": "ref", "value": [{"type": "var", "value": "lt"}]},
+        {"type": "number", "value": 3},
+        {"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "level"}]}
+      ]},
+      {"index": 1, "terms": [
+        {"type": "ref", "value": [{"type": "var", "value": "internal"}, {"type": "string", "value": "member_2"}]},
+        {"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "status"}]},
+        {"type": "set", "value": [{"type": "string", "value": "active"}, {"type": "string", "value": "pending"}]}
+      ]}
+    ]
+  ]
+}`
+
+func parse(t *testing.T, s string) map[string]any {
+	t.Helper()
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+
+	return m
+}
+
+func TestFromPartial(t *testing.T) {
+	node, err := filter.FromPartial(parse(t, partial), []string{"input.resource"})
+	require.NoError(t, err)
+
+	expected := &filter.Node{Op: filter.OpOr, Children: []*filter.Node{
+		{Op: filter.OpEq, Field: "owner", Value: "euang"},
+		{Op: filter.OpAnd, Children: []*filter.Node{
+			{Op: filter.OpEq, Field: "public", Value: true},
+			{Op: filter.OpNot, Children: []*filter.Node{{Op: filter.OpEq, Field: "archived", Value: true}}},
+		}},
+		{Op: filter.OpAnd, Children: []*filter.Node{
+			{Op: filter.OpGt, Field: "level", Value: float64(3)},
+			{Op: filter.OpIn, Field: "status", Value: []any{"active", "pending"}},
+		}},
+	}}
+
+	assert.Equal(t, expected, node)
+}
+
+func TestFromPartialConstant(t *testing.T) {
+	node, err := filter.FromPartial(parse(t, `{}`), []string{"input.resource"})
+	require.NoError(t, err)
+	assert.Equal(t, filter.OpFalse, node.Op)
+
+	node, err = filter.FromPartial(parse(t, `{"queries": [[]]}`), []string{"input.resource"})
+	require.NoError(t, err)
+	assert.Equal(t, filter.OpTrue, node.Op)
+}
+
+func TestFromPartialUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+	}{
+		{name: "support", result: `{"queries": [[]], "support": [{"package": {}}]}`},
+		{name: "builtin", result: `{"queries": [[{"terms": [
+			{"type": "ref", "value": [{"type": "var", "value": "startswith"}]},
+			{"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "name"}]},
+			{"type": "string", "value": "a"}
+		]}]]}`},
+		{name: "two-unknowns", result: `{"queries": [[{"terms": [
+			{"type": "ref", "value": [{"type": "var", "value": "eq"}]},
+			{"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "a"}]},
+			{"type": "ref", "value": [{"type": "var", "value": "input"}, {"type": "string", "value": "resource"}, {"type": "string", "value": "b"}]}
+		]}]]}`},
+		{name: "not-unknown", result: `{"queries": [[{"terms": {"type": "ref", "value": [{"type": "var", "value": "data"}, {"type": "string", "value": "x"}]}}]]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := filter.FromPartial(parse(t, tc.result), []string{"input.resource"})
+			require.ErrorIs(t, err, filter.ErrUnsupported)
+		})
+	}
+}
+
+func TestToSQL(t *testing.T) {
+	node, err := filter.FromPartial(parse(t, partial), []string{"input.resource"})
+	require.NoError(t, err)
+
+	sql, err := filter.ToSQL(node, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `(owner = $1) OR ((public = $2) AND (NOT (archived = $3))) OR ((level > $4) AND (status IN ($5, $6)))`, sql.Where)
+	assert.Equal(t, []any{"euang", true, true, float64(3), "active", "pending"}, sql.Args)
+
+	mapping := map[string]string{"owner": "d.owner_id", "public": "d.is_public", "archived": "d.archived", "level": "d.level", "status": "d.status"}
+
+	sql, err = filter.ToSQL(node, mapping)
+	require.NoError(t, err)
+	assert.Contains(t, sql.Where, "d.owner_id = $1")
+
+	delete(mapping, "status")
+
+	_, err = filter.ToSQL(node, mapping)
+	require.ErrorIs(t, err, filter.ErrUnmappedField)
+
+	_, err = filter.ToSQL(&filter.Node{Op: filter.OpEq, Field: "name; DROP TABLE x", Value: "a"}, nil)
+	require.ErrorIs(t, err, filter.ErrUnsupported)
+
+	sql, err = filter.ToSQL(&filter.Node{Op: filter.OpNeq, Field: "owner"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "owner IS NOT NULL", sql.Where)
+}
+
+func TestToMongo(t *testing.T) {
+	node, err := filter.FromPartial(parse(t, partial), []string{"input.resource"})
+	require.NoError(t, err)
+
+	doc, err := filter.ToMongo(node, map[string]string{"owner": "owner.id", "public": "public", "archived": "archived", "level": "level", "status": "status"})
+	require.NoError(t, err)
+
+	expected := map[string]any{"$or": []any{
+		map[string]any{"owner.id": map[string]any{"$eq": "euang"}},
+		map[string]any{"$and": []any{
+			map[string]any{"public": map[string]any{"$eq": true}},
+			map[string]any{"$nor": []any{map[string]any{"archived": map[string]any{"$eq": true}}}},
+		}},
+		map[string]any{"$and": []any{
+			map[string]any{"level": map[string]any{"$gt": float64(3)}},
+			map[string]any{"status": map[string]any{"$in": []any{"active", "pending"}}},
+		}},
+	}}
+
+	assert.Equal(t, expected, doc)
+}
+
+func TestRender(t *testing.T) {
+	node := &filter.Node{Op: filter.OpEq, Field: "owner", Value: "euang"}
+
+	resp, err := filter.Render(node, filter.DialectSQL, map[string]string{"owner": "docs.owner_id"})
+	require.NoError(t, err)
+	assert.Equal(t, "docs.owner_id = $1", resp.GetSql().GetWhere())
+	assert.Equal(t, "euang", resp.GetSql().GetArgs()[0].GetStringValue())
+	assert.Equal(t, "eq", resp.GetFilter().GetFields()["op"].GetStringValue())
+	assert.Nil(t, resp.GetMongo())
+
+	resp, err = filter.Render(node, filter.DialectMongo, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "euang", resp.GetMongo().GetFields()["owner"].GetStructValue().GetFields()["$eq"].GetStringValue())
+
+	_, err = filter.Render(node, "cql", nil)
+	require.ErrorIs(t, err, filter.ErrUnknownDialect)
+}
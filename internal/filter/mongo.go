@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"github.com/pkg/errors"
+)
+
+var mongoOperators = map[Op]string{
+	OpEq:  "$eq",
+	OpNeq: "$ne",
+	OpLt:  "$lt",
+	OpLte: "$lte",
+	OpGt:  "$gt",
+	OpGte: "$gte",
+	OpIn:  "$in",
+}
+
+// ToMongo, renders the filter as a MongoDB query document, the mapping maps the filter fields
+// to document fields, fields without a mapping are used as document fields.
+func ToMongo(n *Node, mapping map[string]string) (map[string]any, error) {
+	switch n.Op {
+	case OpTrue:
+		return map[string]any{}, nil
+	case OpFalse:
+		return map[string]any{"$expr": false}, nil
+	case OpAnd, OpOr:
+		if len(n.Children) == 0 {
+			if n.Op == OpAnd {
+				return map[string]any{}, nil
+			}
+
+			return map[string]any{"$expr": false}, nil
+		}
+
+		children := make([]any, 0, len(n.Children))
+
+		for _, child := range n.Children {
+			doc, err := ToMongo(child, mapping)
+			if err != nil {
+				return nil, err
+			}
+
+			children = append(children, doc)
+		}
+
+		return map[string]any{"$" + string(n.Op): children}, nil
+	case OpNot:
+		if len(n.Children) != 1 {
+			return nil, errors.Wrap(ErrInvalid, "not requires one operand")
+		}
+
+		doc, err := ToMongo(n.Children[0], mapping)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"$nor": []any{doc}}, nil
+	case OpEq, OpNeq, OpLt, OpLte, OpGt, OpGte, OpIn:
+		field, err := mongoField(n.Field, mapping)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := n.Value.([]any); ok != (n.Op == OpIn) {
+			return nil, errors.Wrapf(ErrUnsupported, "%s %s %v", n.Field, n.Op, n.Value)
+		}
+
+		return map[string]any{field: map[string]any{mongoOperators[n.Op]: n.Value}}, nil
+	default:
+		return nil, errors.Wrapf(ErrUnsupported, "operator %q", n.Op)
+	}
+}
+
+func mongoField(field string, mapping map[string]string) (string, error) {
+	if len(mapping) == 0 {
+		return field, nil
+	}
+
+	mapped, ok := mapping[field]
+	if !ok {
+		return "", errors.Wrapf(ErrUnmappedField, "%q", field)
+	}
+
+	return mapped, nil
+}
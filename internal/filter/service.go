@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	DialectSQL   = "sql"
+	DialectMongo = "mongo"
+)
+
+var ErrUnknownDialect = errors.New("unknown filter dialect")
+
+// CompileRequest, returns the authorizer compile request of the filter request.
+func (r *FilterRequest) CompileRequest() *authorizer.CompileRequest {
+	return &authorizer.CompileRequest{
+		Query:           r.GetQuery(),
+		Input:           r.GetInput(),
+		Unknowns:        r.GetUnknowns(),
+		DisableInlining: r.GetDisableInlining(),
+		Options:         r.GetOptions(),
+		PolicyContext:   r.GetPolicyContext(),
+		IdentityContext: r.GetIdentityContext(),
+		ResourceContext: r.GetResourceContext(),
+		PolicyInstance:  r.GetPolicyInstance(),
+	}
+}
+
+// Render, returns the filter response, extended with the rendering of the requested dialect,
+// a SQL where clause or a MongoDB query document, the mapping maps filter fields to columns
+// or document fields.
+func Render(n *Node, dialect string, mapping map[string]string) (*FilterResponse, error) {
+	resp := &FilterResponse{Filter: &structpb.Struct{}}
+
+	if err := convert(n, resp.Filter); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(dialect) {
+	case "":
+	case DialectSQL:
+		sql, err := ToSQL(n, mapping)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Sql = &SQLFilter{Where: sql.Where}
+
+		for _, arg := range sql.Args {
+			v := &structpb.Value{}
+			if err := convert(arg, v); err != nil {
+				return nil, err
+			}
+
+			resp.Sql.Args = append(resp.Sql.Args, v)
+		}
+	case DialectMongo:
+		doc, err := ToMongo(n, mapping)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Mongo = &structpb.Struct{}
+		if err := convert(doc, resp.Mongo); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Wrapf(ErrUnknownDialect, "%q", dialect)
+	}
+
+	return resp, nil
+}
+
+// convert, converts the value into the struct value message, the filter nodes and renderings
+// are converted using their JSON encoding.
+func convert(v any, msg proto.Message) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return protojson.Unmarshal(buf, msg)
+}
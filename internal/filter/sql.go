@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SQL, a Postgres where clause with positional ($n) parameters.
+type SQL struct {
+	Where string `json:"where"`
+	Args  []any  `json:"args"`
+}
+
+var ErrUnmappedField = errors.New("unmapped field")
+
+// column, an optionally table qualified column name.
+var column = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var sqlOperators = map[Op]string{
+	OpEq:  "=",
+	OpNeq: "<>",
+	OpLt:  "<",
+	OpLte: "<=",
+	OpGt:  ">",
+	OpGte: ">=",
+}
+
+// ToSQL, renders the filter as a Postgres where clause, the mapping maps the filter fields
+// to (table qualified) columns, fields without a mapping are used as column names.
+func ToSQL(n *Node, mapping map[string]string) (*SQL, error) {
+	r := &sqlRenderer{mapping: mapping}
+
+	where, err := r.render(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQL{Where: where, Args: r.args}, nil
+}
+
+type sqlRenderer struct {
+	mapping map[string]string
+	args    []any
+}
+
+func (r *sqlRenderer) render(n *Node) (string, error) {
+	switch n.Op {
+	case OpTrue:
+		return "TRUE", nil
+	case OpFalse:
+		return "FALSE", nil
+	case OpAnd, OpOr:
+		return r.join(n)
+	case OpNot:
+		if len(n.Children) != 1 {
+			return "", errors.Wrap(ErrInvalid, "not requires one operand")
+		}
+
+		child, err := r.render(n.Children[0])
+		if err != nil {
+			return "", err
+		}
+
+		return "NOT (" + child + ")", nil
+	case OpIn:
+		return r.in(n)
+	case OpEq, OpNeq, OpLt, OpLte, OpGt, OpGte:
+		return r.compare(n)
+	default:
+		return "", errors.Wrapf(ErrUnsupported, "operator %q", n.Op)
+	}
+}
+
+func (r *sqlRenderer) join(n *Node) (string, error) {
+	if len(n.Children) == 0 {
+		// an empty conjunction is satisfied, an empty disjunction is not.
+		if n.Op == OpAnd {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	}
+
+	parts := make([]string, 0, len(n.Children))
+
+	for _, child := range n.Children {
+		part, err := r.render(child)
+		if err != nil {
+			return "", err
+		}
+
+		parts = append(parts, "("+part+")")
+	}
+
+	return strings.Join(parts, " "+strings.ToUpper(string(n.Op))+" "), nil
+}
+
+func (r *sqlRenderer) compare(n *Node) (string, error) {
+	col, err := r.column(n.Field)
+	if err != nil {
+		return "", err
+	}
+
+	if n.Value == nil {
+		switch n.Op {
+		case OpEq:
+			return col + " IS NULL", nil
+		case OpNeq:
+			return col + " IS NOT NULL", nil
+		default:
+			return "", errors.Wrapf(ErrUnsupported, "%s %s null", n.Field, n.Op)
+		}
+	}
+
+	if _, ok := n.Value.([]any); ok {
+		return "", errors.Wrapf(ErrUnsupported, "%s %s collection", n.Field, n.Op)
+	}
+
+	return fmt.Sprintf("%s %s %s", col, sqlOperators[n.Op], r.arg(n.Value)), nil
+}
+
+func (r *sqlRenderer) in(n *Node) (string, error) {
+	col, err := r.column(n.Field)
+	if err != nil {
+		return "", err
+	}
+
+	values, ok := n.Value.([]any)
+	if !ok {
+		return "", errors.Wrapf(ErrInvalid, "%s in requires a collection", n.Field)
+	}
+
+	if len(values) == 0 {
+		return "FALSE", nil
+	}
+
+	params := make([]string, 0, len(values))
+	for _, v := range values {
+		params = append(params, r.arg(v))
+	}
+
+	return col + " IN (" + strings.Join(params, ", ") + ")", nil
+}
+
+// arg, adds a parameter and returns its placeholder.
+func (r *sqlRenderer) arg(v any) string {
+	r.args = append(r.args, v)
+	return fmt.Sprintf("$%d", len(r.args))
+}
+
+func (r *sqlRenderer) column(field string) (string, error) {
+	col := field
+
+	if len(r.mapping) > 0 {
+		mapped, ok := r.mapping[field]
+		if !ok {
+			return "", errors.Wrapf(ErrUnmappedField, "%q", field)
+		}
+
+		col = mapped
+	}
+
+	if !column.MatchString(col) {
+		return "", errors.Wrapf(ErrUnsupported, "invalid column name %q", col)
+	}
+
+	return col, nil
+}
@@ -0,0 +1,142 @@
+// Package ratelimit, token bucket rate limits and in-flight request limits of the gRPC calls,
+// per caller and per method.
+//
+// A call is limited by the first rule matching its caller and method, each caller of a rule has
+// its own token bucket and in-flight counter. Rejected calls fail with RESOURCE_EXHAUSTED, and a
+// retry-after header, which the gateway returns as a 429 status with a Retry-After header. The
+// HTTP handlers served by the gateway mux are limited by their path, and rejected with a 429 status.
+package ratelimit
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/pkg/errors"
+)
+
+// Caller prefixes, the caller of a call is identified by the credential which authenticated the call,
+// or by the address of the peer when the call is anonymous.
+const (
+	CallerAPIKey  = "api_key:"
+	CallerClient  = "client:"
+	CallerSubject = "subject:"
+	CallerPeer    = "peer:"
+)
+
+// Config, rate limit configuration.
+type Config struct {
+	// Enabled, enforce the rate limit rules.
+	Enabled bool `json:"enabled"`
+	// MaxCallers, maximum number of tracked callers per rule, the least recently seen callers are evicted (default 10000).
+	MaxCallers int `json:"max_callers"`
+	// Rules, the limits of the calls, a call is limited by the first rule matching its caller and method.
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule, limits the calls of the matching callers to the matching methods.
+type Rule struct {
+	// Name, name of the rule, used as the rule label of the rejection metrics (default rule-<index>).
+	Name string `json:"name"`
+	// Callers, globs of the callers (api_key:<name>, client:<identity>, subject:<sub> or peer:<ip>), all callers when empty.
+	Callers []string `json:"callers"`
+	// Methods, the services (reader, writer, authorizer, ...), gRPC method globs or HTTP paths, all methods when empty.
+	Methods []string `json:"methods"`
+	// RequestsPerSecond, refill rate of the token bucket of a caller, no rate limit when 0.
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	// Burst, size of the token bucket of a caller (default requests_per_second, at least 1).
+	Burst int `json:"burst"`
+	// MaxInFlight, maximum number of concurrent calls of a caller, unlimited when 0.
+	MaxInFlight int `json:"max_in_flight"`
+}
+
+// Validate, validates the rate limit configuration.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MaxCallers < 0 {
+		return errors.Errorf("max_callers %d must not be negative", c.MaxCallers)
+	}
+
+	for i, r := range c.Rules {
+		if _, err := newRule(i, r); err != nil {
+			return errors.Wrapf(err, "rules[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// rule, a validated rule.
+type rule struct {
+	*Rule
+
+	name    string
+	methods keys.Scope
+}
+
+func newRule(index int, r *Rule) (*rule, error) {
+	if r.RequestsPerSecond < 0 {
+		return nil, errors.Errorf("requests_per_second %v must not be negative", r.RequestsPerSecond)
+	}
+
+	if r.Burst < 0 {
+		return nil, errors.Errorf("burst %d must not be negative", r.Burst)
+	}
+
+	if r.MaxInFlight < 0 {
+		return nil, errors.Errorf("max_in_flight %d must not be negative", r.MaxInFlight)
+	}
+
+	if r.RequestsPerSecond == 0 && r.MaxInFlight == 0 {
+		return nil, errors.New("requests_per_second or max_in_flight must be set")
+	}
+
+	for _, caller := range r.Callers {
+		if _, err := path.Match(caller, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid caller glob %q", caller)
+		}
+	}
+
+	methods, err := keys.NewScope(r.Methods)
+	if err != nil {
+		return nil, err
+	}
+
+	name := r.Name
+	if name == "" {
+		name = fmt.Sprintf("rule-%d", index)
+	}
+
+	return &rule{Rule: r, name: name, methods: methods}, nil
+}
+
+// matches, returns true when the rule applies to the call of the method by the caller.
+func (r *rule) matches(caller, method string) bool {
+	if !r.methods.Allows(method) {
+		return false
+	}
+
+	if len(r.Callers) == 0 {
+		return true
+	}
+
+	for _, pattern := range r.Callers {
+		if ok, _ := path.Match(pattern, caller); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// burst, returns the token bucket size of the rule.
+func (r *rule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+
+	return max(1, int(r.RequestsPerSecond))
+}
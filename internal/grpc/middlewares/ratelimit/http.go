@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc/status"
+)
+
+// Handler, returns the handler enforcing the limits of the calls of the HTTP path, for the handlers
+// served by the gateway mux, which do not pass the gRPC middleware. The handler must be wrapped by
+// the authentication handler, which identifies the caller. A rejected call returns a 429 status and
+// a Retry-After header. The next handler is returned as is when rate limiting is disabled.
+func (l *Limiter) Handler(path string, next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release, retryAfter, err := l.Allow(HTTPCaller(r), path)
+		if err != nil {
+			w.Header().Set(HeaderRetryAfter, retryAfterSeconds(retryAfter))
+			http.Error(w, status.Convert(err).Message(), http.StatusTooManyRequests)
+
+			return
+		}
+		defer release()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HTTPCaller, returns the caller of the HTTP request, identified by the credential which
+// authenticated the request, or by the remote address of the request.
+func HTTPCaller(r *http.Request) string {
+	if caller := authenticatedCaller(r.Context()); caller != "" {
+		return caller
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return CallerPeer + host
+}
@@ -0,0 +1,62 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
+	"github.com/aserto-dev/topaz/internal/header"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataPath = "/v1/data"
+
+func TestHandler(t *testing.T) {
+	limiter, err := ratelimit.New(&ratelimit.Config{
+		Enabled: true,
+		Rules: []*ratelimit.Rule{
+			{Methods: []string{dataPath}, Callers: []string{ratelimit.CallerAPIKey + "*"}, RequestsPerSecond: 0.001, Burst: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := limiter.Handler(dataPath, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, dataPath, http.NoBody)
+		if key != "" {
+			req = req.WithContext(header.ContextWithAPIKeyName(req.Context(), key))
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, call("ci").Code)
+
+	rejected := call("ci")
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.NotEmpty(t, rejected.Header().Get(ratelimit.HeaderRetryAfter))
+
+	// callers are limited by their own bucket, anonymous callers do not match the rule.
+	assert.Equal(t, http.StatusOK, call("ops").Code)
+	assert.Equal(t, http.StatusOK, call("").Code)
+	assert.Equal(t, http.StatusOK, call("").Code)
+}
+
+func TestHandlerDisabled(t *testing.T) {
+	limiter, err := ratelimit.New(&ratelimit.Config{})
+	require.NoError(t, err)
+
+	handler := limiter.Handler(dataPath, http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, dataPath, http.NoBody))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
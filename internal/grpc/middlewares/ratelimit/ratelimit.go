@@ -0,0 +1,271 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	grpcutil "github.com/aserto-dev/topaz/internal/grpc"
+	"github.com/aserto-dev/topaz/internal/header"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// HeaderRetryAfter, response header of a rejected call, the number of seconds after which the call can be retried.
+	HeaderRetryAfter = "retry-after"
+
+	// DefaultMaxCallers, default maximum number of tracked callers per rule.
+	DefaultMaxCallers = 10000
+
+	// callerTTL, time after which an idle caller is evicted, which resets its token bucket.
+	callerTTL = 10 * time.Minute
+
+	reasonRate        = "rate"
+	reasonConcurrency = "concurrency"
+
+	headerForwardedFor = "x-forwarded-for"
+)
+
+var rejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "topaz_rate_limit_rejections_total",
+		Help: "Number of calls rejected by the rate limits, by rule, method and reason (rate, concurrency).",
+	},
+	[]string{"rule", "method", "reason"},
+)
+
+// Collectors, returns the rate limit metrics collectors.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{rejections}
+}
+
+// Limiter, enforces the rate limit rules, the limiter is shared by the gRPC servers, so the limits
+// of a caller apply to all its calls, regardless of the listen address.
+type Limiter struct {
+	rules   []*rule
+	callers []*expirable.LRU[string, *callerState]
+	mu      sync.Mutex
+}
+
+// callerState, the token bucket and the in-flight calls of a caller of a rule.
+type callerState struct {
+	bucket   *rate.Limiter
+	inflight atomic.Int64
+}
+
+// New, returns the limiter of the rate limit configuration, nil when rate limiting is disabled.
+func New(cfg *Config) (*Limiter, error) {
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return nil, nil //nolint:nilnil // rate limiting is disabled.
+	}
+
+	maxCallers := cfg.MaxCallers
+	if maxCallers == 0 {
+		maxCallers = DefaultMaxCallers
+	}
+
+	l := &Limiter{}
+
+	for i, r := range cfg.Rules {
+		rule, err := newRule(i, r)
+		if err != nil {
+			return nil, err
+		}
+
+		l.rules = append(l.rules, rule)
+		l.callers = append(l.callers, expirable.NewLRU[string, *callerState](maxCallers, nil, callerTTL))
+	}
+
+	return l, nil
+}
+
+// Allow, admits the call of the method by the caller, the returned function must be called when
+// the admitted call completes. A rejected call returns a RESOURCE_EXHAUSTED error, and the
+// duration after which the call can be retried.
+func (l *Limiter) Allow(caller, method string) (func(), time.Duration, error) {
+	for i, r := range l.rules {
+		if !r.matches(caller, method) {
+			continue
+		}
+
+		state := l.state(i, caller)
+
+		if r.MaxInFlight > 0 && state.inflight.Add(1) > int64(r.MaxInFlight) {
+			state.inflight.Add(-1)
+			rejections.WithLabelValues(r.name, method, reasonConcurrency).Inc()
+
+			return nil, time.Second, status.Errorf(codes.ResourceExhausted, "too many concurrent requests (rule %s)", r.name)
+		}
+
+		release := func() {
+			if r.MaxInFlight > 0 {
+				state.inflight.Add(-1)
+			}
+		}
+
+		if state.bucket != nil {
+			reservation := state.bucket.Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				reservation.Cancel()
+				release()
+				rejections.WithLabelValues(r.name, method, reasonRate).Inc()
+
+				return nil, delay, status.Errorf(codes.ResourceExhausted, "rate limit exceeded (rule %s)", r.name)
+			}
+		}
+
+		return release, 0, nil
+	}
+
+	return func() {}, 0, nil
+}
+
+// state, returns the state of the caller of the rule, created on first use.
+func (l *Limiter) state(index int, caller string) *callerState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state, ok := l.callers[index].Get(caller); ok {
+		return state
+	}
+
+	state := &callerState{}
+
+	if r := l.rules[index]; r.RequestsPerSecond > 0 {
+		state.bucket = rate.NewLimiter(rate.Limit(r.RequestsPerSecond), r.burst())
+	}
+
+	l.callers[index].Add(caller, state)
+
+	return state
+}
+
+// Middleware, the gRPC middleware enforcing the limits, must follow the authentication middleware,
+// which identifies the caller.
+type Middleware struct {
+	limiter *Limiter
+}
+
+var _ grpcutil.Middleware = &Middleware{}
+
+func NewMiddleware(limiter *Limiter) *Middleware {
+	return &Middleware{limiter: limiter}
+}
+
+func (m *Middleware) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, retryAfter, err := m.limiter.Allow(Caller(ctx), info.FullMethod)
+		if err != nil {
+			_ = grpc.SetHeader(ctx, retryAfterHeader(retryAfter))
+			return nil, withRetryInfo(err, retryAfter)
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+func (m *Middleware) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, retryAfter, err := m.limiter.Allow(Caller(stream.Context()), info.FullMethod)
+		if err != nil {
+			_ = stream.SetHeader(retryAfterHeader(retryAfter))
+			return withRetryInfo(err, retryAfter)
+		}
+		defer release()
+
+		return handler(srv, stream)
+	}
+}
+
+// Caller, returns the caller of the call, identified by the credential which authenticated the call,
+// or by the address of the peer. The address forwarded by the gateway is used for the calls of the
+// gateway, which connects from the loopback interface.
+func Caller(ctx context.Context) string {
+	if caller := authenticatedCaller(ctx); caller != "" {
+		return caller
+	}
+
+	return CallerPeer + peerAddress(ctx)
+}
+
+// authenticatedCaller, returns the caller identified by the credential which authenticated the call,
+// empty when the call is anonymous.
+func authenticatedCaller(ctx context.Context) string {
+	if name := header.ExtractAPIKeyName(ctx); name != "" {
+		return CallerAPIKey + name
+	}
+
+	if identity := header.ExtractClientIdentity(ctx); identity != "" {
+		return CallerClient + identity
+	}
+
+	if subject := header.ExtractTokenSubject(ctx); subject != "" {
+		return CallerSubject + subject
+	}
+
+	return ""
+}
+
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	// the gateway appends the remote address of the HTTP request to the forwarded addresses.
+	if fwd := metadata.ValueFromIncomingContext(ctx, headerForwardedFor); len(fwd) > 0 {
+		addrs := strings.Split(fwd[len(fwd)-1], ",")
+		if addr := strings.TrimSpace(addrs[len(addrs)-1]); addr != "" {
+			return addr
+		}
+	}
+
+	return host
+}
+
+// retryAfterHeader, returns the retry-after header in whole seconds, rounded up.
+func retryAfterHeader(retryAfter time.Duration) metadata.MD {
+	return metadata.Pairs(HeaderRetryAfter, retryAfterSeconds(retryAfter))
+}
+
+// retryAfterSeconds, returns the retry delay in whole seconds, rounded up, at least 1.
+func retryAfterSeconds(retryAfter time.Duration) string {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+
+	return strconv.FormatInt(max(1, seconds), 10)
+}
+
+// withRetryInfo, adds the retry delay to the error details of the status.
+func withRetryInfo(err error, retryAfter time.Duration) error {
+	st, err2 := status.Convert(err).WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err2 != nil {
+		return err
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,109 @@
+package tests_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
+	"github.com/aserto-dev/topaz/internal/header"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const checksMethod = "/aserto.directory.reader.v3.Reader/Checks"
+
+func TestRateLimitRejects(t *testing.T) {
+	limiter, err := ratelimit.New(&ratelimit.Config{
+		Enabled: true,
+		Rules: []*ratelimit.Rule{
+			{Name: "ci", Callers: []string{"api_key:ci-*"}, Methods: []string{"reader"}, RequestsPerSecond: 0.1, Burst: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := header.ContextWithAPIKeyName(context.Background(), "ci-runner")
+
+	for range 2 {
+		release, _, err := limiter.Allow(ratelimit.Caller(ctx), checksMethod)
+		require.NoError(t, err)
+		release()
+	}
+
+	_, retryAfter, err := limiter.Allow(ratelimit.Caller(ctx), checksMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Greater(t, retryAfter, time.Second)
+
+	// other callers and methods are not limited by the rule.
+	other := header.ContextWithAPIKeyName(context.Background(), "admin")
+	_, _, err = limiter.Allow(ratelimit.Caller(other), checksMethod)
+	require.NoError(t, err)
+
+	_, _, err = limiter.Allow(ratelimit.Caller(ctx), "/aserto.directory.writer.v3.Writer/SetObject")
+	require.NoError(t, err)
+}
+
+func TestRateLimitInFlight(t *testing.T) {
+	limiter, err := ratelimit.New(&ratelimit.Config{
+		Enabled: true,
+		Rules:   []*ratelimit.Rule{{MaxInFlight: 1}},
+	})
+	require.NoError(t, err)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+	assert.Equal(t, "peer:10.0.0.1", ratelimit.Caller(ctx))
+
+	unary := ratelimit.NewMiddleware(limiter).Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: checksMethod}
+
+	_, err = unary(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		_, err := unary(ctx, nil, info, func(context.Context, any) (any, error) { return output, nil })
+
+		st := status.Convert(err)
+		require.Equal(t, codes.ResourceExhausted, st.Code())
+		require.Len(t, st.Details(), 1)
+		assert.IsType(t, &errdetails.RetryInfo{}, st.Details()[0])
+
+		return output, nil
+	})
+	require.NoError(t, err)
+
+	// the in-flight call is released when it completes.
+	result, err := unary(ctx, nil, info, func(context.Context, any) (any, error) { return output, nil })
+	require.NoError(t, err)
+	assert.Equal(t, output, result)
+}
+
+func TestRateLimitGatewayCaller(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 5000}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "203.0.113.7, 198.51.100.2"))
+
+	assert.Equal(t, "peer:198.51.100.2", ratelimit.Caller(ctx))
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	limiter, err := ratelimit.New(&ratelimit.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+
+	tests := map[string]*ratelimit.Rule{
+		"no limit":       {Methods: []string{"reader"}},
+		"negative rate":  {RequestsPerSecond: -1},
+		"unknown method": {RequestsPerSecond: 1, Methods: []string{"reeder"}},
+		"invalid caller": {RequestsPerSecond: 1, Callers: []string{"["}},
+	}
+
+	for name, rule := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &ratelimit.Config{Enabled: true, Rules: []*ratelimit.Rule{rule}}
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
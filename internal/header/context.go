@@ -8,8 +8,20 @@ import (
 
 type CtxKey string
 
+// APIKeyName, context key of the name of the API key which authenticated the request.
+const APIKeyName = CtxKey("Api-Key-Name")
+
+// ClientIdentity, context key of the identity of the client certificate which authenticated the request.
+const ClientIdentity = CtxKey("Client-Identity")
+
+// TokenSubject, context key of the subject of the bearer token which authenticated the request.
+const TokenSubject = CtxKey("Token-Subject")
+
 var knownValueNames = []CtxKey{
 	HeaderAsertoRequestID,
+	APIKeyName,
+	ClientIdentity,
+	TokenSubject,
 }
 
 func IsValidUUID(uid string) bool {
@@ -31,6 +43,30 @@ func KnownContextValueStrings(ctx context.Context) map[string]any {
 	return result
 }
 
+func ContextWithAPIKeyName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, APIKeyName, name)
+}
+
+func ExtractAPIKeyName(ctx context.Context) string {
+	return extract(ctx, APIKeyName)
+}
+
+func ContextWithClientIdentity(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, ClientIdentity, name)
+}
+
+func ExtractClientIdentity(ctx context.Context) string {
+	return extract(ctx, ClientIdentity)
+}
+
+func ContextWithTokenSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, TokenSubject, subject)
+}
+
+func ExtractTokenSubject(ctx context.Context) string {
+	return extract(ctx, TokenSubject)
+}
+
 func extract(ctx context.Context, key CtxKey) string {
 	id, ok := ctx.Value(key).(string)
 	if !ok {
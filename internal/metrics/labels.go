@@ -0,0 +1,153 @@
+// Package metrics, label cardinality and histogram buckets of the authorization domain metrics.
+//
+// Policy paths and decisions are chosen by the callers, object types and relations by the
+// directory model, to bound the number of time series, values can be bucketed using glob
+// patterns, and the number of distinct values of a label can be capped, further values are
+// reported as "other".
+package metrics
+
+import (
+	"path"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Other, label value reported for the values exceeding the maximum of distinct values of a label.
+const Other = "other"
+
+// DefaultMaxLabelValues, default maximum of distinct values of a label.
+const DefaultMaxLabelValues = 100
+
+// LatencyBuckets, histogram buckets of the domain latencies, 100µs to ~3.3s.
+var LatencyBuckets = prometheus.ExponentialBuckets(0.0001, 2, 16) //nolint:mnd
+
+// Labels, label cardinality configuration of the domain metrics.
+type Labels struct {
+	// MaxValues, maximum number of distinct values of a label, further values are reported as "other", unlimited when 0.
+	MaxValues int `json:"max_values"`
+	// PolicyPaths, glob patterns bucketing the policy path label, a path is reported as its first matching pattern.
+	PolicyPaths []string `json:"policy_paths"`
+	// ObjectTypes, glob patterns bucketing the object type label of the directory checks.
+	ObjectTypes []string `json:"object_types"`
+	// Relations, glob patterns bucketing the relation (or permission) label of the directory checks.
+	Relations []string `json:"relations"`
+}
+
+// Validate, validates the label configuration.
+func (c *Labels) Validate() error {
+	if c.MaxValues < 0 {
+		return errors.Errorf("max_values %d must not be negative", c.MaxValues)
+	}
+
+	for _, patterns := range [][]string{c.PolicyPaths, c.ObjectTypes, c.Relations} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Wrapf(err, "invalid label pattern %q", pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Label, bounds the values of a metric label.
+type Label struct {
+	patterns []string
+	max      int
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewLabel, returns a label bucketing its values using the glob patterns, and reporting the
+// values exceeding max distinct values as "other", max <= 0 is unlimited.
+func NewLabel(maxValues int, patterns ...string) *Label {
+	return &Label{
+		patterns: patterns,
+		max:      maxValues,
+		seen:     map[string]struct{}{},
+	}
+}
+
+// Value, returns the reported value of the label value.
+func (l *Label) Value(v string) string {
+	for _, pattern := range l.patterns {
+		if ok, _ := path.Match(pattern, v); ok {
+			return pattern
+		}
+	}
+
+	if l.max <= 0 {
+		return v
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+
+	if len(l.seen) >= l.max {
+		return Other
+	}
+
+	l.seen[v] = struct{}{}
+
+	return v
+}
+
+type labels struct {
+	policyPath *Label
+	decision   *Label
+	objectType *Label
+	relation   *Label
+}
+
+var current atomic.Pointer[labels]
+
+func init() {
+	Configure(&Labels{MaxValues: DefaultMaxLabelValues})
+}
+
+// Configure, sets the label cardinality of the domain metrics, the distinct values seen so far are reset.
+func Configure(cfg *Labels) {
+	current.Store(&labels{
+		policyPath: NewLabel(cfg.MaxValues, cfg.PolicyPaths...),
+		decision:   NewLabel(cfg.MaxValues),
+		objectType: NewLabel(cfg.MaxValues, cfg.ObjectTypes...),
+		relation:   NewLabel(cfg.MaxValues, cfg.Relations...),
+	})
+}
+
+// PolicyPath, returns the reported value of the policy path label.
+func PolicyPath(v string) string {
+	return current.Load().policyPath.Value(v)
+}
+
+// Decision, returns the reported value of the decision label.
+func Decision(v string) string {
+	return current.Load().decision.Value(v)
+}
+
+// ObjectType, returns the reported value of the object type label.
+func ObjectType(v string) string {
+	return current.Load().objectType.Value(v)
+}
+
+// Relation, returns the reported value of the relation label.
+func Relation(v string) string {
+	return current.Load().relation.Value(v)
+}
+
+// Result, returns the result label value of an operation, success or error.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "success"
+}
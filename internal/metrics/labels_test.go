@@ -0,0 +1,45 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelPatterns(t *testing.T) {
+	l := metrics.NewLabel(0, "todoApp.*.todos", "todoApp.GET.*")
+
+	assert.Equal(t, "todoApp.*.todos", l.Value("todoApp.PUT.todos"))
+	assert.Equal(t, "todoApp.GET.*", l.Value("todoApp.GET.users"))
+	assert.Equal(t, "peoplefinder.GET.api", l.Value("peoplefinder.GET.api"))
+}
+
+func TestLabelMaxValues(t *testing.T) {
+	l := metrics.NewLabel(2, "policies.*")
+
+	assert.Equal(t, "a", l.Value("a"))
+	assert.Equal(t, "b", l.Value("b"))
+	assert.Equal(t, metrics.Other, l.Value("c"))
+	assert.Equal(t, "a", l.Value("a"))
+
+	// bucketed values do not count towards the maximum.
+	assert.Equal(t, "policies.*", l.Value("policies.todo"))
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { metrics.Configure(&metrics.Labels{MaxValues: metrics.DefaultMaxLabelValues}) })
+
+	cfg := &metrics.Labels{MaxValues: 1, PolicyPaths: []string{"todoApp.*"}}
+	require.NoError(t, cfg.Validate())
+
+	metrics.Configure(cfg)
+
+	assert.Equal(t, "todoApp.*", metrics.PolicyPath("todoApp.GET.todos"))
+	assert.Equal(t, "allowed", metrics.Decision("allowed"))
+	assert.Equal(t, metrics.Other, metrics.Decision("visible"))
+
+	require.Error(t, (&metrics.Labels{PolicyPaths: []string{"["}}).Validate())
+	require.Error(t, (&metrics.Labels{MaxValues: -1}).Validate())
+}
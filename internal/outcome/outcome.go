@@ -0,0 +1,89 @@
+package outcome
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// AnnotationPrefix, decision log annotation key prefix of a structured outcome.
+	AnnotationPrefix = "outcome:"
+	// AllowedField, name of the boolean field of a structured outcome.
+	AllowedField = "allowed"
+)
+
+var (
+	ErrNonBoolean     = errors.New("non-boolean outcome")
+	ErrMissingAllowed = errors.New("structured outcome without boolean allowed field")
+)
+
+// Outcomes, structured outcomes keyed by decision name.
+type Outcomes map[string]map[string]any
+
+// Parse, returns the boolean outcome of a decision value, and the structured outcome,
+// when the value is an object with a boolean allowed field, for example:
+//
+//	{"allowed": true, "reasons": [...], "obligations": {...}}
+//
+// In strict mode only boolean values are accepted.
+func Parse(v any, strict bool) (bool, map[string]any, error) {
+	switch value := v.(type) {
+	case bool:
+		return value, nil, nil
+	case map[string]any:
+		if strict {
+			return false, nil, ErrNonBoolean
+		}
+
+		allowed, ok := value[AllowedField].(bool)
+		if !ok {
+			return false, nil, ErrMissingAllowed
+		}
+
+		return allowed, value, nil
+	default:
+		return false, nil, ErrNonBoolean
+	}
+}
+
+// Structs, returns the structured outcomes as the outcomes of the Is response.
+func (o Outcomes) Structs() (map[string]*structpb.Struct, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+
+	structs := make(map[string]*structpb.Struct, len(o))
+
+	for decision, value := range o {
+		s, err := structpb.NewStruct(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decision [%s]", decision)
+		}
+
+		structs[decision] = s
+	}
+
+	return structs, nil
+}
+
+// Annotations, returns the structured outcomes as decision log annotations.
+func (o Outcomes) Annotations() map[string]string {
+	if len(o) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]string, len(o))
+
+	for decision, value := range o {
+		b, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+
+		annotations[AnnotationPrefix+decision] = string(b)
+	}
+
+	return annotations
+}
@@ -0,0 +1,248 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: aserto/topaz/outcome/v1/outcome.proto
+
+package outcome
+
+import (
+	v2 "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	api "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// IsRequest, the authorizer Is request, with the strict decision outcome mode.
+type IsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// identity context of the decisions.
+	IdentityContext *api.IdentityContext `protobuf:"bytes,1,opt,name=identity_context,json=identityContext,proto3" json:"identity_context,omitempty"`
+	// policy context, the policy path and decisions to evaluate.
+	PolicyContext *api.PolicyContext `protobuf:"bytes,2,opt,name=policy_context,json=policyContext,proto3" json:"policy_context,omitempty"`
+	// resource context of the decisions.
+	ResourceContext *structpb.Struct `protobuf:"bytes,3,opt,name=resource_context,json=resourceContext,proto3" json:"resource_context,omitempty"`
+	// policy instance, selects the policy instance by name.
+	PolicyInstance *api.PolicyInstance `protobuf:"bytes,4,opt,name=policy_instance,json=policyInstance,proto3" json:"policy_instance,omitempty"`
+	// only accept boolean decision outcomes, a decision returning an object fails the request.
+	Strict        bool `protobuf:"varint,5,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsRequest) Reset() {
+	*x = IsRequest{}
+	mi := &file_aserto_topaz_outcome_v1_outcome_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsRequest) ProtoMessage() {}
+
+func (x *IsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_outcome_v1_outcome_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsRequest.ProtoReflect.Descriptor instead.
+func (*IsRequest) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_outcome_v1_outcome_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IsRequest) GetIdentityContext() *api.IdentityContext {
+	if x != nil {
+		return x.IdentityContext
+	}
+	return nil
+}
+
+func (x *IsRequest) GetPolicyContext() *api.PolicyContext {
+	if x != nil {
+		return x.PolicyContext
+	}
+	return nil
+}
+
+func (x *IsRequest) GetResourceContext() *structpb.Struct {
+	if x != nil {
+		return x.ResourceContext
+	}
+	return nil
+}
+
+func (x *IsRequest) GetPolicyInstance() *api.PolicyInstance {
+	if x != nil {
+		return x.PolicyInstance
+	}
+	return nil
+}
+
+func (x *IsRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+// IsResponse, the boolean decisions, and the structured outcomes keyed by decision name.
+type IsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// boolean decisions, the allowed field of a structured outcome.
+	Decisions []*v2.Decision `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	// structured outcomes, for example {"allowed": true, "reasons": [...], "obligations": {...}}.
+	Outcomes      map[string]*structpb.Struct `protobuf:"bytes,2,rep,name=outcomes,proto3" json:"outcomes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsResponse) Reset() {
+	*x = IsResponse{}
+	mi := &file_aserto_topaz_outcome_v1_outcome_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsResponse) ProtoMessage() {}
+
+func (x *IsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_outcome_v1_outcome_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsResponse.ProtoReflect.Descriptor instead.
+func (*IsResponse) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_outcome_v1_outcome_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IsResponse) GetDecisions() []*v2.Decision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *IsResponse) GetOutcomes() map[string]*structpb.Struct {
+	if x != nil {
+		return x.Outcomes
+	}
+	return nil
+}
+
+var File_aserto_topaz_outcome_v1_outcome_proto protoreflect.FileDescriptor
+
+const file_aserto_topaz_outcome_v1_outcome_proto_rawDesc = "" +
+	"\n" +
+	"%aserto/topaz/outcome/v1/outcome.proto\x12\x17aserto.topaz.outcome.v1\x1a/aserto/authorizer/v2/api/identity_context.proto\x1a-aserto/authorizer/v2/api/policy_context.proto\x1a.aserto/authorizer/v2/api/policy_instance.proto\x1a%aserto/authorizer/v2/authorizer.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xe0\x02\n" +
+	"\tIsRequest\x12T\n" +
+	"\x10identity_context\x18\x01 \x01(\v2).aserto.authorizer.v2.api.IdentityContextR\x0fidentityContext\x12N\n" +
+	"\x0epolicy_context\x18\x02 \x01(\v2'.aserto.authorizer.v2.api.PolicyContextR\rpolicyContext\x12B\n" +
+	"\x10resource_context\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x0fresourceContext\x12Q\n" +
+	"\x0fpolicy_instance\x18\x04 \x01(\v2(.aserto.authorizer.v2.api.PolicyInstanceR\x0epolicyInstance\x12\x16\n" +
+	"\x06strict\x18\x05 \x01(\bR\x06strict\"\xef\x01\n" +
+	"\n" +
+	"IsResponse\x12<\n" +
+	"\tdecisions\x18\x01 \x03(\v2\x1e.aserto.authorizer.v2.DecisionR\tdecisions\x12M\n" +
+	"\boutcomes\x18\x02 \x03(\v21.aserto.topaz.outcome.v1.IsResponse.OutcomesEntryR\boutcomes\x1aT\n" +
+	"\rOutcomesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05value:\x028\x012{\n" +
+	"\aOutcome\x12p\n" +
+	"\x02Is\x12\".aserto.topaz.outcome.v1.IsRequest\x1a#.aserto.topaz.outcome.v1.IsResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/v2/authz/outcomesB6Z4github.com/aserto-dev/topaz/internal/outcome;outcomeb\x06proto3"
+
+var (
+	file_aserto_topaz_outcome_v1_outcome_proto_rawDescOnce sync.Once
+	file_aserto_topaz_outcome_v1_outcome_proto_rawDescData []byte
+)
+
+func file_aserto_topaz_outcome_v1_outcome_proto_rawDescGZIP() []byte {
+	file_aserto_topaz_outcome_v1_outcome_proto_rawDescOnce.Do(func() {
+		file_aserto_topaz_outcome_v1_outcome_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aserto_topaz_outcome_v1_outcome_proto_rawDesc), len(file_aserto_topaz_outcome_v1_outcome_proto_rawDesc)))
+	})
+	return file_aserto_topaz_outcome_v1_outcome_proto_rawDescData
+}
+
+var file_aserto_topaz_outcome_v1_outcome_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_aserto_topaz_outcome_v1_outcome_proto_goTypes = []any{
+	(*IsRequest)(nil),           // 0: aserto.topaz.outcome.v1.IsRequest
+	(*IsResponse)(nil),          // 1: aserto.topaz.outcome.v1.IsResponse
+	nil,                         // 2: aserto.topaz.outcome.v1.IsResponse.OutcomesEntry
+	(*api.IdentityContext)(nil), // 3: aserto.authorizer.v2.api.IdentityContext
+	(*api.PolicyContext)(nil),   // 4: aserto.authorizer.v2.api.PolicyContext
+	(*structpb.Struct)(nil),     // 5: google.protobuf.Struct
+	(*api.PolicyInstance)(nil),  // 6: aserto.authorizer.v2.api.PolicyInstance
+	(*v2.Decision)(nil),         // 7: aserto.authorizer.v2.Decision
+}
+var file_aserto_topaz_outcome_v1_outcome_proto_depIdxs = []int32{
+	3, // 0: aserto.topaz.outcome.v1.IsRequest.identity_context:type_name -> aserto.authorizer.v2.api.IdentityContext
+	4, // 1: aserto.topaz.outcome.v1.IsRequest.policy_context:type_name -> aserto.authorizer.v2.api.PolicyContext
+	5, // 2: aserto.topaz.outcome.v1.IsRequest.resource_context:type_name -> google.protobuf.Struct
+	6, // 3: aserto.topaz.outcome.v1.IsRequest.policy_instance:type_name -> aserto.authorizer.v2.api.PolicyInstance
+	7, // 4: aserto.topaz.outcome.v1.IsResponse.decisions:type_name -> aserto.authorizer.v2.Decision
+	2, // 5: aserto.topaz.outcome.v1.IsResponse.outcomes:type_name -> aserto.topaz.outcome.v1.IsResponse.OutcomesEntry
+	5, // 6: aserto.topaz.outcome.v1.IsResponse.OutcomesEntry.value:type_name -> google.protobuf.Struct
+	0, // 7: aserto.topaz.outcome.v1.Outcome.Is:input_type -> aserto.topaz.outcome.v1.IsRequest
+	1, // 8: aserto.topaz.outcome.v1.Outcome.Is:output_type -> aserto.topaz.outcome.v1.IsResponse
+	8, // [8:9] is the sub-list for method output_type
+	7, // [7:8] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_aserto_topaz_outcome_v1_outcome_proto_init() }
+func file_aserto_topaz_outcome_v1_outcome_proto_init() {
+	if File_aserto_topaz_outcome_v1_outcome_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aserto_topaz_outcome_v1_outcome_proto_rawDesc), len(file_aserto_topaz_outcome_v1_outcome_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aserto_topaz_outcome_v1_outcome_proto_goTypes,
+		DependencyIndexes: file_aserto_topaz_outcome_v1_outcome_proto_depIdxs,
+		MessageInfos:      file_aserto_topaz_outcome_v1_outcome_proto_msgTypes,
+	}.Build()
+	File_aserto_topaz_outcome_v1_outcome_proto = out.File
+	file_aserto_topaz_outcome_v1_outcome_proto_goTypes = nil
+	file_aserto_topaz_outcome_v1_outcome_proto_depIdxs = nil
+}
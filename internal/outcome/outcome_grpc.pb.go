@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: aserto/topaz/outcome/v1/outcome.proto
+
+package outcome
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Outcome_Is_FullMethodName = "/aserto.topaz.outcome.v1.Outcome/Is"
+)
+
+// OutcomeClient is the client API for Outcome service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Outcome, evaluates decisions, which return either a boolean or a structured outcome.
+type OutcomeClient interface {
+	// Is, evaluates the decisions of the policy context, and returns the boolean decisions,
+	// and the structured outcomes of the decisions which returned an object.
+	Is(ctx context.Context, in *IsRequest, opts ...grpc.CallOption) (*IsResponse, error)
+}
+
+type outcomeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutcomeClient(cc grpc.ClientConnInterface) OutcomeClient {
+	return &outcomeClient{cc}
+}
+
+func (c *outcomeClient) Is(ctx context.Context, in *IsRequest, opts ...grpc.CallOption) (*IsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsResponse)
+	err := c.cc.Invoke(ctx, Outcome_Is_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OutcomeServer is the server API for Outcome service.
+// All implementations should embed UnimplementedOutcomeServer
+// for forward compatibility.
+//
+// Outcome, evaluates decisions, which return either a boolean or a structured outcome.
+type OutcomeServer interface {
+	// Is, evaluates the decisions of the policy context, and returns the boolean decisions,
+	// and the structured outcomes of the decisions which returned an object.
+	Is(context.Context, *IsRequest) (*IsResponse, error)
+}
+
+// UnimplementedOutcomeServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOutcomeServer struct{}
+
+func (UnimplementedOutcomeServer) Is(context.Context, *IsRequest) (*IsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Is not implemented")
+}
+func (UnimplementedOutcomeServer) testEmbeddedByValue() {}
+
+// UnsafeOutcomeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutcomeServer will
+// result in compilation errors.
+type UnsafeOutcomeServer interface {
+	mustEmbedUnimplementedOutcomeServer()
+}
+
+func RegisterOutcomeServer(s grpc.ServiceRegistrar, srv OutcomeServer) {
+	// If the following call pancis, it indicates UnimplementedOutcomeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Outcome_ServiceDesc, srv)
+}
+
+func _Outcome_Is_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutcomeServer).Is(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outcome_Is_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutcomeServer).Is(ctx, req.(*IsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Outcome_ServiceDesc is the grpc.ServiceDesc for Outcome service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Outcome_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aserto.topaz.outcome.v1.Outcome",
+	HandlerType: (*OutcomeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Is",
+			Handler:    _Outcome_Is_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aserto/topaz/outcome/v1/outcome.proto",
+}
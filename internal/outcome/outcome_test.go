@@ -0,0 +1,51 @@
+package outcome_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/outcome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      any
+		strict     bool
+		allowed    bool
+		structured bool
+		err        error
+	}{
+		{name: "bool", value: true, allowed: true},
+		{name: "bool-strict", value: false, strict: true},
+		{name: "object", value: map[string]any{"allowed": true, "reasons": []any{"owner"}}, allowed: true, structured: true},
+		{name: "object-strict", value: map[string]any{"allowed": true}, strict: true, err: outcome.ErrNonBoolean},
+		{name: "object-no-allowed", value: map[string]any{"reasons": []any{}}, err: outcome.ErrMissingAllowed},
+		{name: "string", value: "true", err: outcome.ErrNonBoolean},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, structured, err := outcome.Parse(tc.value, tc.strict)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, allowed)
+			assert.Equal(t, tc.structured, structured != nil)
+		})
+	}
+}
+
+func TestStructsAnnotations(t *testing.T) {
+	o := outcome.Outcomes{"allowed": {"allowed": true, "reasons": []any{"owner"}}}
+
+	structs, err := o.Structs()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"allowed": true, "reasons": []any{"owner"}}, structs["allowed"].AsMap())
+
+	assert.Equal(t, map[string]string{"outcome:allowed": `{"allowed":true,"reasons":["owner"]}`}, o.Annotations())
+}
@@ -0,0 +1,282 @@
+// Package policytest, runs the rego unit tests (test_ rules) of local policy files, using
+// the OPA test runner, with the topaz custom built-ins registered, and reports the results
+// and, optionally, the policy coverage.
+package policytest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/registry"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/cover"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/tester"
+	"github.com/pkg/errors"
+)
+
+type Outcome string
+
+const (
+	OutcomePass  Outcome = "pass"
+	OutcomeFail  Outcome = "fail"
+	OutcomeError Outcome = "error"
+	OutcomeSkip  Outcome = "skip"
+)
+
+// DefaultTimeout, the default timeout of a test case.
+const DefaultTimeout = 5 * time.Second
+
+// Options, the policy files and the test run options.
+type Options struct {
+	// Paths, the policy files and directories, the data files are loaded as policy data.
+	Paths []string
+	// Ignore, the file and directory name patterns to ignore.
+	Ignore []string
+	// Run, regular expression, only the test cases matching the expression are run.
+	Run string
+	// Coverage, report the policy coverage.
+	Coverage bool
+	// Timeout, the timeout of a test case.
+	Timeout time.Duration
+	// Builtins, the custom built-ins available to the policy.
+	Builtins []*tester.Builtin
+}
+
+// Result, the result of a test case.
+type Result struct {
+	Package  string        `json:"package"`
+	Name     string        `json:"name"`
+	File     string        `json:"file,omitempty"`
+	Row      int           `json:"row,omitempty"`
+	Outcome  Outcome       `json:"outcome"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Output   string        `json:"output,omitempty"`
+}
+
+// Report, the results of the test run.
+type Report struct {
+	Results  []*Result     `json:"results"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Errors   int           `json:"errors"`
+	Skipped  int           `json:"skipped"`
+	Coverage *cover.Report `json:"coverage,omitempty"`
+}
+
+// OK, returns true when no test case failed or errored.
+func (r *Report) OK() bool {
+	return r.Failed == 0 && r.Errors == 0
+}
+
+// Builtins, returns the custom built-ins of the registry as test runner built-ins.
+func Builtins(fns []*registry.Builtin) []*tester.Builtin {
+	result := make([]*tester.Builtin, 0, len(fns))
+
+	for _, fn := range fns {
+		result = append(result, &tester.Builtin{
+			Decl: &ast.Builtin{Name: fn.Decl.Name, Decl: fn.Decl.Decl},
+			Func: rego.Function1(fn.Decl, fn.Impl),
+		})
+	}
+
+	return result
+}
+
+// Run, loads the policy files and runs the test cases.
+func Run(ctx context.Context, opts *Options) (*Report, error) {
+	if len(opts.Paths) == 0 {
+		return nil, errors.New("no policy paths")
+	}
+
+	result, err := loader.NewFileLoader().
+		WithRegoVersion(ast.RegoV0).
+		WithProcessAnnotation(true).
+		Filtered(opts.Paths, ignoreFilter(opts.Ignore))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load policy")
+	}
+
+	modules := result.ParsedModules()
+
+	store := inmem.NewFromObjectWithOpts(result.Documents, inmem.OptRoundTripOnWrite(false))
+
+	txn, err := store.NewTransaction(ctx, storage.WriteParams)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Abort(ctx, txn)
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	runner := tester.NewRunner().
+		SetDefaultRegoVersion(ast.RegoV0).
+		SetStore(store).
+		SetModules(modules).
+		AddCustomBuiltins(opts.Builtins).
+		CapturePrintOutput(true).
+		SetTimeout(timeout).
+		Filter(opts.Run)
+
+	var coverage *cover.Cover
+	if opts.Coverage {
+		coverage = cover.New()
+		runner.SetCoverageQueryTracer(coverage)
+	}
+
+	ch, err := runner.RunTests(ctx, txn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run tests")
+	}
+
+	report := &Report{Results: []*Result{}}
+
+	for tr := range ch {
+		report.add(tr)
+	}
+
+	if coverage != nil {
+		cr := coverage.Report(modules)
+		report.Coverage = &cr
+	}
+
+	return report, nil
+}
+
+func (r *Report) add(tr *tester.Result) {
+	res := &Result{
+		Package:  strings.TrimPrefix(tr.Package, "data."),
+		Name:     tr.Name,
+		Duration: tr.Duration,
+		Output:   string(tr.Output),
+	}
+
+	if tr.Location != nil {
+		res.File, res.Row = tr.Location.File, tr.Location.Row
+	}
+
+	switch {
+	case tr.Skip:
+		res.Outcome = OutcomeSkip
+		r.Skipped++
+	case tr.Error != nil:
+		res.Outcome = OutcomeError
+		res.Error = tr.Error.Error()
+		r.Errors++
+	case tr.Fail:
+		res.Outcome = OutcomeFail
+		r.Failed++
+	default:
+		res.Outcome = OutcomePass
+		r.Passed++
+	}
+
+	r.Results = append(r.Results, res)
+}
+
+// WriteJSON, writes the report as JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteText, writes the failed, errored and, when verbose, all test cases, followed by the
+// summary and the coverage per file.
+func (r *Report) WriteText(w io.Writer, verbose bool) error {
+	ew := &errWriter{w: w}
+
+	for _, res := range r.Results {
+		if res.Outcome == OutcomePass && !verbose {
+			continue
+		}
+
+		ew.printf("%s:%d: data.%s.%s: %s (%s)\n", res.File, res.Row, res.Package, res.Name,
+			strings.ToUpper(string(res.Outcome)), res.Duration.Round(time.Microsecond))
+
+		if res.Error != "" {
+			ew.printf("  %s\n", res.Error)
+		}
+
+		if res.Output != "" && (verbose || res.Outcome != OutcomePass) {
+			for line := range strings.Lines(res.Output) {
+				ew.printf("  %s", line)
+			}
+		}
+	}
+
+	total := len(r.Results)
+
+	ew.printf("--------------------------------------------------------------------------------\n")
+
+	for _, c := range []struct {
+		outcome Outcome
+		count   int
+	}{
+		{OutcomePass, r.Passed},
+		{OutcomeFail, r.Failed},
+		{OutcomeError, r.Errors},
+		{OutcomeSkip, r.Skipped},
+	} {
+		if c.count > 0 || c.outcome == OutcomePass {
+			ew.printf("%s: %d/%d\n", strings.ToUpper(string(c.outcome)), c.count, total)
+		}
+	}
+
+	if r.Coverage != nil {
+		files := make([]string, 0, len(r.Coverage.Files))
+		for file := range r.Coverage.Files {
+			files = append(files, file)
+		}
+
+		sort.Strings(files)
+
+		ew.printf("COVERAGE: %.2f%%\n", r.Coverage.Coverage)
+
+		for _, file := range files {
+			ew.printf("  %s: %.2f%%\n", file, r.Coverage.Files[file].Coverage)
+		}
+	}
+
+	return ew.err
+}
+
+// ignoreFilter, returns the loader filter of the ignore patterns.
+func ignoreFilter(ignore []string) loader.Filter {
+	return func(abspath string, info fs.FileInfo, depth int) bool {
+		for _, pattern := range ignore {
+			if loader.GlobExcludeName(pattern, 1)(abspath, info, depth) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
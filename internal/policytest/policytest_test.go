@@ -0,0 +1,141 @@
+package policytest_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/policytest"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/tester"
+	"github.com/open-policy-agent/opa/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policy = `package policy.documents
+
+default allowed = false
+
+allowed {
+	obj := ds.object({"object_type": "document", "object_id": input.resource.id})
+	obj.properties.public
+}
+
+owner = x {
+	x := input.resource.owner
+}
+`
+
+const policyTests = `package policy.documents_test
+
+import data.policy.documents
+
+test_public_allowed {
+	documents.allowed with input as {"resource": {"id": "public"}}
+}
+
+test_private_denied {
+	not documents.allowed with input as {"resource": {"id": "private"}}
+}
+
+test_private_allowed {
+	print("checking private")
+	documents.allowed with input as {"resource": {"id": "private"}}
+}
+`
+
+// objectBuiltin, a ds.object built-in returning a public document when the object id is "public".
+func objectBuiltin() *tester.Builtin {
+	decl := &rego.Function{
+		Name: "ds.object",
+		Decl: types.NewFunction(types.Args(types.A), types.A),
+	}
+
+	impl := func(_ rego.BuiltinContext, req *ast.Term) (*ast.Term, error) {
+		id, _ := req.Value.(ast.Object).Get(ast.StringTerm("object_id")).Value.(ast.String)
+
+		return ast.ObjectTerm(
+			ast.Item(ast.StringTerm("id"), ast.StringTerm(string(id))),
+			ast.Item(ast.StringTerm("properties"), ast.ObjectTerm(
+				ast.Item(ast.StringTerm("public"), ast.BooleanTerm(id == "public")),
+			)),
+		), nil
+	}
+
+	return &tester.Builtin{
+		Decl: &ast.Builtin{Name: decl.Name, Decl: decl.Decl},
+		Func: rego.Function1(decl, impl),
+	}
+}
+
+func policyDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "documents.rego"), []byte(policy), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "documents_test.rego"), []byte(policyTests), 0o600))
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	report, err := policytest.Run(t.Context(), &policytest.Options{
+		Paths:    []string{policyDir(t)},
+		Coverage: true,
+		Builtins: []*tester.Builtin{objectBuiltin()},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, report.OK())
+	assert.Equal(t, 2, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Results, 3)
+
+	for _, res := range report.Results {
+		assert.Equal(t, "policy.documents_test", res.Package)
+		assert.Equal(t, "documents_test.rego", filepath.Base(res.File))
+
+		if res.Name == "test_private_allowed" {
+			assert.Equal(t, policytest.OutcomeFail, res.Outcome)
+			assert.Equal(t, 13, res.Row)
+			assert.Contains(t, res.Output, "checking private")
+		}
+	}
+
+	require.NotNil(t, report.Coverage)
+	assert.Greater(t, report.Coverage.Coverage, 0.0)
+	assert.Less(t, report.Coverage.Coverage, 100.0)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf, false))
+	assert.Contains(t, buf.String(), "data.policy.documents_test.test_private_allowed: FAIL")
+	assert.NotContains(t, buf.String(), "test_public_allowed")
+	assert.Contains(t, buf.String(), "PASS: 2/3")
+	assert.Contains(t, buf.String(), "FAIL: 1/3")
+	assert.Contains(t, buf.String(), "COVERAGE: ")
+}
+
+func TestRunFilter(t *testing.T) {
+	report, err := policytest.Run(t.Context(), &policytest.Options{
+		Paths:    []string{policyDir(t)},
+		Run:      "test_public",
+		Builtins: []*tester.Builtin{objectBuiltin()},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.OK())
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "test_public_allowed", report.Results[0].Name)
+	assert.Nil(t, report.Coverage)
+}
+
+func TestRunUndeclaredBuiltin(t *testing.T) {
+	_, err := policytest.Run(t.Context(), &policytest.Options{
+		Paths: []string{policyDir(t)},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ds.object")
+}
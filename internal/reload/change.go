@@ -0,0 +1,33 @@
+package reload
+
+// Change, a prepared change of a live section, the changes of a reload are prepared first, and
+// committed when all of them are prepared, or discarded when one fails, so a configuration which
+// fails to apply leaves the running configuration unchanged.
+type Change struct {
+	commit  func()
+	discard func()
+}
+
+// NewChange, returns a change, which commit replaces the running component, and which discard
+// releases the prepared component, either function can be nil.
+func NewChange(commit, discard func()) *Change {
+	return &Change{commit: commit, discard: discard}
+}
+
+// Commit, commits the changes, in order.
+func Commit(changes ...*Change) {
+	for _, c := range changes {
+		if c != nil && c.commit != nil {
+			c.commit()
+		}
+	}
+}
+
+// Discard, discards the changes, in order.
+func Discard(changes ...*Change) {
+	for _, c := range changes {
+		if c != nil && c.discard != nil {
+			c.discard()
+		}
+	}
+}
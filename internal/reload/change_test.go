@@ -0,0 +1,26 @@
+package reload_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/reload"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChange(t *testing.T) {
+	var calls []string
+
+	changes := []*reload.Change{
+		reload.NewChange(func() { calls = append(calls, "commit auth") }, func() { calls = append(calls, "discard auth") }),
+		reload.NewChange(func() { calls = append(calls, "commit jwt") }, nil),
+		nil,
+	}
+
+	reload.Commit(changes...)
+	assert.Equal(t, []string{"commit auth", "commit jwt"}, calls)
+
+	calls = nil
+
+	reload.Discard(changes...)
+	assert.Equal(t, []string{"discard auth"}, calls)
+}
@@ -0,0 +1,116 @@
+package reload
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const pathSeparator = "."
+
+// document, returns the JSON representation of the configuration, the paths of the changes
+// are the paths of the JSON keys.
+func document(cfg any) (map[string]any, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal configuration")
+	}
+
+	doc := map[string]any{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal configuration")
+	}
+
+	return doc, nil
+}
+
+// Changes, returns the sorted paths of the values which differ between the documents, a value
+// which is not an object, an array included, is compared as a whole.
+func Changes(prev, next map[string]any) []string {
+	var changes []string
+
+	var walk func(path []string, prev, next map[string]any)
+
+	walk = func(path []string, prev, next map[string]any) {
+		keys := make([]string, 0, len(prev)+len(next))
+
+		for k := range prev {
+			keys = append(keys, k)
+		}
+
+		for k := range next {
+			if _, ok := prev[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+
+		for _, k := range keys {
+			p := append(slices.Clone(path), k)
+
+			prevObj, prevOK := prev[k].(map[string]any)
+			nextObj, nextOK := next[k].(map[string]any)
+
+			switch {
+			case prevOK && nextOK:
+				walk(p, prevObj, nextObj)
+			case !reflect.DeepEqual(prev[k], next[k]):
+				changes = append(changes, strings.Join(p, pathSeparator))
+			}
+		}
+	}
+
+	walk(nil, prev, next)
+
+	slices.Sort(changes)
+
+	return changes
+}
+
+// Split, splits the changes in the sections which are applied live, the live sections matching
+// the changes, and the changes which require a restart. A live section is a path prefix, in
+// which a * segment matches any key.
+func Split(changes, live []string) (applied, restart []string) {
+	for _, change := range changes {
+		section, ok := match(change, live)
+		if !ok {
+			restart = append(restart, change)
+			continue
+		}
+
+		if !slices.Contains(applied, section) {
+			applied = append(applied, section)
+		}
+	}
+
+	return applied, restart
+}
+
+// match, returns the first live section which is a prefix of the path.
+func match(path string, live []string) (string, bool) {
+	segments := strings.Split(path, pathSeparator)
+
+	for _, section := range live {
+		prefix := strings.Split(section, pathSeparator)
+		if len(prefix) > len(segments) {
+			continue
+		}
+
+		matched := true
+
+		for i, s := range prefix {
+			if s != "*" && s != segments[i] {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return section, true
+		}
+	}
+
+	return "", false
+}
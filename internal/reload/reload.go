@@ -0,0 +1,221 @@
+// Package reload, reloads the configuration of a running process, on demand, on a signal or on a
+// change of the configuration file.
+//
+// A reload validates the configuration, applies the changes of the live sections, the sections
+// which can be replaced without a restart, and reports the changes which require a restart. A
+// configuration which fails to load, or to apply, leaves the running configuration unchanged.
+package reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type State string
+
+const (
+	StateOK        State = "ok"
+	StateUnchanged State = "unchanged"
+	StateError     State = "error"
+)
+
+// Triggers of a reload.
+const (
+	TriggerSignal = "signal"
+	TriggerFile   = "file"
+)
+
+const revisionLen = 12
+
+// Status, the version of the running configuration and the result of the last reload.
+type Status struct {
+	// Version, version of the running configuration, incremented by each reload which changed the configuration.
+	Version int `json:"version"`
+	// Revision, hash of the running configuration.
+	Revision string `json:"revision"`
+	// LoadedAt, time the running configuration was loaded.
+	LoadedAt time.Time `json:"loaded_at"`
+	// RestartRequired, the changes of the running configuration which are applied by a restart.
+	RestartRequired []string `json:"restart_required,omitempty"`
+	// LastReload, the result of the last reload.
+	LastReload *Result `json:"last_reload,omitempty"`
+}
+
+// Result, the result of a reload.
+type Result struct {
+	Trigger string    `json:"trigger"`
+	Time    time.Time `json:"time"`
+	State   State     `json:"state"`
+	// Applied, the live sections which were applied.
+	Applied []string `json:"applied,omitempty"`
+	// RestartRequired, the changes which are applied by a restart.
+	RestartRequired []string `json:"restart_required,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// LoadFunc, loads and validates the configuration.
+type LoadFunc[T any] func() (T, error)
+
+// ApplyFunc, applies the changed live sections of the configuration.
+type ApplyFunc[T any] func(ctx context.Context, cfg T, sections []string) error
+
+// Reloader, reloads the configuration, and applies the changes of the live sections.
+type Reloader[T any] struct {
+	logger *zerolog.Logger
+	load   LoadFunc[T]
+	apply  ApplyFunc[T]
+	live   []string
+
+	// reloads are serialized.
+	reloadMu sync.Mutex
+	// started, the configuration the process started with, the reference of the changes which require a restart.
+	started map[string]any
+	// current, the last applied configuration, the reference of the changes of the live sections.
+	current map[string]any
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New, returns the reloader of the running configuration cfg, the live sections are the paths of
+// the JSON representation of the configuration which are applied by the apply function, in which
+// a * segment matches any key.
+func New[T any](logger *zerolog.Logger, cfg T, load LoadFunc[T], apply ApplyFunc[T], live ...string) (*Reloader[T], error) {
+	rLogger := logger.With().Str("component", "reload").Logger()
+
+	doc, err := document(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reloader[T]{
+		logger:  &rLogger,
+		load:    load,
+		apply:   apply,
+		live:    live,
+		started: doc,
+		current: doc,
+		status: Status{
+			Version:  1,
+			Revision: revision(doc),
+			LoadedAt: time.Now().UTC(),
+		},
+	}, nil
+}
+
+// Status, returns a copy of the reload status.
+func (r *Reloader[T]) Status() *Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := r.status
+	status.RestartRequired = slices.Clone(r.status.RestartRequired)
+
+	return &status
+}
+
+// Reload, loads the configuration and applies the changes of the live sections, returns the result of the reload.
+func (r *Reloader[T]) Reload(ctx context.Context, trigger string) *Result {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	result := &Result{Trigger: trigger, Time: time.Now().UTC()}
+
+	next, doc, err := r.loadDocument()
+	if err != nil {
+		return r.failed(result, err)
+	}
+
+	changes := Changes(r.current, doc)
+	if len(changes) == 0 {
+		result.State = StateUnchanged
+		return r.record(result, nil)
+	}
+
+	applied, _ := Split(changes, r.live)
+	_, restart := Split(Changes(r.started, doc), r.live)
+
+	if len(applied) > 0 {
+		if err := r.apply(ctx, next, applied); err != nil {
+			return r.failed(result, err)
+		}
+	}
+
+	r.current = doc
+
+	result.State = StateOK
+	result.Applied = applied
+	result.RestartRequired = restart
+
+	r.logger.Info().Str("trigger", trigger).Strs("applied", applied).Msg("configuration reloaded")
+
+	if len(restart) > 0 {
+		r.logger.Warn().Strs("restart_required", restart).Msg("configuration changes require a restart")
+	}
+
+	return r.record(result, doc)
+}
+
+func (r *Reloader[T]) loadDocument() (T, map[string]any, error) {
+	next, err := r.load()
+	if err != nil {
+		return next, nil, err
+	}
+
+	doc, err := document(next)
+
+	return next, doc, err
+}
+
+func (r *Reloader[T]) failed(result *Result, err error) *Result {
+	result.State = StateError
+	result.Error = err.Error()
+
+	r.logger.Error().Err(err).Str("trigger", result.Trigger).Msg("configuration reload failed, keeping running configuration")
+
+	return r.record(result, nil)
+}
+
+// record, records the result of the reload, and the version of the reloaded configuration doc, when set.
+func (r *Reloader[T]) record(result *Result, doc map[string]any) *Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if doc != nil {
+		r.status.Version++
+		r.status.Revision = revision(doc)
+		r.status.LoadedAt = result.Time
+		r.status.RestartRequired = result.RestartRequired
+	}
+
+	r.status.LastReload = result
+
+	return result
+}
+
+// Handler, returns the HTTP handler of the reload status.
+func (r *Reloader[T]) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(r.Status()); err != nil {
+			r.logger.Error().Err(err).Msg("failed to write reload status")
+		}
+	})
+}
+
+// revision, returns the hash of the configuration document.
+func revision(doc map[string]any) string {
+	b, _ := json.Marshal(doc)
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])[:revisionLen]
+}
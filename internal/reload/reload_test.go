@@ -0,0 +1,203 @@
+package reload_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/reload"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Logging struct {
+		Level string `json:"level"`
+	} `json:"logging"`
+	Services map[string]*testService `json:"services"`
+	Listen   string                  `json:"listen"`
+}
+
+type testService struct {
+	Origins []string `json:"origins"`
+	Port    int      `json:"port"`
+}
+
+func newTestConfig() *testConfig {
+	cfg := &testConfig{Listen: ":8080"}
+	cfg.Logging.Level = "info"
+	cfg.Services = map[string]*testService{
+		"reader": {Origins: []string{"http://localhost"}, Port: 9292},
+	}
+
+	return cfg
+}
+
+var live = []string{"logging.level", "services.*.origins"}
+
+func TestChanges(t *testing.T) {
+	prev := map[string]any{
+		"a": map[string]any{"b": 1.0, "c": []any{"x"}},
+		"d": "e",
+	}
+	next := map[string]any{
+		"a": map[string]any{"b": 1.0, "c": []any{"x", "y"}},
+		"f": true,
+	}
+
+	assert.Equal(t, []string{"a.c", "d", "f"}, reload.Changes(prev, next))
+	assert.Empty(t, reload.Changes(prev, prev))
+}
+
+func TestSplit(t *testing.T) {
+	applied, restart := reload.Split(
+		[]string{"listen", "logging.level", "services.reader.origins", "services.writer.origins", "services.writer.port"},
+		live,
+	)
+
+	assert.Equal(t, []string{"logging.level", "services.*.origins"}, applied)
+	assert.Equal(t, []string{"listen", "services.writer.port"}, restart)
+}
+
+func TestReload(t *testing.T) {
+	logger := zerolog.Nop()
+	next := newTestConfig()
+
+	var (
+		loadErr  error
+		applyErr error
+		applied  []string
+	)
+
+	r, err := reload.New(&logger, newTestConfig(),
+		func() (*testConfig, error) { return next, loadErr },
+		func(_ context.Context, _ *testConfig, sections []string) error {
+			applied = sections
+			return applyErr
+		},
+		live...,
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	start := r.Status()
+
+	// an unchanged configuration is not applied.
+	result := r.Reload(ctx, reload.TriggerSignal)
+	assert.Equal(t, reload.StateUnchanged, result.State)
+	assert.Nil(t, applied)
+
+	// live changes are applied, the other changes require a restart.
+	next.Logging.Level = "debug"
+	next.Listen = ":9090"
+
+	result = r.Reload(ctx, reload.TriggerSignal)
+	assert.Equal(t, reload.StateOK, result.State)
+	assert.Equal(t, []string{"logging.level"}, applied)
+	assert.Equal(t, []string{"listen"}, result.RestartRequired)
+
+	status := r.Status()
+	assert.Equal(t, start.Version+1, status.Version)
+	assert.NotEqual(t, start.Revision, status.Revision)
+	assert.Equal(t, []string{"listen"}, status.RestartRequired)
+
+	// the changes requiring a restart are reported until they are reverted.
+	next.Services["reader"].Origins = []string{"https://example.com"}
+
+	result = r.Reload(ctx, reload.TriggerFile)
+	assert.Equal(t, []string{"services.*.origins"}, applied)
+	assert.Equal(t, []string{"listen"}, result.RestartRequired)
+
+	// a configuration which fails to load or to apply is not recorded.
+	loadErr = errors.New("invalid configuration")
+
+	result = r.Reload(ctx, reload.TriggerFile)
+	assert.Equal(t, reload.StateError, result.State)
+	assert.Equal(t, "invalid configuration", result.Error)
+
+	loadErr, applyErr = nil, errors.New("failed to apply")
+	next.Logging.Level = "trace"
+
+	result = r.Reload(ctx, reload.TriggerFile)
+	assert.Equal(t, reload.StateError, result.State)
+
+	status = r.Status()
+	assert.Equal(t, start.Version+2, status.Version)
+	assert.Equal(t, result, status.LastReload)
+
+	// the failed changes are applied again by the next reload.
+	applyErr = nil
+
+	result = r.Reload(ctx, reload.TriggerFile)
+	assert.Equal(t, reload.StateOK, result.State)
+	assert.Equal(t, []string{"logging.level"}, applied)
+}
+
+func TestWatch(t *testing.T) {
+	logger := zerolog.Nop()
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	write := func(cfg *testConfig) {
+		b, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, b, 0o600))
+	}
+
+	cfg := newTestConfig()
+	write(cfg)
+
+	r, err := reload.New(&logger, cfg,
+		func() (*testConfig, error) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			next := &testConfig{}
+
+			return next, json.Unmarshal(b, next)
+		},
+		func(context.Context, *testConfig, []string) error { return nil },
+		live...,
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	require.NoError(t, r.Watch(ctx, path, signals))
+
+	lastReload := func(trigger string, state reload.State) func() bool {
+		return func() bool {
+			last := r.Status().LastReload
+			return last != nil && last.Trigger == trigger && last.State == state
+		}
+	}
+
+	signals <- syscall.SIGHUP
+
+	assert.Eventually(t, lastReload(reload.TriggerSignal, reload.StateUnchanged), 5*time.Second, 10*time.Millisecond)
+
+	cfg.Logging.Level = "debug"
+	write(cfg)
+
+	assert.Eventually(t, lastReload(reload.TriggerFile, reload.StateOK), 5*time.Second, 10*time.Millisecond)
+
+	// the status is served as JSON.
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/config", http.NoBody))
+
+	status := reload.Status{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, 2, status.Version)
+	assert.Equal(t, []string{"logging.level"}, status.LastReload.Applied)
+}
@@ -0,0 +1,100 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+// debounce, delay between the last change of the configuration file and the reload.
+const debounce = 500 * time.Millisecond
+
+// Watch, reloads the configuration on each signal received from the signals channel, and after the
+// changes of the configuration file at path, when set, until the context is done.
+//
+// The directory of the configuration file is watched, so a file replaced by a rename, as written by
+// most editors, is reloaded as well.
+func (r *Reloader[T]) Watch(ctx context.Context, path string, signals <-chan os.Signal) error {
+	var events <-chan fsnotify.Event
+
+	var fsErrors <-chan error
+
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return errors.Wrapf(err, "invalid configuration file path %q", path)
+		}
+
+		path = abs
+
+		fsw, err := fsnotify.NewWatcher()
+		if err != nil {
+			return errors.Wrap(err, "failed to create configuration file watcher")
+		}
+
+		if err := fsw.Add(filepath.Dir(path)); err != nil {
+			_ = fsw.Close()
+			return errors.Wrapf(err, "failed to watch configuration file %q", path)
+		}
+
+		go func() {
+			<-ctx.Done()
+			_ = fsw.Close()
+		}()
+
+		events, fsErrors = fsw.Events, fsw.Errors
+	}
+
+	go r.watch(ctx, path, signals, events, fsErrors)
+
+	return nil
+}
+
+func (r *Reloader[T]) watch(
+	ctx context.Context,
+	path string,
+	signals <-chan os.Signal,
+	events <-chan fsnotify.Event,
+	fsErrors <-chan error,
+) {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case sig := <-signals:
+			r.logger.Info().Str("signal", sig.String()).Msg("configuration reload requested")
+			r.Reload(ctx, TriggerSignal)
+
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+
+			if event.Name == path && !event.Has(fsnotify.Chmod) {
+				timer.Reset(debounce)
+			}
+
+		case err, ok := <-fsErrors:
+			if !ok {
+				fsErrors = nil
+				continue
+			}
+
+			r.logger.Warn().Err(err).Msg("configuration file watcher error")
+
+		case <-timer.C:
+			r.Reload(ctx, TriggerFile)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/pkg/errors"
+)
+
+// BundleEvaluator, evaluates the decisions in-process, using a candidate policy bundle.
+type BundleEvaluator struct {
+	bundle *bundle.Bundle
+	opts   []func(*rego.Rego)
+
+	mu       sync.Mutex
+	prepared map[string]preparedQuery
+}
+
+type preparedQuery struct {
+	query rego.PreparedEvalQuery
+	err   error
+}
+
+var _ Evaluator = (*BundleEvaluator)(nil)
+
+// NewBundleEvaluator, loads the policy bundle (directory or bundle file), the options register
+// the custom built-ins used by the policy, like the directory built-ins.
+func NewBundleEvaluator(path string, opts ...func(*rego.Rego)) (*BundleEvaluator, error) {
+	// topaz evaluates policies using rego v0, unless the bundle manifest states otherwise.
+	b, err := loader.NewFileLoader().WithRegoVersion(ast.RegoV0).AsBundle(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load bundle %q", path)
+	}
+
+	return &BundleEvaluator{
+		bundle:   b,
+		opts:     opts,
+		prepared: map[string]preparedQuery{},
+	}, nil
+}
+
+func (e *BundleEvaluator) Eval(ctx context.Context, path string, decisions []string, input map[string]any) (map[string]bool, error) {
+	query, err := e.prepare(ctx, path, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rs) == 0 {
+		return nil, errors.Errorf("undefined results: path=%s decisions=%v", path, decisions)
+	}
+
+	return Outcomes(rs[0].Bindings, decisions)
+}
+
+// prepare, returns the prepared query of the policy path and decisions, the query, or its error, is prepared once.
+func (e *BundleEvaluator) prepare(ctx context.Context, path string, decisions []string) (rego.PreparedEvalQuery, error) {
+	key := path + ":" + strings.Join(decisions, ",")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if p, ok := e.prepared[key]; ok {
+		return p.query, p.err
+	}
+
+	opts := append([]func(*rego.Rego){
+		rego.Query(Query(path, decisions)),
+		rego.ParsedBundle("candidate", e.bundle),
+		rego.SetRegoVersion(ast.RegoV0),
+	}, e.opts...)
+
+	query, err := rego.New(opts...).PrepareForEval(ctx)
+	e.prepared[key] = preparedQuery{query: query, err: err}
+
+	return query, err
+}
@@ -0,0 +1,177 @@
+// Package replay, replays the decisions recorded by the topaz file decision logger against a
+// candidate policy, and reports the changed outcomes grouped by policy path and decision.
+//
+// Only decisions recorded with their evaluated input, logger option capture_input, can be replayed.
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
+	"github.com/pkg/errors"
+)
+
+// AnnotationInput, decision log annotation containing the JSON encoded evaluated input of the decision.
+const AnnotationInput = topaz_file_decision_logger.AnnotationInput
+
+const maxLineSize = 16 * 1024 * 1024
+
+// Record, a decision log record, with the captured input of the decision.
+type Record struct {
+	ID          string            `json:"id"`
+	Path        string            `json:"path"`
+	Outcomes    map[string]bool   `json:"outcomes"`
+	Annotations map[string]string `json:"annotations"`
+	Input       map[string]any    `json:"-"`
+}
+
+// Decisions, returns the decisions of the record, in alphabetical order.
+func (r *Record) Decisions() []string {
+	decisions := make([]string, 0, len(r.Outcomes))
+	for d := range r.Outcomes {
+		decisions = append(decisions, d)
+	}
+
+	slices.Sort(decisions)
+
+	return decisions
+}
+
+// Files, returns the rotated backups of the decisions file, oldest first, followed by the decisions file.
+// Backups are named {name}-{timestamp}{ext}, or {name}-{timestamp}{ext}.gz when compressed.
+func Files(filename string) ([]string, error) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	// the backup timestamp format sorts chronologically.
+	slices.Sort(files)
+
+	if _, err := os.Stat(filename); err == nil {
+		files = append(files, filename)
+	}
+
+	if len(files) == 0 {
+		return nil, errors.Errorf("no decisions file %q", filename)
+	}
+
+	return files, nil
+}
+
+// Scan, calls fn for each record of the files, records without captured input are passed with a nil Input.
+func Scan(ctx context.Context, files []string, fn func(*Record) error) error {
+	for _, file := range files {
+		if err := scanFile(ctx, file, fn); err != nil {
+			return errors.Wrapf(err, "%s", file)
+		}
+	}
+
+	return nil
+}
+
+func scanFile(ctx context.Context, file string, fn func(*Record) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+
+	if strings.HasSuffix(file, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		rec, err := ParseRecord(scanner.Bytes())
+		if err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ParseRecord, parses a decision log line, either a decision, or a decision wrapped in the message field of a log entry.
+func ParseRecord(line []byte) (*Record, error) {
+	var entry struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil, errors.Wrap(err, "invalid decision record")
+	}
+
+	if entry.ID == "" && entry.Message != "" {
+		line = []byte(entry.Message)
+	}
+
+	rec := &Record{}
+	if err := json.Unmarshal(line, rec); err != nil {
+		return nil, errors.Wrap(err, "invalid decision record")
+	}
+
+	if input, ok := rec.Annotations[AnnotationInput]; ok {
+		if err := json.Unmarshal([]byte(input), &rec.Input); err != nil {
+			return nil, errors.Wrapf(err, "invalid input of decision %q", rec.ID)
+		}
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,198 @@
+package replay
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aserto-dev/topaz/internal/outcome"
+	"github.com/pkg/errors"
+)
+
+// Evaluator, evaluates the decisions of the policy path with the input, using the candidate policy.
+type Evaluator interface {
+	Eval(ctx context.Context, path string, decisions []string, input map[string]any) (map[string]bool, error)
+}
+
+// Report, the replay outcome, grouped by policy path and decision.
+type Report struct {
+	Records int      `json:"records"` // records read
+	Skipped int      `json:"skipped"` // records without captured input
+	Groups  []*Group `json:"groups"`
+}
+
+// Group, the replayed outcomes of a policy path and decision.
+type Group struct {
+	Path      string   `json:"path"`
+	Decision  string   `json:"decision"`
+	Evaluated int      `json:"evaluated"`
+	Unchanged int      `json:"unchanged"`
+	Granted   int      `json:"granted"` // recorded false, replayed true
+	Revoked   int      `json:"revoked"` // recorded true, replayed false
+	Errors    int      `json:"errors"`
+	Changed   []string `json:"changed,omitempty"` // ids of the changed decisions
+	Error     string   `json:"error,omitempty"`   // first evaluation error
+}
+
+// Replay, evaluates each record of the files with the evaluator, and reports the changed outcomes.
+func Replay(ctx context.Context, files []string, ev Evaluator) (*Report, error) {
+	report := &Report{}
+	groups := map[string]*Group{}
+
+	group := func(path, decision string) *Group {
+		key := path + "\x00" + decision
+
+		g, ok := groups[key]
+		if !ok {
+			g = &Group{Path: path, Decision: decision}
+			groups[key] = g
+		}
+
+		return g
+	}
+
+	err := Scan(ctx, files, func(rec *Record) error {
+		report.Records++
+
+		if rec.Input == nil || rec.Path == "" || len(rec.Outcomes) == 0 {
+			report.Skipped++
+			return nil
+		}
+
+		decisions := rec.Decisions()
+
+		outcomes, err := ev.Eval(ctx, rec.Path, decisions, rec.Input)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		for _, d := range decisions {
+			g := group(rec.Path, d)
+			g.Evaluated++
+
+			replayed, ok := outcomes[d]
+
+			switch {
+			case err != nil || !ok:
+				g.Errors++
+
+				if g.Error == "" && err != nil {
+					g.Error = err.Error()
+				}
+			case replayed == rec.Outcomes[d]:
+				g.Unchanged++
+			case replayed:
+				g.Granted++
+				g.Changed = append(g.Changed, rec.ID)
+			default:
+				g.Revoked++
+				g.Changed = append(g.Changed, rec.ID)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range groups {
+		report.Groups = append(report.Groups, g)
+	}
+
+	slices.SortFunc(report.Groups, func(a, b *Group) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Decision, b.Decision)
+	})
+
+	return report, nil
+}
+
+// Changed, returns the number of changed outcomes.
+func (r *Report) Changed() int {
+	n := 0
+	for _, g := range r.Groups {
+		n += g.Granted + g.Revoked
+	}
+
+	return n
+}
+
+// WriteJSON, writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteCSV, writes a row per policy path and decision.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"path", "decision", "evaluated", "unchanged", "granted", "revoked", "errors"}); err != nil {
+		return err
+	}
+
+	for _, g := range r.Groups {
+		row := []string{
+			g.Path,
+			g.Decision,
+			strconv.Itoa(g.Evaluated),
+			strconv.Itoa(g.Unchanged),
+			strconv.Itoa(g.Granted),
+			strconv.Itoa(g.Revoked),
+			strconv.Itoa(g.Errors),
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Query, returns the Rego query binding the decisions of the policy path to x0..xn,
+// the query of the authorizer Is evaluation.
+func Query(path string, decisions []string) string {
+	var sb strings.Builder
+
+	for i, d := range decisions {
+		fmt.Fprintf(&sb, "x%d = data.%s.%s\n", i, path, d)
+	}
+
+	return sb.String()
+}
+
+// Outcomes, returns the outcomes of the decisions bound to x0..xn by the Query, structured
+// decision outcomes are accepted, using the value of the allowed field.
+func Outcomes(bindings map[string]any, decisions []string) (map[string]bool, error) {
+	outcomes := make(map[string]bool, len(decisions))
+
+	for i, d := range decisions {
+		v, ok := bindings[fmt.Sprintf("x%d", i)]
+		if !ok {
+			return nil, errors.Errorf("undefined decision [%s]", d)
+		}
+
+		allowed, _, err := outcome.Parse(v, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decision [%s]", d)
+		}
+
+		outcomes[d] = allowed
+	}
+
+	return outcomes, nil
+}
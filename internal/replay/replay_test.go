@@ -0,0 +1,126 @@
+package replay_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policy = `package policy.documents
+
+import rego.v1
+
+default can_read := false
+
+can_read if input.user.id == input.resource.owner
+
+default can_write := false
+
+can_write if input.user.id == "admin"
+`
+
+// record, returns a decision log line, wrapped in the message field of a log entry, as written by the decision logger.
+func record(t *testing.T, id, user, owner string, canRead, canWrite bool) string {
+	t.Helper()
+
+	input, err := json.Marshal(map[string]any{
+		"user":     map[string]any{"id": user},
+		"resource": map[string]any{"owner": owner},
+	})
+	require.NoError(t, err)
+
+	decision, err := json.Marshal(map[string]any{
+		"id":          id,
+		"path":        "policy.documents",
+		"outcomes":    map[string]bool{"can_read": canRead, "can_write": canWrite},
+		"annotations": map[string]string{replay.AnnotationInput: string(input)},
+	})
+	require.NoError(t, err)
+
+	line, err := json.Marshal(map[string]string{"level": "", "message": string(decision)})
+	require.NoError(t, err)
+
+	return string(line) + "\n"
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	bundleDir := filepath.Join(dir, "bundle")
+	require.NoError(t, os.MkdirAll(bundleDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "policy.rego"), []byte(policy), 0o600))
+
+	logDir := filepath.Join(dir, "log")
+	require.NoError(t, os.MkdirAll(logDir, 0o700))
+
+	// compressed backup, recorded with a policy which allowed everyone to read.
+	var gz bytes.Buffer
+
+	zw := gzip.NewWriter(&gz)
+	_, err := zw.Write([]byte(record(t, "d1", "euang", "kris", true, false)))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "decisions-2025-01-01T00-00-00.000.json.gz"), gz.Bytes(), 0o600))
+
+	current := strings.Join([]string{
+		record(t, "d2", "euang", "euang", true, false),
+		record(t, "d3", "admin", "kris", false, false),
+		`{"id": "d4", "path": "policy.documents", "outcomes": {"can_read": true}}` + "\n",
+	}, "")
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, "decisions.json"), []byte(current), 0o600))
+
+	files, err := replay.Files(filepath.Join(logDir, "decisions.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.True(t, strings.HasSuffix(files[0], ".json.gz"))
+
+	ev, err := replay.NewBundleEvaluator(bundleDir)
+	require.NoError(t, err)
+
+	report, err := replay.Replay(ctx, files, ev)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Records)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 2, report.Changed())
+
+	require.Len(t, report.Groups, 2)
+
+	read := report.Groups[0]
+	assert.Equal(t, "can_read", read.Decision)
+	assert.Equal(t, 3, read.Evaluated)
+	assert.Equal(t, 1, read.Revoked)
+	assert.Equal(t, []string{"d1"}, read.Changed)
+
+	write := report.Groups[1]
+	assert.Equal(t, "can_write", write.Decision)
+	assert.Equal(t, 1, write.Granted)
+	assert.Equal(t, []string{"d3"}, write.Changed)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	assert.Equal(t, "path,decision,evaluated,unchanged,granted,revoked,errors\n"+
+		"policy.documents,can_read,3,2,0,1,0\n"+
+		"policy.documents,can_write,3,2,1,0,0\n", buf.String())
+}
+
+func TestReplayUndefinedDecision(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.rego"), []byte(policy), 0o600))
+
+	ev, err := replay.NewBundleEvaluator(dir)
+	require.NoError(t, err)
+
+	_, err = ev.Eval(context.Background(), "policy.documents", []string{"can_delete"}, map[string]any{})
+	require.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler, starts a server span for each HTTP request, continuing the trace of the W3C trace
+// context of the request headers, the gRPC calls of the gateway are children of the span. The
+// span is named by the method and the route of the request, the gateway route set by SetRoute,
+// or the pattern of the matching mux handler, the URL path is only recorded as an attribute.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := Tracer().Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rt := &route{}
+		req := r.WithContext(context.WithValue(ctx, routeKey{}, rt))
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rw, req)
+
+		if pattern := rt.pattern(req); pattern != "" {
+			span.SetName(r.Method + " " + pattern)
+			span.SetAttributes(attribute.String("http.route", pattern))
+		}
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+
+		if rw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.status))
+		}
+	})
+}
+
+// SetRoute, sets the route of the HTTP request of the context, the path pattern matched by the
+// gateway, used as the name of the server span of the request.
+func SetRoute(ctx context.Context, pattern string) {
+	if rt, ok := ctx.Value(routeKey{}).(*route); ok {
+		rt.set(pattern)
+	}
+}
+
+type routeKey struct{}
+
+// route, the route of a request, set by the gateway while the request is served.
+type route struct {
+	mu      sync.Mutex
+	gateway string
+}
+
+func (rt *route) set(pattern string) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.gateway = pattern
+}
+
+// pattern, returns the gateway route, or the pattern of the mux handler which served the request,
+// without the method and host of the pattern.
+func (rt *route) pattern(req *http.Request) string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.gateway != "" {
+		return rt.gateway
+	}
+
+	if i := strings.Index(req.Pattern, "/"); i >= 0 {
+		return req.Pattern[i:]
+	}
+
+	return ""
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush, flushes the underlying response writer, used by the streaming gateway responses.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap, returns the underlying response writer, used by http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
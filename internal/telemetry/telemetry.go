@@ -0,0 +1,151 @@
+// Package telemetry, OpenTelemetry distributed tracing, spans are exported over OTLP when tracing
+// is enabled, otherwise the global no-op tracer provider is used, the W3C trace context is always
+// propagated, so a caller's trace is continued by the downstream services.
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName, instrumentation scope name of the topaz spans.
+const TracerName = "github.com/aserto-dev/topaz"
+
+const (
+	DefaultEndpoint    = "localhost:4317"
+	DefaultServiceName = "topazd"
+	DefaultSampleRatio = 1.0
+
+	exportTimeout = 10 * time.Second
+)
+
+// Config, tracing configuration.
+type Config struct {
+	// Enabled, export spans over OTLP, the no-op tracer provider is used when disabled.
+	Enabled bool `json:"enabled"`
+	// Endpoint, OTLP gRPC endpoint (host:port) of the collector.
+	Endpoint string `json:"endpoint"`
+	// Insecure, connect to the collector without TLS.
+	Insecure bool `json:"insecure"`
+	// Headers, headers sent with each export request, like authentication headers of the collector.
+	Headers map[string]string `json:"headers,omitempty"`
+	// SampleRatio, ratio [0..1] of the sampled root spans, spans with a remote parent follow the parent's sampling decision.
+	SampleRatio float64 `json:"sample_ratio"`
+	// ServiceName, service.name resource attribute of the spans.
+	ServiceName string `json:"service_name"`
+}
+
+// Validate, validates the tracing configuration.
+func (c *Config) Validate() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.Errorf("sample_ratio %v must be between 0 and 1", c.SampleRatio)
+	}
+
+	return nil
+}
+
+// Setup, installs the W3C trace context propagator, and the OTLP exporting tracer provider when
+// tracing is enabled, the returned function flushes and shuts down the tracer provider.
+func Setup(ctx context.Context, cfg *Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(valueOrDefault(cfg.Endpoint, DefaultEndpoint)),
+		otlptracegrpc.WithTimeout(exportTimeout),
+	}
+
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create otlp trace exporter")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", valueOrDefault(cfg.ServiceName, DefaultServiceName)),
+		)),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// NewInMemory, installs a tracer provider recording all spans in memory, for tests, the returned
+// function restores the previous tracer provider and propagator.
+func NewInMemory() (*tracetest.InMemoryExporter, func()) {
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return exporter, func() {
+		_ = tp.Shutdown(context.Background())
+
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}
+}
+
+// Tracer, returns the tracer of the topaz spans, of the current global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start, starts an internal span, the span must be ended using End.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End, ends the span, recording the error, when set.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,198 @@
+package telemetry_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aserto-dev/topaz/internal/telemetry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentID    = "00f067aa0ba902b7"
+	traceparent = "00-" + traceID + "-" + parentID + "-01"
+)
+
+func TestServerSpanContinuesTrace(t *testing.T) {
+	exporter, cleanup := telemetry.NewInMemory()
+	t.Cleanup(cleanup)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+	info := &grpc.UnaryServerInfo{FullMethod: "/aserto.authorizer.v2.Authorizer/Is"}
+
+	_, err := telemetry.NewServerMiddleware().Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		_, span := telemetry.Start(ctx, "is.eval")
+		telemetry.End(span, nil)
+
+		return nil, status.Error(grpccodes.PermissionDenied, "denied")
+	})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	eval, server := spans[0], spans[1]
+	assert.Equal(t, "is.eval", eval.Name)
+	assert.Equal(t, "aserto.authorizer.v2.Authorizer/Is", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, traceID, server.SpanContext.TraceID().String())
+	assert.Equal(t, parentID, server.Parent.SpanID().String())
+	assert.Equal(t, server.SpanContext.SpanID(), eval.Parent.SpanID())
+	assert.Equal(t, codes.Error, server.Status.Code)
+}
+
+func TestClientInterceptorPropagates(t *testing.T) {
+	exporter, cleanup := telemetry.NewInMemory()
+	t.Cleanup(cleanup)
+
+	ctx, span := telemetry.Start(context.Background(), "ds.check")
+
+	var propagated string
+
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		propagated = md.Get("traceparent")[0]
+
+		return nil
+	}
+
+	err := telemetry.UnaryClientInterceptor()(ctx, "/aserto.directory.reader.v3.Reader/Check", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	telemetry.End(span, errors.New("boom"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	client := spans[0]
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind)
+	assert.Equal(t, "00-"+client.SpanContext.TraceID().String()+"-"+client.SpanContext.SpanID().String()+"-01", propagated)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
+
+func TestHandlerContinuesTrace(t *testing.T) {
+	exporter, cleanup := telemetry.NewInMemory()
+	t.Cleanup(cleanup)
+
+	h := telemetry.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, traceID, trace.SpanContextFromContext(r.Context()).TraceID().String())
+		telemetry.SetRoute(r.Context(), "/api/v2/authz/is")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/authz/is", nil)
+	req.Header.Set("traceparent", traceparent)
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /api/v2/authz/is", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
+
+func TestHandlerSpanRoute(t *testing.T) {
+	exporter, cleanup := telemetry.NewInMemory()
+	t.Cleanup(cleanup)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/data/", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/api/", func(_ http.ResponseWriter, r *http.Request) {
+		telemetry.SetRoute(r.Context(), "/api/v3/directory/object/{object_type}/{object_id}")
+	})
+
+	h := telemetry.Handler(mux)
+
+	for _, path := range []string{"/api/v3/directory/object/user/beth", "/v1/data/todo/allowed", "/unknown"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "GET /api/v3/directory/object/{object_type}/{object_id}", spans[0].Name)
+	assert.Equal(t, "GET /v1/data/", spans[1].Name)
+	assert.Equal(t, "GET", spans[2].Name)
+
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "url.path" {
+			assert.Equal(t, "/api/v3/directory/object/user/beth", attr.Value.AsString())
+		}
+	}
+}
+
+func TestStreamClientSpanEndsWithStream(t *testing.T) {
+	exporter, cleanup := telemetry.NewInMemory()
+	t.Cleanup(cleanup)
+
+	stream := &fakeClientStream{msgs: 2}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return stream, nil
+	}
+
+	desc := &grpc.StreamDesc{ServerStreams: true}
+
+	cs, err := telemetry.StreamClientInterceptor()(context.Background(), desc, nil, "/aserto.directory.exporter.v3.Exporter/Export", streamer)
+	require.NoError(t, err)
+
+	require.NoError(t, cs.RecvMsg(nil))
+	assert.Empty(t, exporter.GetSpans(), "span ended before the stream completed")
+
+	require.NoError(t, cs.RecvMsg(nil))
+	require.ErrorIs(t, cs.RecvMsg(nil), io.EOF)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	// a failed stream ends the span with an error.
+	stream = &fakeClientStream{err: status.Error(grpccodes.Unavailable, "gone")}
+
+	cs, err = telemetry.StreamClientInterceptor()(context.Background(), desc, nil, "/aserto.directory.exporter.v3.Exporter/Export", streamer)
+	require.NoError(t, err)
+	require.Error(t, cs.RecvMsg(nil))
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+
+	msgs int
+	err  error
+}
+
+func (s *fakeClientStream) RecvMsg(any) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.msgs == 0 {
+		return io.EOF
+	}
+
+	s.msgs--
+
+	return nil
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := telemetry.Setup(context.Background(), &telemetry.Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	require.Error(t, (&telemetry.Config{SampleRatio: 2}).Validate())
+}
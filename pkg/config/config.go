@@ -10,6 +10,11 @@ import (
 	"github.com/aserto-dev/runtime"
 	"github.com/aserto-dev/topaz/internal/certs"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
+	"github.com/aserto-dev/topaz/internal/metrics"
+	"github.com/aserto-dev/topaz/internal/telemetry"
+	"github.com/aserto-dev/topaz/topazd/authorizer/authzen"
+	"github.com/aserto-dev/topaz/topazd/authorizer/extauthz"
 	"github.com/aserto-dev/topaz/topazd/debug"
 	"github.com/aserto-dev/topaz/topazd/service/builder"
 	"github.com/pkg/errors"
@@ -37,16 +42,36 @@ type ServicesConfig struct {
 		ListenAddress string           `json:"listen_address"`
 		Certificates  client.TLSConfig `json:"certs"`
 		ZPages        bool             `json:"zpages"`
+		Labels        metrics.Labels   `json:"labels"`
 	} `json:"metrics"`
 	Services map[string]*builder.API `json:"services"`
 }
 
 type JWT struct {
-	AcceptableTimeSkewSeconds int      `json:"acceptable_time_skew_seconds"` // Duration which exp (Expiry) and nbf (Not Before) claims may differ
-	AllowedIssuers            []string `json:"allowed_issuers"`              // NOTE: if this list is empty the behavior defaults to InsecureWhitelist
-	CacheRefreshMinInterval   string   `json:"cache_refresh_min_interval"`
-	CacheRefreshMaxInterval   string   `json:"cache_refresh_max_interval"`
-	ExpectedAudience          string   `json:"expected_audience"`
+	AcceptableTimeSkewSeconds int          `json:"acceptable_time_skew_seconds"` // Duration which exp (Expiry) and nbf (Not Before) claims may differ
+	AllowedIssuers            []string     `json:"allowed_issuers"`              // NOTE: if this list is empty the behavior defaults to InsecureWhitelist
+	CacheRefreshMinInterval   string       `json:"cache_refresh_min_interval"`
+	CacheRefreshMaxInterval   string       `json:"cache_refresh_max_interval"`
+	ExpectedAudience          string       `json:"expected_audience"`
+	Issuers                   []*JWTIssuer `json:"issuers"` // per issuer claim mapping, validation and static key sources
+}
+
+// JWTIssuer, per issuer token validation and identity resolution configuration.
+//
+// When the issuer has a static key source (jwks_file or public_keys), tokens are verified
+// using the static keys, otherwise the keys are retrieved using OIDC discovery.
+type JWTIssuer struct {
+	Issuer         string   `json:"issuer"`
+	SubjectClaim   string   `json:"subject_claim"`   // claim, or dot separated nested claim path, used as subject (default sub)
+	RequiredClaims []string `json:"required_claims"` // claims which must be present in the token
+	Algorithms     []string `json:"algorithms"`      // allowed signing algorithms, when empty any algorithm of the key set is allowed
+	JWKSFile       string   `json:"jwks_file"`       // path of a static JWKS file
+	PublicKeys     []string `json:"public_keys"`     // static PEM encoded public keys
+}
+
+// HasStaticKeys, returns true when the issuer keys are provided in the configuration.
+func (i *JWTIssuer) HasStaticKeys() bool {
+	return i.JWKSFile != "" || len(i.PublicKeys) > 0
 }
 
 func (j *JWT) AcceptableTimeSkewDuration() time.Duration {
@@ -76,6 +101,39 @@ func (j *JWT) CacheRefreshMaxIntervalDuration() time.Duration {
 	return d
 }
 
+// IdentityCache, identity to user resolution cache configuration.
+type IdentityCache struct {
+	Disabled bool   `json:"disabled"`
+	Size     int    `json:"size"` // maximum number of cached identities
+	TTL      string `json:"ttl"`  // duration string, time to live of a cached identity
+}
+
+const (
+	defaultIdentityCacheSize = 10000
+	defaultIdentityCacheTTL  = 60 * time.Second
+)
+
+// MaxSize, returns the maximum number of cached identities, zero when the cache is disabled.
+func (c *IdentityCache) MaxSize() int {
+	switch {
+	case c.Disabled:
+		return 0
+	case c.Size <= 0:
+		return defaultIdentityCacheSize
+	default:
+		return c.Size
+	}
+}
+
+func (c *IdentityCache) TTLDuration() time.Duration {
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil || d <= 0 {
+		return defaultIdentityCacheTTL
+	}
+
+	return d
+}
+
 // Common holds the shared configuration elements.
 type Common struct {
 	Version      int           `json:"version"`
@@ -90,6 +148,9 @@ type Common struct {
 
 	JWT JWT `json:"jwt"`
 
+	// Authorizer identity to user resolution cache configuration
+	IdentityCache IdentityCache `json:"identity_cache"`
+
 	// Directory configuration
 	Edge directory.Config `json:"directory"`
 
@@ -98,6 +159,106 @@ type Common struct {
 
 	// Default OPA configuration
 	OPA runtime.Config `json:"opa"`
+
+	// Named policy instances, served side by side with the default OPA instance
+	Policies []*PolicyInstance `json:"policies,omitempty"`
+
+	// Shadow policy, evaluated for a sample of the Is requests, to compare a candidate bundle against live traffic
+	Shadow *ShadowPolicy `json:"shadow,omitempty"`
+
+	// Envoy external authorization service configuration, used when the ext_authz service is configured
+	ExtAuthz extauthz.Config `json:"ext_authz"`
+
+	// OPA compatible data API (/v1/data/{path}) served by the authorizer gateway
+	DataAPI DataAPI `json:"data_api"`
+
+	// Policy backed AuthZEN access evaluations served by the authorizer, instead of the directory
+	AuthZEN authzen.Config `json:"authzen"`
+
+	// OpenTelemetry tracing, spans are exported over OTLP when enabled
+	Tracing telemetry.Config `json:"tracing"`
+
+	// Rate limits and in-flight request limits of the gRPC calls, per caller and per method
+	RateLimit ratelimit.Config `json:"rate_limit"`
+}
+
+// DataAPI, OPA compatible data API configuration.
+type DataAPI struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PolicyInstance, a named policy instance with its own OPA configuration (bundle source,
+// decision logger settings), selected by name or by the policy path prefix of a request.
+type PolicyInstance struct {
+	Name       string         `json:"name"`
+	PathPrefix string         `json:"path_prefix"`
+	OPA        runtime.Config `json:"opa"`
+}
+
+// ShadowPolicy, a candidate policy evaluated asynchronously, with the input of a sampled fraction
+// of the Is requests served by the shadowed policy instance, divergent decisions are logged to a
+// separate divergence log.
+type ShadowPolicy struct {
+	Policy      string         `json:"policy"`       // shadowed policy instance, defaults to the default instance
+	SampleRate  float64        `json:"sample_rate"`  // fraction [0..1] of the requests evaluated by the shadow policy
+	MaxInflight int            `json:"max_inflight"` // maximum number of pending shadow evaluations, sampled requests are dropped when reached
+	Timeout     string         `json:"timeout"`      // duration string, timeout of a shadow evaluation
+	Log         ShadowLog      `json:"divergence_log"`
+	OPA         runtime.Config `json:"opa"`
+}
+
+// ShadowLog, rotating file log of the divergent decisions.
+type ShadowLog struct {
+	Filename   string `json:"filename"`
+	MaxSize    int    `json:"max_size"` // megabytes
+	MaxAge     int    `json:"max_age"`  // days
+	MaxBackups int    `json:"max_backups"`
+}
+
+const (
+	defaultShadowMaxInflight  = 64
+	defaultShadowTimeout      = 5 * time.Second
+	defaultShadowLogFilename  = "shadow-decisions.json"
+	defaultShadowLogMaxSize   = 50
+	defaultShadowLogMaxBackup = 2
+)
+
+// Inflight, returns the maximum number of pending shadow evaluations.
+func (s *ShadowPolicy) Inflight() int {
+	if s.MaxInflight <= 0 {
+		return defaultShadowMaxInflight
+	}
+
+	return s.MaxInflight
+}
+
+// TimeoutDuration, returns the timeout of a shadow evaluation.
+func (s *ShadowPolicy) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(s.Timeout)
+	if err != nil || d <= 0 {
+		return defaultShadowTimeout
+	}
+
+	return d
+}
+
+// LogConfig, returns the divergence log configuration, with defaults applied.
+func (s *ShadowPolicy) LogConfig() ShadowLog {
+	log := s.Log
+
+	if log.Filename == "" {
+		log.Filename = defaultShadowLogFilename
+	}
+
+	if log.MaxSize <= 0 {
+		log.MaxSize = defaultShadowLogMaxSize
+	}
+
+	if log.MaxBackups <= 0 {
+		log.MaxBackups = defaultShadowLogMaxBackup
+	}
+
+	return log
 }
 
 // LoggerConfig is a basic Config copy that gets loaded before everything else,
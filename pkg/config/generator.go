@@ -67,6 +67,11 @@ func (g *Generator) WithResource(resource string) *Generator {
 	return g
 }
 
+func (g *Generator) WithPolicyDir(dir string) *Generator {
+	g.PolicyDir = dir
+	return g
+}
+
 func (g *Generator) WithEdgeDirectory(enabled bool) *Generator {
 	g.EdgeDirectory = enabled
 	return g
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aserto-dev/topaz/internal/metrics"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/aserto-dev/topaz/topaz/cc"
 	"github.com/aserto-dev/topaz/topaz/x"
 	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
@@ -65,6 +67,12 @@ func LoadConfiguration(fileName string) (*Loader, error) {
 	v.SetDefault("remote_directory.address", "0.0.0.0:9292")
 	v.SetDefault("remote_directory.insecure", "true")
 
+	v.SetDefault("api.metrics.labels.max_values", metrics.DefaultMaxLabelValues)
+
+	v.SetDefault("tracing.endpoint", telemetry.DefaultEndpoint)
+	v.SetDefault("tracing.sample_ratio", telemetry.DefaultSampleRatio)
+	v.SetDefault("tracing.service_name", telemetry.DefaultServiceName)
+
 	v.AutomaticEnv()
 
 	fileContents, err := os.ReadFile(fileName)
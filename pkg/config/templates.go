@@ -14,10 +14,13 @@ type templateParams struct {
 	RegistryService   string //
 	RegistryImage     string //
 	RegistryTag       string //
+	PolicyDir         string // local policy directory, watched by topazd.
 }
 
 const LocalImageTemplate string = templatePreamble + opaLocalPolicyImage + topazFileDecisionLoggerPlugin + asertoEdgePlugin
 
+const LocalDirTemplate string = templatePreamble + opaLocalPolicyDir + topazFileDecisionLoggerPlugin + asertoEdgePlugin
+
 const RemoteImageTemplate string = templatePreamble + opaRemotePolicyImage + topazFileDecisionLoggerPlugin + asertoEdgePlugin
 
 const templatePreamble string = `# yaml-language-server: $schema=https://topaz.sh/schema/config.json
@@ -58,6 +61,11 @@ jwt:
   cache_refresh_max_interval: 15m # set as default, 15 minutes
   expected_audience: "" # set as default, empty
 
+# identity to user resolution cache configuration
+identity_cache:
+  size: 10000 # set as default, 10K identities
+  ttl: 60s    # set as default, 60 seconds
+
 # authentication configuration
 auth:
   keys:
@@ -346,6 +354,24 @@ opa:
     plugins:
 `
 
+const opaLocalPolicyDir string = `
+opa:
+  instance_id: "-"
+  graceful_shutdown_period_seconds: 2
+  # max_plugin_wait_time_seconds: 30 set as default
+  local_bundles:
+    # the policy directory is compiled on every change, a policy which fails to compile
+    # does not replace the last good policy, see the policy status of the authorizer.
+    paths:
+      - '{{ .PolicyDir }}'
+    watch: true
+    skip_verification: true
+  config:
+    decision_logs:
+      console: false
+    plugins:
+`
+
 const opaRemotePolicyImage string = `
 opa:
   instance_id: "-"
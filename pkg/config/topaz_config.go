@@ -1,8 +1,12 @@
 package config
 
 import (
+	"slices"
 	"strings"
 
+	"github.com/aserto-dev/topaz/topazd/authentication/bearer"
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/aserto-dev/topaz/topazd/authentication/mtls"
 	"github.com/rs/zerolog/log"
 
 	"github.com/pkg/errors"
@@ -20,6 +24,22 @@ type AuthnConfig struct {
 	APIKeys map[string]string `json:"api_keys"`
 	Options CallOptions       `json:"options"`
 	Keys    []string          `json:"keys"`
+	// ScopedKeys, named API keys, limited to a list of services or methods, with optional hashed values and expiry.
+	ScopedKeys []*keys.Key `json:"scoped_keys"`
+	// MTLS, client certificate authentication, mapping verified client certificates to caller identities.
+	MTLS mtls.Config `json:"mtls"`
+	// Bearer, JWT bearer token authentication, scoped by rules on the token claims.
+	Bearer bearer.Config `json:"bearer"`
+}
+
+// HasKeys, returns true when API keys or scoped API keys are configured.
+func (c *AuthnConfig) HasKeys() bool {
+	return len(c.APIKeys) > 0 || len(c.ScopedKeys) > 0
+}
+
+// Required, returns true when API keys, client certificates or bearer tokens authenticate the calls.
+func (c *AuthnConfig) Required() bool {
+	return c.HasKeys() || c.MTLS.Enabled || c.Bearer.Enabled
 }
 
 func (c *AuthnConfig) transposeKeys() {
@@ -87,16 +107,66 @@ func (c *Config) validation() error {
 		if len(c.OPA.Config.Bundles) > 1 {
 			return errors.New("opa.config.bundles - too many bundles")
 		}
+
+		if err := c.validatePolicies(); err != nil {
+			return err
+		}
+
+		if err := c.validateShadow(); err != nil {
+			return err
+		}
 	}
 
 	if len(c.APIConfig.Services) == 0 {
 		return errors.New("no api services configured")
 	}
 
+	if _, ok := c.APIConfig.Services["ext_authz"]; ok {
+		if _, ok := c.APIConfig.Services["authorizer"]; !ok {
+			return errors.New("ext_authz service needs the authorizer service to be configured")
+		}
+	}
+
+	if c.AuthZEN.Enabled {
+		if _, ok := c.APIConfig.Services["authorizer"]; !ok {
+			return errors.New("authzen needs the authorizer service to be configured")
+		}
+	}
+
+	if err := c.validateJWTIssuers(); err != nil {
+		return err
+	}
+
+	if err := c.Tracing.Validate(); err != nil {
+		return errors.Wrap(err, "tracing")
+	}
+
+	if err := c.APIConfig.Metrics.Labels.Validate(); err != nil {
+		return errors.Wrap(err, "api.metrics.labels")
+	}
+
+	if err := c.RateLimit.Validate(); err != nil {
+		return errors.Wrap(err, "rate_limit")
+	}
+
 	setDefaultCallsAuthz(c)
 	c.Auth.transposeKeys()
 
-	if len(c.Auth.APIKeys) > 0 {
+	if _, err := keys.New(c.Auth.ScopedKeys, c.Auth.APIKeys); err != nil {
+		return errors.Wrap(err, "auth")
+	}
+
+	if c.Auth.MTLS.Enabled {
+		if _, err := mtls.New(&c.Auth.MTLS, nil); err != nil {
+			return errors.Wrap(err, "auth.mtls")
+		}
+	}
+
+	if err := c.Auth.Bearer.Validate(); err != nil {
+		return errors.Wrap(err, "auth.bearer")
+	}
+
+	if c.Auth.Required() {
 		c.Auth.Options.Default.EnableAPIKey = true
 	} else {
 		c.Auth.Options.Default.EnableAnonymous = true
@@ -105,6 +175,96 @@ func (c *Config) validation() error {
 	return nil
 }
 
+// validatePolicies, validates the named policy instances, the instance_id of a policy instance defaults to its name.
+func (c *Config) validatePolicies() error {
+	names := map[string]bool{c.OPA.InstanceID: true}
+
+	for i, policy := range c.Policies {
+		if policy == nil || policy.Name == "" {
+			return errors.Errorf("policies[%d].name not set", i)
+		}
+
+		if names[policy.Name] {
+			return errors.Errorf("policies[%d].name %q is not unique", i, policy.Name)
+		}
+
+		names[policy.Name] = true
+
+		if len(policy.OPA.Config.Bundles) > 1 {
+			return errors.Errorf("policies[%d].opa.config.bundles - too many bundles", i)
+		}
+
+		if policy.OPA.InstanceID == "" {
+			policy.OPA.InstanceID = policy.Name
+		}
+
+		if policy.OPA.MaxPluginWaitTimeSeconds == 0 {
+			policy.OPA.MaxPluginWaitTimeSeconds = c.OPA.MaxPluginWaitTimeSeconds
+		}
+	}
+
+	return nil
+}
+
+// validateShadow, validates the shadow policy, the shadowed policy instance must exist.
+func (c *Config) validateShadow() error {
+	if c.Shadow == nil {
+		return nil
+	}
+
+	if c.Shadow.SampleRate < 0 || c.Shadow.SampleRate > 1 {
+		return errors.Errorf("shadow.sample_rate %v not in range [0..1]", c.Shadow.SampleRate)
+	}
+
+	if c.Shadow.Policy != "" && !slices.Contains(c.PolicyInstanceNames(), c.Shadow.Policy) {
+		return errors.Errorf("shadow.policy %q is not a policy instance", c.Shadow.Policy)
+	}
+
+	if len(c.Shadow.OPA.Config.Bundles) > 1 {
+		return errors.New("shadow.opa.config.bundles - too many bundles")
+	}
+
+	if c.Shadow.OPA.InstanceID == "" {
+		c.Shadow.OPA.InstanceID = "shadow"
+	}
+
+	if c.Shadow.OPA.MaxPluginWaitTimeSeconds == 0 {
+		c.Shadow.OPA.MaxPluginWaitTimeSeconds = c.OPA.MaxPluginWaitTimeSeconds
+	}
+
+	return nil
+}
+
+// validateJWTIssuers, validates the per issuer jwt configuration.
+func (c *Config) validateJWTIssuers() error {
+	issuers := map[string]bool{}
+
+	for i, issuer := range c.JWT.Issuers {
+		if issuer == nil || issuer.Issuer == "" {
+			return errors.Errorf("jwt.issuers[%d].issuer not set", i)
+		}
+
+		if issuers[issuer.Issuer] {
+			return errors.Errorf("jwt.issuers[%d].issuer %q is not unique", i, issuer.Issuer)
+		}
+
+		issuers[issuer.Issuer] = true
+	}
+
+	return nil
+}
+
+// PolicyInstanceNames, returns the names of the policy instances, starting with the default instance.
+func (c *Config) PolicyInstanceNames() []string {
+	names := []string{c.OPA.InstanceID}
+
+	for _, policy := range c.Policies {
+		names = append(names, policy.Name)
+	}
+
+	return names
+}
+
 func setDefaultCallsAuthz(cfg *Config) {
 	if len(cfg.Auth.Options.Overrides) == 0 {
 		infoPath := OptionOverrides{
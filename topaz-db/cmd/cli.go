@@ -1,31 +1,74 @@
 package cmd
 
-import dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
+import (
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
+)
 
 type CLI struct {
-	Init InitCmd `cmd:"" help:"create new database file"`
-	Set  SetCmd  `cmd:"" help:"set manifest"`
-	Load LoadCmd `cmd:"" help:"load data"`
-	Sync SyncCmd `cmd:"" help:"sync data"`
+	Init    InitCmd    `cmd:"" help:"create new database file"`
+	Set     SetCmd     `cmd:"" help:"set manifest"`
+	Load    LoadCmd    `cmd:"" help:"load data"`
+	Sync    SyncCmd    `cmd:"" help:"sync data"`
+	Rekey   RekeyCmd   `cmd:"" help:"rotate encryption keys, encrypts a plaintext database file"`
+	Recount RecountCmd `cmd:"" help:"recompute object and relation counters"`
+}
+
+type EncryptionFlags struct {
+	KeyFile string `flag:"" name:"key-file" type:"path" help:"key encryption key file (base64 encoded 32 byte key)"`
+	KeyEnv  string `flag:"" name:"key-env" help:"environment variable containing the key encryption key"`
 }
 
 type InitCmd struct {
+	EncryptionFlags
+
 	DBFile string `arg:"" help:"db file name"`
 }
 
 type SetCmd struct {
+	EncryptionFlags
+
 	DBFile   string `arg:"" help:"db file name" type:"existingfile"`
 	Manifest string `arg:"" help:"manifest file path" type:"existingfile"`
 }
 
 type LoadCmd struct {
+	EncryptionFlags
+
 	DBFile  string `arg:"" help:"db file name" type:"existingfile"`
 	DataDir string `arg:"" help:"data file directory" type:"existingdir"`
 }
 
 type SyncCmd struct {
 	dsc.Config
+	EncryptionFlags
 
 	DBFile string   `arg:"" help:"db file name" type:"existingfile"`
 	Mode   []string `flag:"" short:"m" enum:"manifest,full,diff,watermark" required:"" help:"sync mode"`
 }
+
+type RekeyCmd struct {
+	EncryptionFlags
+
+	DBFile     string `arg:"" help:"db file name" type:"existingfile"`
+	NewKeyFile string `flag:"" name:"new-key-file" type:"path" help:"new key encryption key file (base64 encoded 32 byte key)" xor:"new-key"`
+	NewKeyEnv  string `flag:"" name:"new-key-env" help:"environment variable containing the new key encryption key" xor:"new-key"`
+	DataKey    bool   `flag:"" name:"data-key" help:"rotate the data encryption key and re-encrypt all values"`
+}
+
+type RecountCmd struct {
+	EncryptionFlags
+
+	DBFile string `arg:"" help:"db file name" type:"existingfile"`
+}
+
+func (f *EncryptionFlags) Encryption() *bdb.EncryptionConfig {
+	if f.KeyFile == "" && f.KeyEnv == "" {
+		return nil
+	}
+
+	return &bdb.EncryptionConfig{
+		KeyFile: f.KeyFile,
+		KeyEnv:  f.KeyEnv,
+	}
+}
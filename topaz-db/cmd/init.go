@@ -31,6 +31,7 @@ func (cmd *InitCmd) Run(ctx context.Context) error {
 	cfg := &directory.Config{
 		DBPath:         cmd.DBFile,
 		RequestTimeout: requestTimeout,
+		Encryption:     cmd.Encryption(),
 	}
 
 	logger := zerolog.New(io.Discard)
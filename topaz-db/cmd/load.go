@@ -19,6 +19,7 @@ func (cmd *LoadCmd) Run(ctx context.Context) error {
 	cfg := &directory.Config{
 		DBPath:         cmd.DBFile,
 		RequestTimeout: requestTimeout,
+		Encryption:     cmd.Encryption(),
 	}
 
 	logger := zerolog.New(io.Discard)
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/aserto-dev/azm/stats"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	bolt "go.etcd.io/bbolt"
+)
+
+func (cmd *RecountCmd) Run(ctx context.Context) error {
+	logger := zerolog.New(os.Stderr).Level(zerolog.InfoLevel)
+
+	store, err := bdb.New(&bdb.Config{
+		DBPath:         cmd.DBFile,
+		RequestTimeout: requestTimeout,
+		Encryption:     cmd.Encryption(),
+	},
+		&logger,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Open(); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var s *stats.Stats
+
+	if err := store.DB().Update(func(tx *bolt.Tx) error {
+		var err error
+
+		s, err = ds.RecomputeCounters(ctx, tx)
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	var objCount, relCount int32
+
+	for _, ot := range s.ObjectTypes {
+		objCount += ot.ObjCount
+		relCount += ot.Count
+	}
+
+	log.Info().Str("db_file", cmd.DBFile).
+		Int("object_types", len(s.ObjectTypes)).
+		Int32("objects", objCount).
+		Int32("relations", relCount).
+		Msg("recount")
+
+	return nil
+}
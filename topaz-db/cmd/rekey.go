@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/aserto-dev/topaz/internal/eds/pkg/bdb"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func (cmd *RekeyCmd) Run(ctx context.Context) error {
+	newCfg := &bdb.EncryptionConfig{
+		KeyFile: cmd.NewKeyFile,
+		KeyEnv:  cmd.NewKeyEnv,
+	}
+
+	if !newCfg.Enabled() {
+		return errors.New("--new-key-file or --new-key-env must be specified")
+	}
+
+	newKEK, err := newCfg.Key()
+	if err != nil {
+		return err
+	}
+
+	var oldKEK []byte
+
+	if oldCfg := cmd.Encryption(); oldCfg.Enabled() {
+		if oldKEK, err = oldCfg.Key(); err != nil {
+			return err
+		}
+	}
+
+	logger := zerolog.New(os.Stderr).Level(zerolog.InfoLevel)
+
+	// open the store without encryption config, the data key is unwrapped by bdb.Rekey.
+	store, err := bdb.New(&bdb.Config{
+		DBPath:         cmd.DBFile,
+		RequestTimeout: requestTimeout,
+	},
+		&logger,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Open(); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	encrypted, err := bdb.IsEncrypted(store.DB())
+	if err != nil {
+		return err
+	}
+
+	if err := bdb.Rekey(store.DB(), oldKEK, newKEK, cmd.DataKey); err != nil {
+		return err
+	}
+
+	log.Info().Str("db_file", cmd.DBFile).Bool("encrypted_in_place", !encrypted).Bool("rotated_data_key", cmd.DataKey).Msg("rekey")
+
+	return nil
+}
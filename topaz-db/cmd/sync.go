@@ -39,6 +39,7 @@ func (cmd *SyncCmd) Run(ctx context.Context) error {
 	cfg := &directory.Config{
 		DBPath:         cmd.DBFile,
 		RequestTimeout: requestTimeout,
+		Encryption:     cmd.Encryption(),
 	}
 
 	logger := zerolog.New(os.Stderr).Level(zerolog.InfoLevel)
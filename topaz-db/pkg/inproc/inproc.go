@@ -9,6 +9,7 @@ import (
 	dsm "github.com/aserto-dev/go-directory/aserto/directory/model/v3"
 	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
 	dsw "github.com/aserto-dev/go-directory/aserto/directory/writer/v3"
+	dsa "github.com/authzen/access.go/api/access/v1"
 
 	"github.com/aserto-dev/topaz/internal/eds"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
@@ -43,6 +44,7 @@ func NewServer(ctx context.Context, logger *zerolog.Logger, cfg *directory.Confi
 	dsw.RegisterWriterServer(s, inProcDirectory.Writer3())
 	dse.RegisterExporterServer(s, inProcDirectory.Exporter3())
 	dsi.RegisterImporterServer(s, inProcDirectory.Importer3())
+	dsa.RegisterAccessServer(s, inProcDirectory.Access1())
 
 	go func() {
 		if err := s.Serve(listener); err != nil {
@@ -12,17 +12,22 @@ import (
 )
 
 type CertPaths struct {
-	Name string
-	Cert string
-	CA   string
-	Key  string
-	Dir  string
+	Name  string
+	Cert  string
+	CA    string
+	CAKey string // persisted to issue client certificates, when set.
+	Key   string
+	Dir   string
 }
 
 func (c *CertPaths) FindExisting() []string {
 	existing := []string{}
 
-	for _, cert := range []string{c.Cert, c.CA, c.Key} {
+	for _, cert := range []string{c.Cert, c.CA, c.CAKey, c.Key} {
+		if cert == "" {
+			continue
+		}
+
 		if fi, err := os.Stat(cert); !os.IsNotExist(err) && !fi.IsDir() {
 			existing = append(existing, cert)
 		}
@@ -75,16 +80,21 @@ func generate(dnsNames []string, certPaths ...*CertPaths) error {
 
 	for _, certPaths := range certPaths {
 		if err := generator.MakeDevCert(&certs.CertGenConfig{
-			CommonName:  certPaths.Name,
-			CertKeyPath: certPaths.Key,
-			CertPath:    certPaths.Cert,
-			CertCAPath:  certPaths.CA,
-			DNSNames:    dnsNames,
+			CommonName:    certPaths.Name,
+			CertKeyPath:   certPaths.Key,
+			CertPath:      certPaths.Cert,
+			CertCAPath:    certPaths.CA,
+			CertCAKeyPath: certPaths.CAKey,
+			DNSNames:      dnsNames,
 		}); err != nil {
 			return errors.Wrap(err, "failed to create dev certs")
 		}
 
 		data = append(data, []any{filepath.Base(certPaths.CA), generated})
+
+		if certPaths.CAKey != "" {
+			data = append(data, []any{filepath.Base(certPaths.CAKey), generated})
+		}
 		data = append(data, []any{filepath.Base(certPaths.Cert), generated})
 		data = append(data, []any{filepath.Base(certPaths.Key), generated})
 	}
@@ -10,6 +10,7 @@ import (
 	dsm "github.com/aserto-dev/go-directory/aserto/directory/model/v3"
 	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
 	dsw "github.com/aserto-dev/go-directory/aserto/directory/writer/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
 	"github.com/aserto-dev/topaz/topaz/clients"
 	dsa "github.com/authzen/access.go/api/access/v1"
 
@@ -37,6 +38,7 @@ type Client struct {
 	Importer dsi.ImporterClient
 	Exporter dse.ExporterClient
 	Access   dsa.AccessClient
+	Report   report.ReportClient
 }
 
 func New(conn *grpc.ClientConn) *Client {
@@ -48,6 +50,7 @@ func New(conn *grpc.ClientConn) *Client {
 		Importer: dsi.NewImporterClient(conn),
 		Exporter: dse.NewExporterClient(conn),
 		Access:   dsa.NewAccessClient(conn),
+		Report:   report.NewReportClient(conn),
 	}
 }
 
@@ -0,0 +1,69 @@
+package directory
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
+	"github.com/aserto-dev/topaz/topaz/jsonx"
+)
+
+const (
+	ReportFormatCSV   string = "csv"
+	ReportFormatJSONL string = "jsonl"
+)
+
+// ReportToFile, writes the access report rows to the writer, as CSV or JSONL.
+func (c *Client) ReportToFile(ctx context.Context, w io.Writer, req *report.AccessReportRequest, format string) error {
+	stream, err := c.Report.AccessReport(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	write, flush := reportWriter(w, format)
+
+	for {
+		row, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+func reportWriter(w io.Writer, format string) (func(*dsc.RelationIdentifier) error, func() error) {
+	if format == ReportFormatJSONL {
+		encoder := jsonx.NewEncoder(w)
+
+		return func(row *dsc.RelationIdentifier) error { return encoder.Encode(row) },
+			func() error { return nil }
+	}
+
+	writer := csv.NewWriter(w)
+
+	// the header is always written, write errors are buffered and surfaced by flush.
+	_ = writer.Write([]string{"object_type", "object_id", "permission", "subject_type", "subject_id"})
+
+	write := func(row *dsc.RelationIdentifier) error {
+		return writer.Write([]string{row.GetObjectType(), row.GetObjectId(), row.GetRelation(), row.GetSubjectType(), row.GetSubjectId()})
+	}
+
+	flush := func() error {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return write, flush
+}
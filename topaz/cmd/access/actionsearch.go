@@ -53,7 +53,11 @@ func (cmd *ActionSearchCmd) template() proto.Message {
 			Id:         "",
 			Properties: &structpb.Struct{},
 		},
-		Context: &structpb.Struct{},
+		Context: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				"include_relations": structpb.NewBoolValue(false),
+			},
+		},
 		Page: &dsa.PaginationRequest{
 			Token:      nil,
 			Limit:      nil,
@@ -11,10 +11,14 @@ type AuthorizerCmd struct {
 	Get           GetCmd          `cmd:"" help:"get policy"`
 	List          ListCmd         `cmd:"" help:"list policy"`
 	Test          TestCmd         `cmd:"" help:"execute authorizer assertions"`
+	Filter        FilterCmd       `cmd:"" help:"compile a query into a data filter"`
+	Batch         BatchCmd        `cmd:"" help:"evaluate policy decisions for many resources"`
+	Replay        ReplayCmd       `cmd:"" help:"replay recorded decisions against a candidate policy"`
 }
 
 type GetCmd struct {
 	Policy GetPolicyCmd `cmd:"" help:"get policy"`
+	Status GetStatusCmd `cmd:"" help:"get compile status of watched local policies"`
 }
 
 type ListCmd struct {
@@ -0,0 +1,62 @@
+package authorizer
+
+import (
+	"context"
+	"os"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/topaz/internal/batch"
+	"github.com/aserto-dev/topaz/topaz/clients"
+	azc "github.com/aserto-dev/topaz/topaz/clients/authorizer"
+	"github.com/aserto-dev/topaz/topaz/jsonx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type BatchCmd struct {
+	clients.RequestArgs
+	azc.Config
+	Strict      bool   `flag:"strict" help:"only accept boolean decision outcomes"`
+	Concurrency uint32 `flag:"" default:"0" help:"lower the number of concurrent evaluations (0 = server default)"`
+
+	req  batch.IsRequest
+	resp batch.IsResponse
+}
+
+func (cmd *BatchCmd) Run(ctx context.Context) error {
+	if cmd.Template {
+		return jsonx.OutputJSONPB(os.Stdout, cmd.template())
+	}
+
+	if err := cmd.Process(&cmd.req, cmd.template); err != nil {
+		return err
+	}
+
+	if cmd.Strict {
+		cmd.req.Strict = true
+	}
+
+	if cmd.Concurrency > 0 {
+		cmd.req.Concurrency = cmd.Concurrency
+	}
+
+	if err := cmd.Invoke(ctx, batch.Batch_Is_FullMethodName, &cmd.req, &cmd.resp); err != nil {
+		return err
+	}
+
+	return jsonx.OutputJSONPB(os.Stdout, &cmd.resp)
+}
+
+func (cmd *BatchCmd) template() proto.Message {
+	return &batch.IsRequest{
+		IdentityContext: &api.IdentityContext{
+			Identity: "",
+			Type:     api.IdentityType_IDENTITY_TYPE_NONE,
+		},
+		PolicyContext: &api.PolicyContext{
+			Path:      "",
+			Decisions: []string{allowed},
+		},
+		ResourceContexts: []*structpb.Struct{{}, {}},
+	}
+}
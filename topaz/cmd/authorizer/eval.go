@@ -6,6 +6,7 @@ import (
 
 	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
 	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/topaz/internal/outcome"
 	"github.com/aserto-dev/topaz/topaz/clients"
 	azc "github.com/aserto-dev/topaz/topaz/clients/authorizer"
 	"github.com/aserto-dev/topaz/topaz/jsonx"
@@ -16,6 +17,8 @@ import (
 type EvalCmd struct {
 	clients.RequestArgs
 	azc.Config
+	Outcomes bool `flag:"outcomes" help:"return the structured decision outcomes, evaluated by the outcome service"`
+	Strict   bool `flag:"strict" help:"only accept boolean decision outcomes, when used with --outcomes"`
 
 	req  authorizer.IsRequest
 	resp authorizer.IsResponse
@@ -26,6 +29,10 @@ func (cmd *EvalCmd) Run(ctx context.Context) error {
 		return jsonx.OutputJSONPB(os.Stdout, cmd.template())
 	}
 
+	if cmd.Outcomes {
+		return cmd.runOutcomes(ctx)
+	}
+
 	if err := cmd.Process(&cmd.req, cmd.template); err != nil {
 		return err
 	}
@@ -37,7 +44,44 @@ func (cmd *EvalCmd) Run(ctx context.Context) error {
 	return jsonx.OutputJSONPB(os.Stdout, &cmd.resp)
 }
 
+// runOutcomes, evaluates the request using the outcome service, which returns the structured decision outcomes.
+func (cmd *EvalCmd) runOutcomes(ctx context.Context) error {
+	var (
+		req  outcome.IsRequest
+		resp outcome.IsResponse
+	)
+
+	if err := cmd.Process(&req, cmd.template); err != nil {
+		return err
+	}
+
+	if cmd.Strict {
+		req.Strict = true
+	}
+
+	if err := cmd.Invoke(ctx, outcome.Outcome_Is_FullMethodName, &req, &resp); err != nil {
+		return err
+	}
+
+	return jsonx.OutputJSONPB(os.Stdout, &resp)
+}
+
 func (cmd *EvalCmd) template() proto.Message {
+	if cmd.Outcomes {
+		return &outcome.IsRequest{
+			PolicyContext: &api.PolicyContext{
+				Path:      "",
+				Decisions: []string{allowed},
+			},
+			IdentityContext: &api.IdentityContext{
+				Identity: "",
+				Type:     api.IdentityType_IDENTITY_TYPE_NONE,
+			},
+			ResourceContext: &structpb.Struct{},
+			Strict:          cmd.Strict,
+		}
+	}
+
 	return &authorizer.IsRequest{
 		PolicyContext: &api.PolicyContext{
 			Path:      "",
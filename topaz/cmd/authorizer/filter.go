@@ -0,0 +1,74 @@
+package authorizer
+
+import (
+	"context"
+	"os"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/topaz/internal/filter"
+	"github.com/aserto-dev/topaz/topaz/clients"
+	azc "github.com/aserto-dev/topaz/topaz/clients/authorizer"
+	"github.com/aserto-dev/topaz/topaz/jsonx"
+	"google.golang.org/protobuf/proto"
+)
+
+type FilterCmd struct {
+	clients.RequestArgs
+	azc.Config
+	Dialect string            `flag:"" help:"render the filter as a SQL where clause or MongoDB query [sql|mongo]"`
+	Map     map[string]string `flag:"" help:"map filter fields to columns or document fields (field=column)"`
+
+	req  filter.FilterRequest
+	resp filter.FilterResponse
+}
+
+func (cmd *FilterCmd) Run(ctx context.Context) error {
+	if cmd.Template {
+		return jsonx.OutputJSONPB(os.Stdout, cmd.template())
+	}
+
+	if err := cmd.Process(&cmd.req, cmd.template); err != nil {
+		return err
+	}
+
+	if cmd.Dialect != "" {
+		cmd.req.Dialect = cmd.Dialect
+	}
+
+	for field, column := range cmd.Map {
+		if cmd.req.Mapping == nil {
+			cmd.req.Mapping = map[string]string{}
+		}
+
+		cmd.req.Mapping[field] = column
+	}
+
+	if err := cmd.Invoke(ctx, filter.Filter_Filter_FullMethodName, &cmd.req, &cmd.resp); err != nil {
+		return err
+	}
+
+	return jsonx.OutputJSONPB(os.Stdout, &cmd.resp)
+}
+
+func (cmd *FilterCmd) template() proto.Message {
+	return &filter.FilterRequest{
+		Query:    "data.policy.allowed == true",
+		Input:    "",
+		Unknowns: []string{"input.resource"},
+		Options: &authorizer.QueryOptions{
+			Metrics:      false,
+			Instrument:   false,
+			Trace:        authorizer.TraceLevel_TRACE_LEVEL_OFF,
+			TraceSummary: false,
+		},
+		PolicyContext: &api.PolicyContext{
+			Path:      "",
+			Decisions: []string{allowed},
+		},
+		IdentityContext: &api.IdentityContext{
+			Identity: "",
+			Type:     api.IdentityType_IDENTITY_TYPE_NONE,
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/topaz/internal/replay"
+	azc "github.com/aserto-dev/topaz/topaz/clients/authorizer"
+	dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/registry"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type ReplayCmd struct {
+	azc.Config
+	File      string `arg:"" help:"decisions file, the rotated and compressed backups are replayed as well" type:"path"`
+	Bundle    string `flag:"" help:"replay in-process using the candidate policy bundle (directory or bundle file), instead of the authorizer service"`
+	Directory string `flag:"" default:"${directory_svc}" help:"directory service address used by the directory (ds.*) and authZen (az.*) built-ins of the in-process policy bundle"`
+	Output    string `flag:"" short:"o" enum:"csv,json" default:"csv" help:"output format [csv|json]"`
+}
+
+func (cmd *ReplayCmd) Run(ctx context.Context) error {
+	files, err := replay.Files(cmd.File)
+	if err != nil {
+		return err
+	}
+
+	ev, err := cmd.evaluator(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := replay.Replay(ctx, files, ev)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+
+	return report.WriteCSV(os.Stdout)
+}
+
+func (cmd *ReplayCmd) evaluator(ctx context.Context) (replay.Evaluator, error) {
+	if cmd.Bundle == "" {
+		client, err := azc.NewClient(ctx, &cmd.Config)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get authorizer client")
+		}
+
+		return &remoteEvaluator{client: client.Authorizer}, nil
+	}
+
+	dsConfig := &dsc.Config{
+		Host:      cmd.Directory,
+		APIKey:    cmd.APIKey,
+		Token:     cmd.Token,
+		Insecure:  cmd.Insecure,
+		Plaintext: cmd.Plaintext,
+		Headers:   cmd.Headers,
+		Timeout:   cmd.Timeout,
+	}
+
+	conn, err := dsConfig.ClientConfig().Connect()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get directory client")
+	}
+
+	logger := zerolog.Nop()
+	client := dsc.New(conn)
+
+	return replay.NewBundleEvaluator(cmd.Bundle, registry.Functions(registry.Builtins(&logger, client.Reader, client.Access))...)
+}
+
+// remoteEvaluator, evaluates the decisions using the query API of a running authorizer, serving the candidate policy.
+type remoteEvaluator struct {
+	client authorizer.AuthorizerClient
+}
+
+func (e *remoteEvaluator) Eval(ctx context.Context, path string, decisions []string, input map[string]any) (map[string]bool, error) {
+	buf, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// the recorded input contains the resolved identity and user, identity type NONE skips the identity resolution.
+	resp, err := e.client.Query(ctx, &authorizer.QueryRequest{
+		Query:           replay.Query(path, decisions),
+		Input:           string(buf),
+		IdentityContext: &api.IdentityContext{Type: api.IdentityType_IDENTITY_TYPE_NONE},
+		PolicyContext:   &api.PolicyContext{Path: path, Decisions: decisions},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := resp.GetResponse().GetFields()["result"].GetListValue().GetValues()
+	if len(results) == 0 {
+		return nil, errors.Errorf("undefined results: path=%s decisions=%v", path, decisions)
+	}
+
+	bindings := results[0].GetStructValue().GetFields()["bindings"].GetStructValue().AsMap()
+
+	return replay.Outcomes(bindings, decisions)
+}
@@ -0,0 +1,27 @@
+package authorizer
+
+import (
+	"context"
+	"os"
+
+	azc "github.com/aserto-dev/topaz/topaz/clients/authorizer"
+	"github.com/aserto-dev/topaz/topaz/jsonx"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
+)
+
+type GetStatusCmd struct {
+	azc.Config
+
+	Instance string `flag:"" help:"policy instance name (default all instances)"`
+	resp     policywatch.StatusResponse
+}
+
+func (cmd *GetStatusCmd) Run(ctx context.Context) error {
+	req := &policywatch.StatusRequest{Instance: cmd.Instance}
+
+	if err := cmd.Invoke(ctx, policywatch.PolicyStatus_Status_FullMethodName, req, &cmd.resp); err != nil {
+		return err
+	}
+
+	return jsonx.OutputJSONPB(os.Stdout, &cmd.resp)
+}
@@ -7,6 +7,7 @@ import (
 type CertsCmd struct {
 	List     ListCertsCmd      `cmd:"" help:"list dev certs"`
 	Generate GenerateCertsCmd  `cmd:"" help:"generate dev certs"`
+	Client   ClientCertsCmd    `cmd:"" help:"issue a client certificate from the dev CA"`
 	Trust    TrustCertsCmd     `cmd:"" help:"trust/untrust dev certs"`
 	Remove   RemoveCertFileCmd `cmd:"" help:"remove dev certs"`
 }
@@ -72,6 +73,8 @@ func getFileList(certsDir string, opts ...fileListOption) []string {
 		filePaths = append(filePaths,
 			filepath.Join(certsDir, grpcFileName+".key"),
 			filepath.Join(certsDir, gatewayFileName+".key"),
+			filepath.Join(certsDir, grpcFileName+"-ca.key"),
+			filepath.Join(certsDir, gatewayFileName+"-ca.key"),
 		)
 	}
 
@@ -0,0 +1,82 @@
+package certs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/certs"
+	"github.com/aserto-dev/topaz/topaz/cc"
+	"github.com/aserto-dev/topaz/topaz/table"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type ClientCertsCmd struct {
+	Name     string        `arg:"" required:"" help:"client name, used as the subject common name and file name (client-<name>.crt)"`
+	CertsDir string        `flag:"" default:"${topaz_certs_dir}" help:"path to dev certs folder" `
+	CA       string        `flag:"" default:"grpc" enum:"grpc,gateway" help:"dev CA issuing the client certificate (grpc, gateway)"`
+	DNSNames []string      `flag:"" help:"DNS names of the client certificate"`
+	URIs     []string      `flag:"" name:"uri" help:"URI SANs of the client certificate, e.g. spiffe://example.org/service"`
+	Emails   []string      `flag:"" name:"email" help:"email SANs of the client certificate"`
+	ValidFor time.Duration `flag:"" default:"0s" help:"validity of the client certificate, defaults to the validity of the CA"`
+	Force    bool          `flag:"" short:"f" default:"false" help:"force generation of client cert, overwriting existing cert files"`
+}
+
+// Run issues a client certificate from the dev CA, to authenticate using mTLS.
+func (cmd *ClientCertsCmd) Run(ctx context.Context) error {
+	caFileName := grpcFileName
+	if cmd.CA == gatewayFileName {
+		caFileName = gatewayFileName
+	}
+
+	caCert := filepath.Join(cmd.CertsDir, caFileName+"-ca.crt")
+	caKey := filepath.Join(cmd.CertsDir, caFileName+"-ca.key")
+
+	if _, err := os.Stat(caKey); os.IsNotExist(err) {
+		return errors.Errorf("dev CA key %s not found, regenerate the dev certs using 'topaz certs generate --force'", caKey)
+	}
+
+	certPath := filepath.Join(cmd.CertsDir, "client-"+cmd.Name+".crt")
+	keyPath := filepath.Join(cmd.CertsDir, "client-"+cmd.Name+".key")
+
+	if !cmd.Force {
+		for _, fqn := range []string{certPath, keyPath} {
+			if _, err := os.Stat(fqn); err == nil {
+				return errors.Errorf("%s already exists, use --force to overwrite", fqn)
+			}
+		}
+	}
+
+	cc.Con().Info().Msg("certs directory: %s", cmd.CertsDir)
+
+	logger := zerolog.Nop()
+	generator := certs.NewGenerator(logger.WithContext(ctx))
+
+	if err := generator.MakeClientCert(&certs.ClientCertGenConfig{
+		CommonName:     cmd.Name,
+		DNSNames:       cmd.DNSNames,
+		URIs:           cmd.URIs,
+		EmailAddresses: cmd.Emails,
+		CertPath:       certPath,
+		CertKeyPath:    keyPath,
+		CACertPath:     caCert,
+		CAKeyPath:      caKey,
+		ValidFor:       cmd.ValidFor,
+	}); err != nil {
+		return errors.Wrap(err, "failed to create client cert")
+	}
+
+	tab := table.New(os.Stderr)
+	defer tab.Close()
+
+	tab.Header("File", "Action")
+	tab.Bulk([][]any{
+		{filepath.Base(certPath), "generated"},
+		{filepath.Base(keyPath), "generated"},
+	})
+	tab.Render()
+
+	return nil
+}
@@ -27,19 +27,21 @@ func (cmd *GenerateCertsCmd) Run(ctx context.Context) error {
 	}
 
 	pathGateway := &certs.CertPaths{
-		Name: certCommonName + "-gateway",
-		Cert: filepath.Join(certsDir, gatewayFileName+".crt"),
-		CA:   filepath.Join(certsDir, gatewayFileName+"-ca.crt"),
-		Key:  filepath.Join(certsDir, gatewayFileName+".key"),
-		Dir:  certsDir,
+		Name:  certCommonName + "-gateway",
+		Cert:  filepath.Join(certsDir, gatewayFileName+".crt"),
+		CA:    filepath.Join(certsDir, gatewayFileName+"-ca.crt"),
+		CAKey: filepath.Join(certsDir, gatewayFileName+"-ca.key"),
+		Key:   filepath.Join(certsDir, gatewayFileName+".key"),
+		Dir:   certsDir,
 	}
 
 	pathGRPC := &certs.CertPaths{
-		Name: certCommonName + "-grpc",
-		Cert: filepath.Join(certsDir, grpcFileName+".crt"),
-		CA:   filepath.Join(certsDir, grpcFileName+"-ca.crt"),
-		Key:  filepath.Join(certsDir, grpcFileName+".key"),
-		Dir:  certsDir,
+		Name:  certCommonName + "-grpc",
+		Cert:  filepath.Join(certsDir, grpcFileName+".crt"),
+		CA:    filepath.Join(certsDir, grpcFileName+"-ca.crt"),
+		CAKey: filepath.Join(certsDir, grpcFileName+"-ca.key"),
+		Key:   filepath.Join(certsDir, grpcFileName+".key"),
+		Dir:   certsDir,
 	}
 
 	cc.Con().Info().Msg("certs directory: %s", certsDir)
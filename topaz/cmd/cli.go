@@ -6,6 +6,7 @@ import (
 	"github.com/aserto-dev/topaz/topaz/cmd/certs"
 	"github.com/aserto-dev/topaz/topaz/cmd/configure"
 	"github.com/aserto-dev/topaz/topaz/cmd/directory"
+	"github.com/aserto-dev/topaz/topaz/cmd/policy"
 	"github.com/aserto-dev/topaz/topaz/cmd/templates"
 	"github.com/aserto-dev/topaz/topaz/cmd/topaz"
 )
@@ -27,6 +28,7 @@ type CLI struct {
 	Directory  directory.DirectoryCmd   `cmd:"" aliases:"ds" help:"directory service commands"`
 	Authorizer authorizer.AuthorizerCmd `cmd:"" aliases:"az" help:"authorizer service commands"`
 	Access     access.AccessCmd         `cmd:"" aliases:"ac" help:"access service commands "`
+	Policy     policy.PolicyCmd         `cmd:"" help:"policy development commands"`
 	Certs      certs.CertsCmd           `cmd:"" help:"certificate management"`
 	Install    topaz.InstallCmd         `cmd:"" help:"install topaz container"`
 	Uninstall  topaz.UninstallCmd       `cmd:"" help:"uninstall topaz container"`
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aserto-dev/topaz/pkg/config"
 	"github.com/aserto-dev/topaz/topaz/cc"
@@ -16,12 +17,13 @@ import (
 const (
 	FromRemote = "remote"
 	FromLocal  = "local"
+	FromDir    = "dir:"
 )
 
 type NewConfigCmd struct {
 	Name          ConfigName `flag:"" short:"n" required:"" help:"config name"`
-	Resource      string     `flag:"" short:"r" required:"" help:"policy uri (e.g. ghcr.io/org/policy:tag)"`
-	From          string     `flag:"" short:"F" required:"" default:"remote" enum:"remote,local" help:"load policy from remote or local image"`
+	Resource      string     `flag:"" short:"r" help:"policy uri (e.g. ghcr.io/org/policy:tag)"`
+	From          string     `flag:"" short:"F" default:"remote" help:"load policy from remote or local image, or watch a local policy directory (remote|local|dir:<path>)"`
 	Policy        string     `flag:"" short:"P" help:"policy name"`
 	Stdout        bool       `flag:"" short:"p" default:"false" help:"print to stdout"`
 	EdgeDirectory bool       `flag:"" short:"d" default:"false" hidden:"" help:"enable edge directory"`
@@ -30,10 +32,19 @@ type NewConfigCmd struct {
 
 //nolint:nestif
 func (cmd *NewConfigCmd) Run(ctx context.Context) error {
-	if cmd.Resource == "" {
+	policyDir, err := cmd.policyDir()
+	if err != nil {
+		return err
+	}
+
+	if policyDir == "" && cmd.Resource == "" {
 		return errors.New("no policy specified. Please provide a policy URI with the --resource (-r) option")
 	}
 
+	if policyDir != "" && cmd.Policy == "" {
+		cmd.Policy = filepath.Base(policyDir)
+	}
+
 	cfg := cc.GetConfig()
 
 	configFile := cmd.Name.String() + ".yaml"
@@ -51,7 +62,8 @@ func (cmd *NewConfigCmd) Run(ctx context.Context) error {
 		WithConfigName(cmd.Name.String()).
 		WithPolicyName(cmd.Policy).
 		WithResource(cmd.Resource).
-		WithLocalPolicy(cmd.From == FromLocal).
+		WithLocalPolicy(cmd.From == FromLocal || policyDir != "").
+		WithPolicyDir(policyDir).
 		WithEdgeDirectory(cmd.EdgeDirectory)
 
 	if _, err := configGenerator.CreateConfigDir(); err != nil {
@@ -71,10 +83,7 @@ func (cmd *NewConfigCmd) Run(ctx context.Context) error {
 		return err
 	}
 
-	var (
-		w   io.Writer
-		err error
-	)
+	var w io.Writer
 
 	if cmd.Stdout {
 		w = os.Stdout
@@ -95,6 +104,14 @@ func (cmd *NewConfigCmd) Run(ctx context.Context) error {
 		}
 	}
 
+	if policyDir != "" {
+		if !cmd.Stdout {
+			cc.Con().Info().Msg("using local policy directory: %s", policyDir)
+		}
+
+		return configGenerator.GenerateConfig(w, config.LocalDirTemplate)
+	}
+
 	if !cmd.Stdout {
 		if cmd.From == FromLocal {
 			cc.Con().Info().Msg("using local policy image: %s", cmd.Resource)
@@ -110,3 +127,30 @@ func (cmd *NewConfigCmd) Run(ctx context.Context) error {
 
 	return configGenerator.GenerateConfig(w, config.RemoteImageTemplate)
 }
+
+// policyDir, returns the absolute path of the policy directory of --from dir:<path>,
+// empty when the policy is loaded from an image.
+func (cmd *NewConfigCmd) policyDir() (string, error) {
+	switch {
+	case cmd.From == FromRemote || cmd.From == FromLocal:
+		return "", nil
+	case !strings.HasPrefix(cmd.From, FromDir):
+		return "", errors.Errorf("invalid --from value %q, must be remote, local or dir:<path>", cmd.From)
+	}
+
+	dir, err := filepath.Abs(strings.TrimPrefix(cmd.From, FromDir))
+	if err != nil {
+		return "", err
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "policy directory %q", dir)
+	}
+
+	if !fi.IsDir() {
+		return "", errors.Errorf("policy directory %q is not a directory", dir)
+	}
+
+	return dir, nil
+}
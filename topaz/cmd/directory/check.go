@@ -2,9 +2,13 @@ package directory
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
 	"github.com/aserto-dev/topaz/topaz/clients"
 	dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
 	"github.com/aserto-dev/topaz/topaz/jsonx"
@@ -15,6 +19,8 @@ type CheckCmd struct {
 	clients.RequestArgs
 	dsc.Config
 
+	Explain bool `flag:"" help:"explain check outcome using the relation paths explored"`
+
 	req  reader.CheckRequest
 	resp reader.CheckResponse
 }
@@ -28,11 +34,45 @@ func (cmd *CheckCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	if cmd.Explain {
+		cmd.req.Trace = true
+	}
+
 	if err := cmd.Invoke(ctx, reader.Reader_Check_FullMethodName, &cmd.req, &cmd.resp); err != nil {
 		return err
 	}
 
-	return jsonx.OutputJSONPB(os.Stdout, &cmd.resp)
+	if err := jsonx.OutputJSONPB(os.Stdout, &cmd.resp); err != nil {
+		return err
+	}
+
+	if cmd.Explain {
+		explain(os.Stderr, &cmd.resp)
+	}
+
+	return nil
+}
+
+// explain, renders the relation paths contained in the check response context.
+func explain(w io.Writer, resp *reader.CheckResponse) {
+	decision := "denied, deepest paths explored"
+	if resp.GetCheck() {
+		decision = "granted by"
+	}
+
+	paths := resp.GetContext().GetFields()[ds.ContextPaths].GetListValue().GetValues()
+
+	fmt.Fprintf(w, "\n%s (%d):\n", decision, len(paths))
+
+	for i, path := range paths {
+		calls := path.GetListValue().GetValues()
+
+		fmt.Fprintf(w, "\npath %d:\n", i+1)
+
+		for depth, call := range calls {
+			fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth+1), call.GetStringValue())
+		}
+	}
 }
 
 func (cmd *CheckCmd) template() proto.Message {
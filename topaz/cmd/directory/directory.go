@@ -6,6 +6,7 @@ type DirectoryCmd struct {
 	Check   CheckCmd   `cmd:"" help:"check single permission"`
 	Checks  ChecksCmd  `cmd:"" help:"check multiple permissions"`
 	Search  SearchCmd  `cmd:"" help:"search relation graph"`
+	Report  ReportCmd  `cmd:"" help:"access report of an object or subject"`
 	Get     GetCmd     `cmd:"" help:"get object|relation|manifest|model"`
 	Set     SetCmd     `cmd:"" help:"set object|relation|manifest"`
 	Delete  DeleteCmd  `cmd:"" help:"delete object|relation|manifest"`
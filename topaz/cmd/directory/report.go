@@ -0,0 +1,74 @@
+package directory
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
+	"github.com/aserto-dev/topaz/topaz/cc"
+	dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
+	"github.com/pkg/errors"
+)
+
+type ReportCmd struct {
+	dsc.Config
+
+	Object      string `flag:"object" xor:"anchor" required:"" help:"report who can access the object (type:id)"`
+	Subject     string `flag:"subject" xor:"anchor" required:"" help:"report what the subject (type:id) can access"`
+	Permission  string `flag:"permission" help:"limit report to a single permission"`
+	Type        string `flag:"type" help:"limit report to a single subject type (--object) or object type (--subject)"`
+	Output      string `flag:"output" short:"o" enum:"csv,jsonl" default:"csv" help:"output format [csv|jsonl]"`
+	File        string `flag:"file" short:"f" type:"path" help:"path to report file, defaults to stdout"`
+	Concurrency uint32 `flag:"concurrency" default:"0" help:"maximum number of concurrent graph searches, limited by the server"`
+}
+
+func (cmd *ReportCmd) Run(ctx context.Context) error {
+	req, err := cmd.request()
+	if err != nil {
+		return err
+	}
+
+	client, err := dsc.NewClient(ctx, &cmd.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to get directory client")
+	}
+
+	writer := os.Stdout
+
+	if cmd.File != "" {
+		cc.Con().Info().Msg(">>> writing report to %q", cmd.File)
+
+		writer, err = os.Create(cmd.File)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+	}
+
+	return client.ReportToFile(ctx, writer, req, cmd.Output)
+}
+
+func (cmd *ReportCmd) request() (*report.AccessReportRequest, error) {
+	req := &report.AccessReportRequest{Relation: cmd.Permission, Concurrency: cmd.Concurrency}
+
+	if cmd.Object != "" {
+		ot, oid, ok := strings.Cut(cmd.Object, ":")
+		if !ok || ot == "" || oid == "" {
+			return nil, errors.Errorf("invalid object %q, expected type:id", cmd.Object)
+		}
+
+		req.ObjectType, req.ObjectId, req.SubjectType = ot, oid, cmd.Type
+
+		return req, nil
+	}
+
+	st, sid, ok := strings.Cut(cmd.Subject, ":")
+	if !ok || st == "" || sid == "" {
+		return nil, errors.Errorf("invalid subject %q, expected type:id", cmd.Subject)
+	}
+
+	req.SubjectType, req.SubjectId, req.ObjectType = st, sid, cmd.Type
+
+	return req, nil
+}
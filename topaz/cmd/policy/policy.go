@@ -0,0 +1,5 @@
+package policy
+
+type PolicyCmd struct {
+	Test TestCmd `cmd:"" help:"run policy unit tests (test_ rules) with the topaz built-ins, against an in-process directory"`
+}
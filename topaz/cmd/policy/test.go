@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/policytest"
+	"github.com/aserto-dev/topaz/topaz-db/pkg/inproc"
+	dsc "github.com/aserto-dev/topaz/topaz/clients/directory"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/registry"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const requestTimeout = 5 * time.Second
+
+type TestCmd struct {
+	Paths    []string      `arg:"" optional:"" default:"." type:"path" help:"policy files and directories"`
+	Manifest string        `flag:"" short:"m" type:"existingfile" help:"directory manifest file"`
+	Data     []string      `flag:"" short:"d" type:"path" help:"directory data files, or directories of data files (*.json), containing objects and relations"`
+	Ignore   []string      `flag:"" help:"file and directory names to ignore"`
+	Filter   string        `flag:"" name:"run" short:"r" help:"only run test cases matching the regular expression"`
+	Coverage bool          `flag:"" short:"c" help:"report policy coverage"`
+	Timeout  time.Duration `flag:"" default:"5s" help:"test case timeout"`
+	Verbose  bool          `flag:"" short:"V" help:"report all test cases and their print output"`
+	Output   string        `flag:"" short:"o" enum:"text,json" default:"text" help:"output format [text|json]"`
+}
+
+var ErrTestsFailed = errors.New("policy tests failed")
+
+func (cmd *TestCmd) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dbDir, err := os.MkdirTemp("", "topaz-policy-test-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dbDir)
+
+	logger := zerolog.New(io.Discard)
+
+	conn, cleanup := inproc.NewServer(ctx, &logger, &directory.Config{
+		DBPath:         filepath.Join(dbDir, "directory.db"),
+		RequestTimeout: requestTimeout,
+	})
+	defer cleanup()
+
+	dsClient := dsc.New(conn)
+
+	if err := cmd.load(ctx, dsClient); err != nil {
+		return err
+	}
+
+	report, err := policytest.Run(ctx, &policytest.Options{
+		Paths:    cmd.Paths,
+		Ignore:   cmd.Ignore,
+		Run:      cmd.Filter,
+		Coverage: cmd.Coverage,
+		Timeout:  cmd.Timeout,
+		Builtins: policytest.Builtins(registry.Builtins(&logger, dsClient.Reader, dsClient.Access)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout, cmd.Verbose)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !report.OK() {
+		return ErrTestsFailed
+	}
+
+	return nil
+}
+
+// load, sets the manifest and imports the objects and relations of the data files into the in-process directory.
+func (cmd *TestCmd) load(ctx context.Context, dsClient *dsc.Client) error {
+	if cmd.Manifest != "" {
+		r, err := os.Open(cmd.Manifest)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		if err := dsClient.SetManifest(ctx, r); err != nil {
+			return errors.Wrapf(err, "failed to set manifest %q", cmd.Manifest)
+		}
+	}
+
+	files := []string{}
+
+	for _, path := range cmd.Data {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return err
+		}
+
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	if err := dsClient.Import(ctx, files); err != nil {
+		return errors.Wrap(err, "failed to import directory data")
+	}
+
+	return nil
+}
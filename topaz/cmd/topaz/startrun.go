@@ -76,6 +76,8 @@ func (cmd *StartRunCmd) run(cfg *cc.Config, mode runMode) error {
 		return err
 	}
 
+	policyVolumes := getPolicyVolumes(activeConfig)
+
 	cc.Con().Info().Msg(">>> starting topaz %q...", cfg.Running.Config)
 
 	dc, err := dockerx.New()
@@ -108,6 +110,7 @@ func (cmd *StartRunCmd) run(cfg *cc.Config, mode runMode) error {
 		dockerx.WithEnvs(cmd.Env),
 		dockerx.WithPorts(ports),
 		dockerx.WithVolumes(volumes),
+		dockerx.WithVolumes(policyVolumes),
 		dockerx.WithOutput(os.Stdout),
 		dockerx.WithError(os.Stderr),
 	}
@@ -178,6 +181,23 @@ func getVolumes(cfg *config.Loader) ([]string, error) {
 	return volumes, nil
 }
 
+// getPolicyVolumes, mounts the local policy bundle paths at the same path inside the container,
+// so the configured paths resolve, the volumes do not define environment variables.
+func getPolicyVolumes(cfg *config.Loader) []string {
+	volumes := []string{}
+
+	for _, path := range cfg.Configuration.OPA.LocalBundles.Paths {
+		if _, err := os.Stat(path); err != nil || !filepath.IsAbs(path) {
+			cc.Con().Warn().Msg("local bundle path %q does not exist or is not an absolute path, not mounted", path)
+			continue
+		}
+
+		volumes = append(volumes, path+":"+path+":ro")
+	}
+
+	return volumes
+}
+
 func getEnvFromVolumes(volumes []string) []string {
 	envs := []string{}
 
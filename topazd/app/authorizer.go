@@ -6,10 +6,17 @@ import (
 
 	authz "github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
 	azOpenAPI "github.com/aserto-dev/openapi-authorizer/publish/authorizer"
+	"github.com/aserto-dev/topaz/internal/batch"
+	"github.com/aserto-dev/topaz/internal/dataapi"
+	"github.com/aserto-dev/topaz/internal/filter"
+	"github.com/aserto-dev/topaz/internal/outcome"
 	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/aserto-dev/topaz/topazd/authorizer/authzen"
 	"github.com/aserto-dev/topaz/topazd/authorizer/impl"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
 	"github.com/aserto-dev/topaz/topazd/authorizer/resolvers"
 	"github.com/aserto-dev/topaz/topazd/service/builder"
+	dsa "github.com/authzen/access.go/api/access/v1"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 
 	"github.com/pkg/errors"
@@ -25,6 +32,9 @@ type Authorizer struct {
 	Resolver         *resolvers.Resolvers
 	AuthorizerServer *impl.AuthorizerServer
 	cfg              *builder.API
+	dataAPI          bool
+	authzen          *authzen.Server
+	dirAccess        dsa.AccessServer
 	opts             []grpc.ServerOption
 	cleanupFunctions []func()
 }
@@ -57,8 +67,19 @@ func NewAuthorizer(
 		return nil, errors.Wrap(err, "failed to create authorizer server")
 	}
 
+	var authzenServer *authzen.Server
+
+	if commonConfig.AuthZEN.Enabled {
+		authzenServer, err = authzen.NewServer(&commonConfig.AuthZEN, authServer, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create authzen server")
+		}
+	}
+
 	return &Authorizer{
 		cfg:              cfg,
+		dataAPI:          commonConfig.DataAPI.Enabled,
+		authzen:          authzenServer,
 		opts:             authorizerOpts,
 		Resolver:         authResolvers,
 		AuthorizerServer: authServer,
@@ -72,6 +93,14 @@ func (e *Authorizer) AvailableServices() []string {
 func (e *Authorizer) GetGRPCRegistrations(services ...string) builder.GRPCRegistrations {
 	return func(server *grpc.Server) {
 		authz.RegisterAuthorizerServer(server, e.AuthorizerServer)
+		outcome.RegisterOutcomeServer(server, e.AuthorizerServer.Outcome())
+		filter.RegisterFilterServer(server, e.AuthorizerServer)
+		batch.RegisterBatchServer(server, e.AuthorizerServer.Batch())
+		policywatch.RegisterPolicyStatusServer(server, e.AuthorizerServer.PolicyStatus())
+
+		if e.authzen != nil {
+			dsa.RegisterAccessServer(server, e.authzen)
+		}
 	}
 }
 
@@ -81,6 +110,28 @@ func (e *Authorizer) GetGatewayRegistration(port string, services ...string) bui
 			return err
 		}
 
+		if err := outcome.RegisterOutcomeHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+			return err
+		}
+
+		if err := filter.RegisterFilterHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+			return err
+		}
+
+		if err := batch.RegisterBatchHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+			return err
+		}
+
+		if err := policywatch.RegisterPolicyStatusHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+			return err
+		}
+
+		if e.authzen != nil {
+			if err := dsa.RegisterAccessHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+				return err
+			}
+		}
+
 		if len(services) > 0 {
 			if err := mux.HandlePath(http.MethodGet, authorizerOpenAPISpec, azOpenAPIHandler); err != nil {
 				return err
@@ -91,6 +142,46 @@ func (e *Authorizer) GetGatewayRegistration(port string, services ...string) bui
 	}
 }
 
+// RegisterDataAPI, registers the OPA compatible data API handlers, of the root document and of
+// the documents below it, on the gateway mux, when the data API is enabled. The documents are
+// evaluated in-process, and the requests are authenticated as calls of the data API path.
+func (e *Authorizer) RegisterDataAPI(mux *http.ServeMux, auth func(path string, next http.Handler) http.Handler) {
+	if !e.dataAPI {
+		return
+	}
+
+	handler := auth(dataapi.PathPrefix, dataapi.Handler(e.AuthorizerServer))
+
+	mux.Handle(dataapi.PathPrefix, handler)
+	mux.Handle(dataapi.PathPrefix+"/", handler)
+}
+
+// PolicyAccess, returns true when the AuthZEN access service is served by the authorizer policies.
+func (e *Authorizer) PolicyAccess() bool {
+	return e.authzen != nil
+}
+
+// SetDirectoryAccess, forwards the AuthZEN searches, and the calls of the az.* built-ins, to the
+// in-process directory access service.
+func (e *Authorizer) SetDirectoryAccess(dir dsa.AccessServer) {
+	e.dirAccess = dir
+
+	if e.authzen != nil {
+		e.authzen.SetDirectory(dir)
+	}
+}
+
+// DirectoryAccess, returns the in-process access client of the directory, when the directory shares
+// the endpoint of the authorizer, where the access service is served by the authorizer policies,
+// otherwise nil.
+func (e *Authorizer) DirectoryAccess() dsa.AccessClient {
+	if e.dirAccess == nil {
+		return nil
+	}
+
+	return authzen.LocalClient(e.dirAccess)
+}
+
 func (e *Authorizer) Cleanups() []func() {
 	return e.cleanupFunctions
 }
@@ -13,7 +13,9 @@ import (
 )
 
 const (
-	consoleService = "console"
+	consoleService  = "console"
+	configPath      = "/api/v2/config"
+	authorizersPath = "/api/v1/authorizers"
 )
 
 type ConsoleService struct{}
@@ -89,6 +91,14 @@ func (e *ConsoleService) PrepareConfig(cfg *config.Config) *handlers.TopazCfg {
 
 			break
 		}
+
+		// without legacy keys, use the first scoped key with a plain text value.
+		for _, key := range cfg.Auth.ScopedKeys {
+			if authorizerAPIKey == "" && key.Key != "" {
+				authorizerAPIKey = key.Key
+				directoryAPIKey = key.Key
+			}
+		}
 	}
 
 	// if the `remote directory` API key is set, use that instead the local API key.
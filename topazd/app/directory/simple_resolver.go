@@ -2,6 +2,7 @@ package directory
 
 import (
 	client "github.com/aserto-dev/go-aserto"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/aserto-dev/topaz/topazd/authorizer/resolvers"
 
 	"github.com/rs/zerolog"
@@ -20,7 +21,7 @@ func NewResolver(logger *zerolog.Logger, cfg *client.Config) (*Resolver, error)
 	l := logger.With().Interface("client", cfg).Logger()
 	l.Debug().Msg("new directory resolver")
 
-	conn, err := cfg.Connect()
+	conn, err := cfg.Connect(client.WithDialOptions(telemetry.DialOptions()...))
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ import (
 	dsm3stream "github.com/aserto-dev/go-directory/pkg/gateway/model/v3"
 	dsOpenAPI "github.com/aserto-dev/openapi-directory/publish/directory"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/report"
 	"github.com/aserto-dev/topaz/topazd/service/builder"
 	dsa "github.com/authzen/access.go/api/access/v1"
 
@@ -30,7 +31,8 @@ const (
 )
 
 type EdgeDir struct {
-	dir *directory.Directory
+	dir      *directory.Directory
+	noAccess bool
 }
 
 var _ builder.ServiceTypes = (*EdgeDir)(nil)
@@ -41,6 +43,17 @@ func NewEdgeDir(edge *directory.Directory) (*EdgeDir, error) {
 	}, nil
 }
 
+// DisableAccess, skips the registration of the AuthZEN access service, when it is served by the
+// authorizer policies on the same endpoint.
+func (e *EdgeDir) DisableAccess() {
+	e.noAccess = true
+}
+
+// Access, returns the directory AuthZEN access service.
+func (e *EdgeDir) Access() dsa.AccessServer {
+	return e.dir.Access1()
+}
+
 func (e *EdgeDir) Close() {
 	if e.dir != nil {
 		e.dir.Close()
@@ -59,7 +72,12 @@ func (e *EdgeDir) GetGRPCRegistrations(services ...string) builder.GRPCRegistrat
 
 		if lo.Contains(services, readerService) {
 			dsr.RegisterReaderServer(server, e.dir.Reader3())
-			dsa.RegisterAccessServer(server, e.dir.Access1())
+
+			if !e.noAccess {
+				dsa.RegisterAccessServer(server, e.dir.Access1())
+			}
+
+			report.RegisterReportServer(server, e.dir.Report1())
 		}
 
 		if lo.Contains(services, writerService) {
@@ -96,12 +114,16 @@ func (e *EdgeDir) GetGatewayRegistration(port string, services ...string) builde
 					return err
 				}
 			}
-			{
+			if !e.noAccess {
 				err := dsa.RegisterAccessHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts)
 				if err != nil {
 					return err
 				}
 			}
+
+			if err := report.RegisterReportHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+				return err
+			}
 		}
 
 		if lo.Contains(services, writerService) {
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"github.com/aserto-dev/topaz/topazd/authorizer/extauthz"
+	"github.com/aserto-dev/topaz/topazd/service/builder"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+const (
+	extAuthzService = "ext_authz"
+)
+
+// ExtAuthz, the envoy external authorization service, check requests are evaluated by the authorizer server.
+type ExtAuthz struct {
+	server *extauthz.Server
+}
+
+var _ builder.ServiceTypes = (*ExtAuthz)(nil)
+
+func NewExtAuthz(cfg *extauthz.Config, authz extauthz.Authorizer, logger *zerolog.Logger) (*ExtAuthz, error) {
+	server, err := extauthz.NewServer(cfg, authz, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ext_authz server")
+	}
+
+	return &ExtAuthz{
+		server: server,
+	}, nil
+}
+
+func (e *ExtAuthz) AvailableServices() []string {
+	return []string{extAuthzService}
+}
+
+func (e *ExtAuthz) GetGRPCRegistrations(services ...string) builder.GRPCRegistrations {
+	return func(server *grpc.Server) {
+		authv3.RegisterAuthorizationServer(server, e.server)
+	}
+}
+
+// GetGatewayRegistration, the ext_authz service is gRPC only, envoy does not use a REST gateway.
+func (e *ExtAuthz) GetGatewayRegistration(port string, services ...string) builder.HandlerRegistrations {
+	return func(ctx context.Context, mux *runtime.ServeMux, grpcEndpoint string, opts []grpc.DialOption) error {
+		return nil
+	}
+}
+
+func (e *ExtAuthz) Close() {
+}
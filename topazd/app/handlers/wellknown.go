@@ -14,6 +14,12 @@ import (
 
 const AuthZENConfiguration string = `/.well-known/authzen-configuration`
 
+// Engines backing the AuthZEN endpoints, reported in the well-known configuration.
+const (
+	EngineDirectory string = "directory"
+	EnginePolicy    string = "policy"
+)
+
 var once sync.Once
 
 type WellKnownConfig struct {
@@ -23,9 +29,13 @@ type WellKnownConfig struct {
 	SearchSubjectEndpoint     string `json:"search_subject_endpoint"`     //nolint:tagliatelle
 	SearchResourceEndpoint    string `json:"search_resource_endpoint"`    //nolint:tagliatelle
 	SearchActionEndpoint      string `json:"search_action_endpoint"`      //nolint:tagliatelle
+	// Engines, the engine (directory or policy) backing each endpoint, keyed by the endpoint field name.
+	Engines map[string]string `json:"topaz_engines"` //nolint:tagliatelle
 }
 
-func WellKnownConfigHandler(endpoint *url.URL) func(w http.ResponseWriter, r *http.Request) {
+// WellKnownConfigHandler, serves the AuthZEN configuration of the endpoint, the access evaluations
+// are served by the evaluation engine, the searches are always served by the directory.
+func WellKnownConfigHandler(endpoint *url.URL, engine string) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		config := WellKnownConfig{
 			PolicyDecisionPoint:       endpoint.String(),
@@ -34,6 +44,13 @@ func WellKnownConfigHandler(endpoint *url.URL) func(w http.ResponseWriter, r *ht
 			SearchSubjectEndpoint:     endpoint.String() + "/access/v1/search/subject",
 			SearchResourceEndpoint:    endpoint.String() + "/access/v1/search/resource",
 			SearchActionEndpoint:      endpoint.String() + "/access/v1/search/action",
+			Engines: map[string]string{
+				"access_evaluation_endpoint":  engine,
+				"access_evaluations_endpoint": engine,
+				"search_subject_endpoint":     EngineDirectory,
+				"search_resource_endpoint":    EngineDirectory,
+				"search_action_endpoint":      EngineDirectory,
+			},
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -42,14 +59,16 @@ func WellKnownConfigHandler(endpoint *url.URL) func(w http.ResponseWriter, r *ht
 	}
 }
 
-func SetWellKnownConfigHandler(cfg *builder.API, mux *http.ServeMux) error {
+// SetWellKnownConfigHandler, registers the AuthZEN configuration handler, cfg is the API of the
+// service serving the AuthZEN endpoints, engine is the engine of the access evaluations.
+func SetWellKnownConfigHandler(cfg *builder.API, engine string, mux *http.ServeMux) error {
 	ep, err := endpoint(cfg)
 	if err != nil {
 		return err
 	}
 
 	once.Do(func() {
-		mux.HandleFunc(AuthZENConfiguration, WellKnownConfigHandler(ep))
+		mux.HandleFunc(AuthZENConfiguration, WellKnownConfigHandler(ep, engine))
 	})
 
 	return nil
@@ -1,28 +1,33 @@
 package middlewares
 
 import (
-	"context"
-
 	grpcutil "github.com/aserto-dev/topaz/internal/grpc"
 	"github.com/aserto-dev/topaz/internal/grpc/middlewares/gerr"
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
 	"github.com/aserto-dev/topaz/internal/grpc/middlewares/request"
 	"github.com/aserto-dev/topaz/internal/grpc/middlewares/tracing"
-	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/aserto-dev/topaz/topazd/authentication"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 )
 
-func GetMiddlewaresForService(ctx context.Context, cfg *config.Config, logger *zerolog.Logger) ([]grpc.ServerOption, error) {
-	var middlewareList grpcutil.Middlewares
-
-	if len(cfg.Auth.APIKeys) > 0 {
-		authMiddleware, err := authentication.NewAPIKeyAuthMiddleware(ctx, &cfg.Auth, logger)
-		if err != nil {
-			return nil, err
-		}
-
-		middlewareList = append(middlewareList, authMiddleware)
+// GetMiddlewaresForService, returns the middlewares of a gRPC server, the authentication middleware and the
+// rate limiter, when set, are shared by the servers.
+//
+// The authentication middleware allows all calls when no credentials are configured, it is installed
+// regardless, so credentials added by a configuration reload apply without a restart.
+func GetMiddlewaresForService(
+	auth *authentication.APIKeyAuthMiddleware,
+	logger *zerolog.Logger,
+	limiter *ratelimit.Limiter,
+) []grpc.ServerOption {
+	// the server span is started first, so rejected (unauthenticated) calls are traced as well.
+	middlewareList := grpcutil.Middlewares{telemetry.NewServerMiddleware(), auth}
+
+	// the rate limits follow the authentication, which identifies the caller.
+	if limiter != nil {
+		middlewareList = append(middlewareList, ratelimit.NewMiddleware(limiter))
 	}
 
 	middlewareList = append(middlewareList,
@@ -32,7 +37,6 @@ func GetMiddlewaresForService(ctx context.Context, cfg *config.Config, logger *z
 	)
 
 	unary, stream := middlewareList.AsGRPCOptions()
-	opts := []grpc.ServerOption{unary, stream}
 
-	return opts, nil
+	return []grpc.ServerOption{unary, stream}
 }
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aserto-dev/topaz/internal/reload"
+	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+// ConfigStatusPath, path of the configuration reload status, served by the metrics server.
+const ConfigStatusPath = "/admin/config"
+
+// Section prefixes of the live sections of the configuration.
+const (
+	SectionPlugins       = "opa.config.plugins."
+	SectionShadowPlugins = "shadow." + SectionPlugins
+	SectionGateway       = "api.services.*.gateway."
+)
+
+// pluginReconfigurer, the runtime resolver, which reconfigures the plugins of the policy instances.
+type pluginReconfigurer interface {
+	PreparePlugins(ctx context.Context, logger *zerolog.Logger, cfg *config.Config, names ...string) (*reload.Change, error)
+}
+
+// ApplyConfig, applies the changed live sections of the reloaded configuration, the context bounds
+// the lifetime of the reloaded components. The components of all sections are built before any of
+// them replaces a running component, a section which fails to build leaves the running configuration
+// unchanged.
+func (e *Topaz) ApplyConfig(ctx context.Context, cfg *config.Config, sections []string) error {
+	var (
+		reloadAuth, reloadJWT, reloadCORS, reloadLogLevel bool
+		plugins                                           []string
+	)
+
+	for _, section := range sections {
+		switch {
+		case strings.HasPrefix(section, "auth."):
+			reloadAuth = true
+		case section == "jwt":
+			reloadJWT = true
+		case section == "logging.log_level":
+			reloadLogLevel = true
+		case strings.HasPrefix(section, SectionGateway):
+			reloadCORS = true
+		case strings.HasPrefix(section, SectionPlugins), strings.HasPrefix(section, SectionShadowPlugins):
+			plugin, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(section, SectionShadowPlugins), SectionPlugins), ".")
+			plugins = lo.Uniq(append(plugins, plugin))
+		}
+	}
+
+	changes, err := e.prepareConfig(ctx, cfg, reloadAuth, reloadJWT, plugins)
+	if err != nil {
+		return err
+	}
+
+	reload.Commit(changes...)
+
+	if reloadLogLevel {
+		zerolog.SetGlobalLevel(cfg.Logging.LogLevelParsed)
+	}
+
+	if reloadCORS {
+		e.reloadCORS(cfg)
+	}
+
+	return nil
+}
+
+// prepareConfig, prepares the changes of the components of the reloaded configuration, the prepared
+// changes are discarded when a component fails to build.
+func (e *Topaz) prepareConfig(ctx context.Context, cfg *config.Config, reloadAuth, reloadJWT bool, plugins []string) ([]*reload.Change, error) {
+	changes := []*reload.Change{}
+
+	fail := func(err error, section string) ([]*reload.Change, error) {
+		reload.Discard(changes...)
+		return nil, errors.Wrap(err, section)
+	}
+
+	if reloadAuth && e.auth != nil {
+		change, err := e.auth.PrepareReload(&cfg.Auth)
+		if err != nil {
+			return fail(err, "auth")
+		}
+
+		changes = append(changes, change)
+	}
+
+	authorizer, _ := e.Services[authorizerService].(*Authorizer)
+
+	if reloadJWT && authorizer != nil {
+		change, err := authorizer.AuthorizerServer.PrepareJWT(ctx, &cfg.JWT)
+		if err != nil {
+			return fail(err, "jwt")
+		}
+
+		changes = append(changes, change)
+	}
+
+	if len(plugins) > 0 && authorizer != nil {
+		if runtimes, ok := authorizer.Resolver.GetRuntimeResolver().(pluginReconfigurer); ok {
+			change, err := runtimes.PreparePlugins(ctx, e.Logger, cfg, plugins...)
+			if err != nil {
+				return fail(err, "opa.config.plugins")
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// reloadCORS, replaces the allowed origins and methods of the gateways, with the settings of the
+// services listening on the gateway address.
+func (e *Topaz) reloadCORS(cfg *config.Config) {
+	for _, server := range e.Manager.Servers {
+		if server.Gateway == nil || server.Gateway.CORS == nil {
+			continue
+		}
+
+		for _, api := range cfg.APIConfig.Services {
+			if api.Gateway.ListenAddress == server.Config.Gateway.ListenAddress {
+				server.Gateway.CORS.Update(api.Gateway.AllowedOrigins, api.Gateway.AllowedMethods)
+				break
+			}
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	cerr "github.com/aserto-dev/errors"
 	console "github.com/aserto-dev/go-topaz-ui"
 	"github.com/aserto-dev/topaz/internal/eds"
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
+	"github.com/aserto-dev/topaz/internal/metrics"
 	"github.com/aserto-dev/topaz/pkg/config"
 	"github.com/aserto-dev/topaz/topazd/app/handlers"
 	"github.com/aserto-dev/topaz/topazd/app/middlewares"
@@ -34,10 +36,16 @@ type Topaz struct {
 	ServiceBuilder *builder.ServiceFactory
 	Manager        *builder.ServiceManager
 	Services       map[string]builder.ServiceTypes
+
+	// auth, the authentication middleware shared by the servers, reloaded with the configuration.
+	auth *authentication.APIKeyAuthMiddleware
 }
 
 var healthCheck *health.Server
 
+// PolicyHealthPrefix, prefix of the per policy instance health service name.
+const PolicyHealthPrefix string = "policy/"
+
 func SetServiceStatus(log *zerolog.Logger, service string, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
 	if healthCheck == nil {
 		return
@@ -89,6 +97,15 @@ func (e *Topaz) Start() error {
 		service, servingStatus := "sync", grpc_health_v1.HealthCheckResponse_NOT_SERVING
 		e.Manager.HealthServer.Server.SetServingStatus(service, servingStatus)
 		e.Logger.Info().Str("component", "edge.plugin").Str("service", service).Str("status", servingStatus.String()).Msg("health")
+
+		// register the policy instance services, the runtimes are loaded before the servers are started.
+		if _, ok := e.Configuration.APIConfig.Services["authorizer"]; ok {
+			for _, name := range e.Configuration.PolicyInstanceNames() {
+				service := PolicyHealthPrefix + name
+				e.Manager.HealthServer.Server.SetServingStatus(service, grpc_health_v1.HealthCheckResponse_SERVING)
+				e.Logger.Info().Str("component", "runtime").Str("service", service).Str("status", "SERVING").Msg("health")
+			}
+		}
 	}
 
 	return nil
@@ -109,6 +126,22 @@ func (e *Topaz) ConfigServices() error {
 		return err
 	}
 
+	limiter, err := ratelimit.New(&e.Configuration.RateLimit)
+	if err != nil {
+		return errors.Wrap(err, "rate_limit")
+	}
+
+	if limiter != nil {
+		if err := builder.RegisterCollectors(ratelimit.Collectors()...); err != nil {
+			e.Logger.Warn().Err(err).Msg("failed to register rate limit metrics")
+		}
+	}
+
+	e.auth, err = authentication.NewAPIKeyAuthMiddleware(e.Context, e.Configuration, e.Logger)
+	if err != nil {
+		return err
+	}
+
 	serviceMap := mapToGRPCPorts(e.Configuration.APIConfig.Services)
 
 	for address, cfg := range serviceMap {
@@ -117,17 +150,13 @@ func (e *Topaz) ConfigServices() error {
 		serviceConfig := cfg
 
 		// get middlewares for edge services.
-		opts, err := middlewares.GetMiddlewaresForService(e.Context, e.Configuration, e.Logger)
-		if err != nil {
-			return err
-		}
-
+		opts := middlewares.GetMiddlewaresForService(e.auth, e.Logger, limiter)
 		opts = append(opts, metricsMiddleware...)
 
 		var (
 			grpcs    []builder.GRPCRegistrations
 			gateways []builder.HandlerRegistrations
-			cleanup  func()
+			cleanups []func()
 		)
 
 		for _, serv := range e.Services {
@@ -146,7 +175,7 @@ func (e *Topaz) ConfigServices() error {
 				}
 
 				gateways = append(gateways, serv.GetGatewayRegistration(gatewayPort, serviceConfig.registeredServices...))
-				cleanup = serv.Close
+				cleanups = append(cleanups, serv.Close)
 				added = true
 			}
 		}
@@ -160,6 +189,7 @@ func (e *Topaz) ConfigServices() error {
 						f(server)
 					}
 				},
+				ClientCAs: e.clientCAs(),
 			},
 			&builder.GatewayOptions{
 				HandlerRegistrations: func(ctx context.Context, mux *runtime.ServeMux, grpcEndpoint string, opts []grpc.DialOption) error {
@@ -173,26 +203,29 @@ func (e *Topaz) ConfigServices() error {
 				},
 				ErrorHandler: cerr.CustomErrorHandler,
 			},
-			cleanup,
+			cleanups...,
 		)
 		if err != nil {
 			return err
 		}
 
-		if readerSvc, ok := e.Configuration.APIConfig.Services["reader"]; ok {
-			if err := handlers.SetWellKnownConfigHandler(readerSvc, server.Gateway.Mux); err != nil {
+		if accessSvc, engine, ok := e.accessService(); ok {
+			if err := handlers.SetWellKnownConfigHandler(accessSvc, engine, server.Gateway.Mux); err != nil {
 				return err
 			}
 		}
 
+		if authorizer, ok := e.Services[authorizerService].(*Authorizer); ok {
+			if lo.Contains(serviceConfig.registeredServices, authorizerService) && server.Gateway != nil && server.Gateway.Mux != nil {
+				authorizer.RegisterDataAPI(server.Gateway.Mux, func(path string, next http.Handler) http.Handler {
+					return e.auth.PathHandler(path, limiter.Handler(path, next))
+				})
+			}
+		}
+
 		if con, ok := e.Services[consoleService]; ok {
 			if lo.Contains(serviceConfig.registeredServices, consoleService) {
 				if server.Gateway != nil && server.Gateway.Mux != nil {
-					apiKeyAuthMiddleware, err := authentication.NewAPIKeyAuthMiddleware(e.Context, &e.Configuration.Auth, e.Logger)
-					if err != nil {
-						return err
-					}
-
 					consoleSvc, ok := con.(*ConsoleService)
 					if !ok {
 						return status.Errorf(codes.Internal, "failed type assertion %q", "ConsoleService")
@@ -201,8 +234,8 @@ func (e *Topaz) ConfigServices() error {
 					consoleConfig := consoleSvc.PrepareConfig(e.Configuration)
 
 					// config service.
-					server.Gateway.Mux.Handle("/api/v2/config", apiKeyAuthMiddleware.ConfigAuth(handlers.ConfigHandlerV2(consoleConfig), e.Configuration.Auth))
-					server.Gateway.Mux.HandleFunc("/api/v1/authorizers", handlers.AuthorizersHandler(consoleConfig))
+					server.Gateway.Mux.Handle(configPath, e.auth.ConfigAuth(limiter.Handler(configPath, handlers.ConfigHandlerV2(consoleConfig))))
+					server.Gateway.Mux.Handle(authorizersPath, limiter.Handler(authorizersPath, http.HandlerFunc(handlers.AuthorizersHandler(consoleConfig))))
 					// console service. depends on config service.
 					server.Gateway.Mux.Handle("/ui/", handlers.UIHandler(http.FS(console.FS)))
 					server.Gateway.Mux.Handle("/assets/", handlers.UIHandler(http.FS(console.FS)))
@@ -226,6 +259,9 @@ func (e *Topaz) setupHealthAndMetrics() ([]grpc.ServerOption, error) {
 		}
 	}
 
+	// the label cardinality is bounded by the loaded defaults, also when the metrics are not served.
+	metrics.Configure(&e.Configuration.APIConfig.Metrics.Labels)
+
 	if e.Configuration.APIConfig.Metrics.ListenAddress != "" {
 		metricsMiddleware, err := e.Manager.SetupMetricsServer(e.Configuration.APIConfig.Metrics.ListenAddress,
 			&e.Configuration.APIConfig.Metrics.Certificates,
@@ -248,6 +284,10 @@ func (e *Topaz) prepareServices() error {
 			return err
 		}
 
+		if err := builder.RegisterCollectors(eds.Collectors()...); err != nil {
+			e.Logger.Warn().Err(err).Msg("failed to register directory metrics")
+		}
+
 		edgeDir, err := NewEdgeDir(dir)
 		if err != nil {
 			return err
@@ -263,6 +303,19 @@ func (e *Topaz) prepareServices() error {
 		}
 
 		e.Services["authorizer"] = authorizer
+
+		if authorizer.PolicyAccess() {
+			e.sharePolicyAccess(authorizer, serviceConfig)
+		}
+
+		if _, ok := e.Configuration.APIConfig.Services[extAuthzService]; ok {
+			extAuthz, err := NewExtAuthz(&e.Configuration.ExtAuthz, authorizer.AuthorizerServer.Outcome(), e.Logger)
+			if err != nil {
+				return err
+			}
+
+			e.Services[extAuthzService] = extAuthz
+		}
 	}
 
 	if _, ok := e.Configuration.APIConfig.Services[consoleService]; ok {
@@ -272,6 +325,43 @@ func (e *Topaz) prepareServices() error {
 	return nil
 }
 
+// sharePolicyAccess, when the local directory reader shares the gRPC endpoint of the authorizer,
+// the AuthZEN access service is only served by the authorizer, which forwards the searches, and the
+// calls of the az.* built-ins, to the in-process directory, instead of to itself.
+func (e *Topaz) sharePolicyAccess(authorizer *Authorizer, authorizerConfig *builder.API) {
+	edgeDir, ok := e.Services["edge"].(*EdgeDir)
+	if !ok {
+		return
+	}
+
+	readerConfig, ok := e.Configuration.APIConfig.Services[readerService]
+	if !ok || readerConfig.GRPC.ListenAddress != authorizerConfig.GRPC.ListenAddress {
+		return
+	}
+
+	// the local directory services are disabled by validateConfig when a remote directory is used.
+	if readerConfig.GRPC.ListenAddress != e.Configuration.DirectoryResolver.Address {
+		return
+	}
+
+	edgeDir.DisableAccess()
+	authorizer.SetDirectoryAccess(edgeDir.Access())
+}
+
+// accessService, returns the API of the service serving the AuthZEN endpoints, and the engine of
+// the access evaluations, the authorizer when the policy backed AuthZEN is enabled, otherwise the reader.
+func (e *Topaz) accessService() (*builder.API, string, bool) {
+	if authorizer, ok := e.Services[authorizerService].(*Authorizer); ok && authorizer.PolicyAccess() {
+		return e.Configuration.APIConfig.Services[authorizerService], handlers.EnginePolicy, true
+	}
+
+	if readerSvc, ok := e.Configuration.APIConfig.Services[readerService]; ok {
+		return readerSvc, handlers.EngineDirectory, true
+	}
+
+	return nil, "", false
+}
+
 type services struct {
 	registeredServices []string
 	API                *builder.API
@@ -341,3 +431,12 @@ func (e *Topaz) validateConfig() error {
 
 	return nil
 }
+
+// clientCAs, returns the client CA bundles verifying the client certificates, when mTLS is enabled.
+func (e *Topaz) clientCAs() []string {
+	if !e.Configuration.Auth.MTLS.Enabled {
+		return nil
+	}
+
+	return []string{e.Configuration.Auth.MTLS.ClientCACertPath}
+}
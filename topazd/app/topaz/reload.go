@@ -0,0 +1,51 @@
+package topaz
+
+import (
+	"github.com/aserto-dev/topaz/internal/reload"
+	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/aserto-dev/topaz/topazd/app"
+	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/edge"
+	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
+	"github.com/samber/lo"
+)
+
+// liveSections, the sections of the configuration which are applied without a restart, the
+// client certificate authentication, and enabling or disabling a plugin, require a restart. The
+// plugins of the named policy instances are part of the policies list, which is compared as a
+// whole, a change of the policies requires a restart.
+var liveSections = lo.Flatten([][]string{
+	{
+		"auth.api_keys",
+		"auth.keys",
+		"auth.scoped_keys",
+		"auth.bearer",
+		"auth.options",
+		"jwt",
+		"logging.log_level",
+		app.SectionGateway + "allowed_origins",
+		app.SectionGateway + "allowed_methods",
+	},
+	pluginSections(topaz_file_decision_logger.PluginName, "capture_input", "logger", "policy_info"),
+	pluginSections(edge.PluginName,
+		"addr", "apikey", "timeout", "sync_interval", "insecure", "client_cert_path",
+		"client_key_path", "ca_cert_path", "no_tls", "no_proxy", "headers",
+	),
+})
+
+// pluginSections, returns the sections of the plugin keys, of the default and of the shadow policy instance.
+func pluginSections(plugin string, keys ...string) []string {
+	return lo.FlatMap(keys, func(key string, _ int) []string {
+		return []string{app.SectionPlugins + plugin + "." + key, app.SectionShadowPlugins + plugin + "." + key}
+	})
+}
+
+// NewReloader, returns the reloader of the configuration, which loads the configuration file with the
+// overrides of the command line, and applies the changes of the live sections to the running services.
+// The reloader must be created before the services are configured, which complete the configuration.
+func NewReloader(topazApp *app.Topaz, configPath config.Path, overrides config.Overrider) (*reload.Reloader[*config.Config], error) {
+	load := func() (*config.Config, error) {
+		return config.NewConfig(configPath, topazApp.Logger, overrides, nil)
+	}
+
+	return reload.New(topazApp.Logger, topazApp.Configuration, load, topazApp.ApplyConfig, liveSections...)
+}
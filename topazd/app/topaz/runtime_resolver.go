@@ -2,98 +2,372 @@ package topaz
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aserto-dev/go-authorizer/pkg/aerr"
 	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
 	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/internal/reload"
 	"github.com/aserto-dev/topaz/pkg/config"
-	"github.com/aserto-dev/topaz/topazd/authorizer/builtins"
-	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/az"
-	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/ds"
+	"github.com/aserto-dev/topaz/topazd/app"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/registry"
 	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/edge"
 	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
 	"github.com/aserto-dev/topaz/topazd/authorizer/resolvers"
 	dsa "github.com/authzen/access.go/api/access/v1"
 	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 var _ resolvers.RuntimeResolver = (*RuntimeResolver)(nil)
 
 type RuntimeResolver struct {
-	runtime *runtime.Runtime
+	runtime   *runtime.Runtime
+	instances map[string]*runtime.Runtime
+	prefixes  map[string]string
+	names     []string
+	shadow    *runtime.Runtime
+	watchers  []*policywatch.Watcher
 }
 
+// NewRuntimeResolver, creates the runtimes of the policy instances, the directory (ds.*) built-ins
+// call the directory of the connection, the authZen (az.*) built-ins call the access client, when
+// set, otherwise the access service of the directory connection.
 func NewRuntimeResolver(
 	ctx context.Context,
 	logger *zerolog.Logger,
 	cfg *config.Config,
 	dsConn *grpc.ClientConn,
+	access dsa.AccessClient,
 ) (resolvers.RuntimeResolver, func(), error) {
-	dsClient := dsr.NewReaderClient(dsConn)
-	acClient := dsa.NewAccessClient(dsConn)
-
-	sidecarRuntime, err := runtime.New(ctx, &cfg.OPA,
-
-		// directory get functions
-		runtime.WithBuiltin1(ds.RegisterIdentity(logger, builtins.DSIdentity, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterUser(logger, builtins.DSUser, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterObject(logger, builtins.DSObject, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterRelation(logger, builtins.DSRelation, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterRelations(logger, builtins.DSRelations, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterGraph(logger, builtins.DSGraph, dsClient)),
-
-		// authorization check functions
-		runtime.WithBuiltin1(ds.RegisterCheck(logger, builtins.DSCheck, dsClient)),
-		runtime.WithBuiltin1(ds.RegisterChecks(logger, builtins.DSChecks, dsClient)),
-
-		// authZen built-ins
-		runtime.WithBuiltin1(az.RegisterEvaluation(logger, builtins.AZEvaluation, acClient)),
-		runtime.WithBuiltin1(az.RegisterEvaluations(logger, builtins.AZEvaluations, acClient)),
-		runtime.WithBuiltin1(az.RegisterSubjectSearch(logger, builtins.AZSubjectSearch, acClient)),
-		runtime.WithBuiltin1(az.RegisterResourceSearch(logger, builtins.AZResourceSearch, acClient)),
-		runtime.WithBuiltin1(az.RegisterActionSearch(logger, builtins.AZActionSearch, acClient)),
+	if access == nil {
+		access = dsa.NewAccessClient(dsConn)
+	}
+
+	reader := dsr.NewReaderClient(dsConn)
+
+	resolver := &RuntimeResolver{
+		instances: map[string]*runtime.Runtime{},
+		prefixes:  map[string]string{},
+	}
+
+	runtimes := []*runtime.Runtime{}
+
+	cleanup := func() {
+		for _, rt := range runtimes {
+			rt.Stop(ctx)
+		}
+
+		for _, w := range resolver.watchers {
+			w.Close()
+		}
+	}
+
+	if err := resolver.watchLocalBundles(ctx, logger, cfg.OPA.InstanceID, &cfg.OPA); err != nil {
+		return nil, cleanup, err
+	}
+
+	defaultRuntime, err := newRuntime(ctx, logger, cfg, &cfg.OPA, reader, access)
+	if defaultRuntime != nil {
+		runtimes = append(runtimes, defaultRuntime)
+	}
 
+	if err != nil {
+		return nil, cleanup, err
+	}
+
+	resolver.runtime = defaultRuntime
+	resolver.instances[cfg.OPA.InstanceID] = defaultRuntime
+	resolver.names = append(resolver.names, cfg.OPA.InstanceID)
+
+	for _, policy := range cfg.Policies {
+		policyLogger := logger.With().Str("policy-instance", policy.Name).Logger()
+
+		if err := resolver.watchLocalBundles(ctx, &policyLogger, policy.Name, &policy.OPA); err != nil {
+			return nil, cleanup, errors.Wrapf(err, "policy instance %q", policy.Name)
+		}
+
+		rt, err := newRuntime(ctx, &policyLogger, cfg, &policy.OPA, reader, access)
+		if rt != nil {
+			runtimes = append(runtimes, rt)
+		}
+
+		if err != nil {
+			return nil, cleanup, errors.Wrapf(err, "policy instance %q", policy.Name)
+		}
+
+		resolver.instances[policy.Name] = rt
+		resolver.names = append(resolver.names, policy.Name)
+
+		if policy.PathPrefix != "" {
+			resolver.prefixes[policy.PathPrefix] = policy.Name
+		}
+	}
+
+	for name, rt := range resolver.instances {
+		registerHealthListener(logger, name, rt)
+	}
+
+	if cfg.Shadow != nil {
+		shadowLogger := logger.With().Str("policy-instance", cfg.Shadow.OPA.InstanceID).Bool("shadow", true).Logger()
+
+		// the shadow policy must not affect the primary policy instances, a shadow policy
+		// which fails to load is disabled instead of failing the start of the authorizer.
+		rt, err := newRuntime(ctx, &shadowLogger, cfg, &cfg.Shadow.OPA, reader, access)
+		if rt != nil {
+			runtimes = append(runtimes, rt)
+		}
+
+		if err != nil {
+			shadowLogger.Error().Err(err).Msg("shadow policy disabled")
+		} else {
+			resolver.shadow = rt
+		}
+	}
+
+	return resolver, cleanup, nil
+}
+
+// newRuntime, creates and starts the runtime of a policy instance, the runtime is returned on a
+// start error, so the caller can stop it.
+func newRuntime(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	cfg *config.Config,
+	opaCfg *runtime.Config,
+	reader dsr.ReaderClient,
+	access dsa.AccessClient,
+) (*runtime.Runtime, error) {
+	fns := registry.Builtins(logger, reader, access)
+	opts := make([]runtime.Option, 0, len(fns)+3)
+
+	// directory (ds.*) and authZen (az.*) built-ins
+	for _, fn := range fns {
+		opts = append(opts, runtime.WithBuiltin1(fn.Decl, fn.Impl))
+	}
+
+	opts = append(opts,
 		// plugins
 		runtime.WithPlugin(topaz_file_decision_logger.PluginName, topaz_file_decision_logger.NewFactory(logger.WithContext(ctx))),
 		runtime.WithPlugin(edge.PluginName, edge.NewPluginFactory(ctx, cfg, logger)),
 
 		runtime.WithRegoVersion(ast.RegoV0),
 	)
+
+	sidecarRuntime, err := runtime.New(ctx, opaCfg, opts...)
 	if err != nil {
-		return nil, func() {}, err
+		return nil, err
 	}
 
-	cleanupRuntime := func() {
-		sidecarRuntime.Stop(ctx)
+	if err := sidecarRuntime.Start(ctx); err != nil {
+		return sidecarRuntime, err
 	}
 
-	cleanup := func() {
-		if cleanupRuntime != nil {
-			cleanupRuntime()
+	if err := sidecarRuntime.WaitForPlugins(ctx, time.Duration(opaCfg.MaxPluginWaitTimeSeconds)*time.Second); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return sidecarRuntime, aerr.ErrRuntimeLoading.Err(err).Msg("timeout while waiting for runtime to load")
 		}
+
+		return sidecarRuntime, aerr.ErrBadRuntime.Err(err)
 	}
 
-	if err := sidecarRuntime.Start(ctx); err != nil {
-		return nil, cleanup, err
+	return sidecarRuntime, nil
+}
+
+// watchLocalBundles, starts a policy watcher for each watched local bundle directory of the
+// policy instance, and points the runtime at the staging directories of the watchers, so a
+// policy edit which fails to compile never replaces the last good policy of the runtime.
+func (r *RuntimeResolver) watchLocalBundles(ctx context.Context, logger *zerolog.Logger, name string, opaCfg *runtime.Config) error {
+	if !opaCfg.LocalBundles.Watch {
+		return nil
 	}
 
-	if err := sidecarRuntime.WaitForPlugins(ctx, time.Duration(cfg.OPA.MaxPluginWaitTimeSeconds)*time.Second); err != nil {
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, cleanup, aerr.ErrRuntimeLoading.Err(err).Msg("timeout while waiting for runtime to load")
+	paths := slices.Clone(opaCfg.LocalBundles.Paths)
+
+	for i, path := range paths {
+		// bundle files are loaded, and watched, by the runtime.
+		if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+			continue
+		}
+
+		staging, err := os.MkdirTemp("", "topaz-policy-")
+		if err != nil {
+			return errors.Wrap(err, "failed to create policy staging directory")
 		}
 
-		return nil, cleanup, aerr.ErrBadRuntime.Err(err)
+		w := policywatch.New(logger, name, path, staging, opaCfg.LocalBundles.Ignore, registry.Decls(logger))
+		r.watchers = append(r.watchers, w)
+
+		// a policy which fails to compile at start is reported by the policy status, the
+		// runtime starts without the policy and loads it once it compiles.
+		w.Sync()
+
+		if err := w.Start(ctx); err != nil {
+			return err
+		}
+
+		paths[i] = w.Staging()
 	}
 
-	return &RuntimeResolver{
-		runtime: sidecarRuntime,
-	}, cleanup, err
+	opaCfg.LocalBundles.Paths = paths
+
+	return nil
 }
 
-func (r *RuntimeResolver) GetRuntime(ctx context.Context) (*runtime.Runtime, error) {
+// registerHealthListener, reports the health of the policy instance as service policy/{name},
+// the instance is serving when all its plugins are operating normally.
+func registerHealthListener(logger *zerolog.Logger, name string, rt *runtime.Runtime) {
+	pm := rt.GetPluginsManager()
+	if pm == nil {
+		return
+	}
+
+	pm.RegisterPluginStatusListener(app.PolicyHealthPrefix+name, func(status map[string]*plugins.Status) {
+		servingStatus := grpc_health_v1.HealthCheckResponse_SERVING
+
+		for _, s := range status {
+			if s != nil && s.State != plugins.StateOK {
+				servingStatus = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		app.SetServiceStatus(logger, app.PolicyHealthPrefix+name, servingStatus)
+	})
+}
+
+// GetRuntime, returns the runtime of the named policy instance, when the name is not a configured
+// policy instance, the runtime of the policy instance with the longest matching path prefix, or the
+// default runtime.
+//
+// Existing clients send the policy instance name of their policy image (e.g. "policy-rebac"), which
+// before named policy instances selected the single runtime, unknown names therefore do not fail.
+func (r *RuntimeResolver) GetRuntime(ctx context.Context, name, path string) (*runtime.Runtime, error) {
+	if instance := r.selectInstance(name, path); instance != "" {
+		return r.instances[instance], nil
+	}
+
 	return r.runtime, nil
 }
+
+// selectInstance, returns the name of the policy instance selected by name or by path prefix,
+// empty when the default policy instance is selected.
+func (r *RuntimeResolver) selectInstance(name, path string) string {
+	if _, ok := r.instances[name]; ok && name != "" {
+		return name
+	}
+
+	match, instance := "", ""
+
+	for prefix, owner := range r.prefixes {
+		if (path == prefix || strings.HasPrefix(path, prefix+".")) && len(prefix) > len(match) {
+			match, instance = prefix, owner
+		}
+	}
+
+	return instance
+}
+
+// Instances, returns the names of the policy instances, starting with the default instance.
+func (r *RuntimeResolver) Instances() []string {
+	return r.names
+}
+
+// Shadow, returns the runtime of the shadow policy, nil when no shadow policy is configured.
+func (r *RuntimeResolver) Shadow() *runtime.Runtime {
+	return r.shadow
+}
+
+// PolicyStatus, returns the compile status of the watched local policy directories.
+func (r *RuntimeResolver) PolicyStatus() []*policywatch.Status {
+	statuses := make([]*policywatch.Status, 0, len(r.watchers))
+
+	for _, w := range r.watchers {
+		statuses = append(statuses, w.Status())
+	}
+
+	return statuses
+}
+
+// PreparePlugins, validates the configuration of the named plugins of the policy instances, the default,
+// the named and the shadow policy instances, each with its plugin configuration of the reloaded
+// configuration, the change reconfigures the plugins of all policy instances when committed.
+func (r *RuntimeResolver) PreparePlugins(ctx context.Context, logger *zerolog.Logger, cfg *config.Config, names ...string) (*reload.Change, error) {
+	factories := map[string]plugins.Factory{
+		topaz_file_decision_logger.PluginName: topaz_file_decision_logger.NewFactory(logger.WithContext(ctx)),
+		edge.PluginName:                       edge.NewPluginFactory(ctx, cfg, logger),
+	}
+
+	for _, name := range names {
+		if _, ok := factories[name]; !ok {
+			return nil, errors.Errorf("plugin %q cannot be reconfigured", name)
+		}
+	}
+
+	type reconfiguration struct {
+		plugin plugins.Plugin
+		config any
+	}
+
+	reconfigurations := []reconfiguration{}
+
+	for rt, opaCfg := range r.instanceConfigs(cfg) {
+		pm := rt.GetPluginsManager()
+		if pm == nil {
+			continue
+		}
+
+		for _, name := range names {
+			plugin := pm.Plugin(name)
+			if plugin == nil {
+				continue
+			}
+
+			raw, err := json.Marshal(opaCfg.Config.Plugins[name])
+			if err != nil {
+				return nil, errors.Wrapf(err, "policy instance %q plugin %q", opaCfg.InstanceID, name)
+			}
+
+			pluginCfg, err := factories[name].Validate(pm, raw)
+			if err != nil {
+				return nil, errors.Wrapf(err, "policy instance %q plugin %q", opaCfg.InstanceID, name)
+			}
+
+			reconfigurations = append(reconfigurations, reconfiguration{plugin: plugin, config: pluginCfg})
+		}
+	}
+
+	commit := func() {
+		for _, rc := range reconfigurations {
+			rc.plugin.Reconfigure(ctx, rc.config)
+		}
+	}
+
+	return reload.NewChange(commit, nil), nil
+}
+
+// instanceConfigs, returns the OPA configuration of the reloaded configuration of each running policy
+// instance, a named policy instance which is not in the reloaded configuration keeps its configuration.
+func (r *RuntimeResolver) instanceConfigs(cfg *config.Config) map[*runtime.Runtime]*runtime.Config {
+	configs := map[*runtime.Runtime]*runtime.Config{r.runtime: &cfg.OPA}
+
+	for _, policy := range cfg.Policies {
+		if rt, ok := r.instances[policy.Name]; ok && rt != r.runtime {
+			configs[rt] = &policy.OPA
+		}
+	}
+
+	if r.shadow != nil && cfg.Shadow != nil {
+		configs[r.shadow] = &cfg.Shadow.OPA
+	}
+
+	return configs
+}
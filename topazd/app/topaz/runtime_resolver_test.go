@@ -0,0 +1,63 @@
+package topaz
+
+import (
+	"testing"
+
+	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectInstance(t *testing.T) {
+	r := &RuntimeResolver{
+		instances: map[string]*runtime.Runtime{"-": {}, "rebac": {}, "tenants": {}, "acme": {}},
+		prefixes:  map[string]string{"tenants": "tenants", "tenants.acme": "acme"},
+	}
+
+	tests := []struct {
+		name     string
+		instance string
+		path     string
+		expected string
+	}{
+		{"named instance", "rebac", "rebac.check", "rebac"},
+		{"named instance overrides prefix", "rebac", "tenants.acme.GET", "rebac"},
+		{"default instance by name", "-", "tenants.GET", "-"},
+		{"unknown name falls back to default", "policy-rebac", "rebac.check", ""},
+		{"unknown name falls back to prefix", "policy-rebac", "tenants.GET", "tenants"},
+		{"no name longest prefix", "", "tenants.acme.GET", "acme"},
+		{"no name prefix requires segment boundary", "", "tenantsx.GET", ""},
+		{"no name exact prefix", "", "tenants", "tenants"},
+		{"no name no prefix", "", "rebac.check", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, r.selectInstance(tc.instance, tc.path))
+		})
+	}
+}
+
+func TestInstanceConfigs(t *testing.T) {
+	def, rebac, removed, shadow := &runtime.Runtime{}, &runtime.Runtime{}, &runtime.Runtime{}, &runtime.Runtime{}
+
+	r := &RuntimeResolver{
+		runtime:   def,
+		instances: map[string]*runtime.Runtime{"-": def, "rebac": rebac, "removed": removed},
+		shadow:    shadow,
+	}
+
+	cfg := &config.Config{
+		OPA:      runtime.Config{InstanceID: "-"},
+		Policies: []*config.PolicyInstance{{Name: "rebac"}, {Name: "added"}},
+		Shadow:   &config.ShadowPolicy{},
+	}
+
+	configs := r.instanceConfigs(cfg)
+
+	assert.Len(t, configs, 3)
+	assert.Same(t, &cfg.OPA, configs[def])
+	assert.Same(t, &cfg.Policies[0].OPA, configs[rebac])
+	assert.Same(t, &cfg.Shadow.OPA, configs[shadow])
+	assert.NotContains(t, configs, removed)
+}
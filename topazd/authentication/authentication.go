@@ -4,15 +4,18 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/aserto-dev/topaz/internal/header"
 	"github.com/aserto-dev/topaz/topazd/app/handlers"
+	"github.com/aserto-dev/topaz/topazd/authentication/mtls"
 	"github.com/rs/zerolog"
 )
 
-func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler, authCfg config.AuthnConfig) http.Handler {
+func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// if no API keys are defined or EnableAPIKey is not set, allow the request
-		options := authCfg.Options.ForPath(r.URL.Path)
+		state := a.state.Load()
+
+		// if no API keys are defined and mTLS and bearer tokens are disabled, or EnableAPIKey is not set, allow the request
+		options := state.cfg.Options.ForPath(r.URL.Path)
 
 		if options.EnableAnonymous {
 			ctx := context.WithValue(r.Context(), handlers.AuthenticatedUser, true)
@@ -21,7 +24,7 @@ func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler, authCfg config.AuthnCo
 			return
 		}
 
-		if (len(authCfg.APIKeys) == 0) || !options.EnableAPIKey {
+		if !a.enabled(state) || !options.EnableAPIKey {
 			ctx := context.WithValue(r.Context(), handlers.AuthenticatedUser, true)
 			h.ServeHTTP(w, r.WithContext(ctx))
 
@@ -31,6 +34,27 @@ func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler, authCfg config.AuthnCo
 		// if we reached this point, auth is enabled
 		ctx := context.WithValue(r.Context(), handlers.AuthEnabled, true)
 
+		caller, err := a.clientCert(func() (*mtls.Caller, error) { return a.certs.AuthenticateConn(r.TLS, "") })
+		if err != nil {
+			returnStatusUnauthorized(w, "The client certificate is invalid.", a.logger)
+
+			return
+		}
+
+		if caller != nil {
+			if !caller.Allows(r.URL.Path) {
+				returnStatusUnauthorized(w, "The client certificate is not allowed.", a.logger)
+
+				return
+			}
+
+			ctx = context.WithValue(ctx, handlers.AuthenticatedUser, true)
+			ctx = header.ContextWithClientIdentity(ctx, caller.Name())
+			h.ServeHTTP(w, r.WithContext(ctx))
+
+			return
+		}
+
 		authHeader := httpAuthHeader(r)
 		if authHeader == "" {
 			// auth header is not present =>  the user is unauthenticated and did not provide a token
@@ -40,6 +64,21 @@ func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler, authCfg config.AuthnCo
 			return
 		}
 
+		if token, ok := bearerToken(state, authHeader); ok {
+			caller, err := a.authorizeToken(r.Context(), state, token, r.URL.Path)
+			if err != nil {
+				returnStatusUnauthorized(w, "The bearer token is invalid.", a.logger)
+
+				return
+			}
+
+			ctx = context.WithValue(ctx, handlers.AuthenticatedUser, true)
+			ctx = header.ContextWithTokenSubject(ctx, caller.Name())
+			h.ServeHTTP(w, r.WithContext(ctx))
+
+			return
+		}
+
 		basicAPIKey, err := parseAuthHeader(authHeader, "basic")
 		if err != nil {
 			returnStatusUnauthorized(w, "Invalid authorization header. expected 'basic' scheme.", a.logger)
@@ -47,14 +86,15 @@ func (a *APIKeyAuthMiddleware) ConfigAuth(h http.Handler, authCfg config.AuthnCo
 			return
 		}
 
-		if _, ok := authCfg.APIKeys[basicAPIKey]; ok {
+		if id, err := a.authorize(state, basicAPIKey, r.URL.Path); err == nil {
 			ctx = context.WithValue(ctx, handlers.AuthenticatedUser, true)
+			ctx = header.ContextWithAPIKeyName(ctx, id.Name())
 			h.ServeHTTP(w, r.WithContext(ctx))
 
 			return
 		}
 
-		// the user is not authenticated because the key they provided is incorrect
+		// the user is not authenticated because the key they provided is incorrect, expired or not allowed
 		returnStatusUnauthorized(w, "The API key is invalid.", a.logger)
 	})
 }
@@ -0,0 +1,229 @@
+package bearer
+
+import (
+	"context"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/pkg/errors"
+
+	"github.com/jwx-go/jwkfetch/v4"
+	"github.com/lestrrat-go/httprc/v3"
+	"github.com/lestrrat-go/jwx/v4/jwk"
+	"github.com/lestrrat-go/jwx/v4/jws"
+	"github.com/lestrrat-go/jwx/v4/jwt"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid bearer token")
+	ErrUnknownIssuer = errors.New("unknown token issuer")
+	ErrNotAllowed    = errors.New("token claims do not match any rule")
+)
+
+// Caller, an authenticated bearer token.
+type Caller struct {
+	keys.Scope
+
+	name   string
+	issuer string
+}
+
+// Name, returns the subject of the token.
+func (c *Caller) Name() string {
+	return c.name
+}
+
+// Issuer, returns the issuer of the token.
+func (c *Caller) Issuer() string {
+	return c.issuer
+}
+
+// Authenticator, verifies bearer tokens and maps their claims to a scope.
+type Authenticator struct {
+	cfg        *Config
+	issuers    map[string]*Issuer
+	staticKeys map[string]jwk.Set
+	rules      []*rule
+	cache      *jwkfetch.Cache
+}
+
+// New, returns the authenticator of the configuration, the static keys of the issuers are loaded,
+// and the key sets of the JWKS URLs are registered in the key set cache, which is refreshed until
+// the context is done.
+func New(ctx context.Context, cfg *Config) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{
+		cfg:        cfg,
+		issuers:    map[string]*Issuer{},
+		staticKeys: map[string]jwk.Set{},
+	}
+
+	for _, r := range cfg.Rules {
+		rule, err := newRule(r)
+		if err != nil {
+			return nil, err
+		}
+
+		a.rules = append(a.rules, rule)
+	}
+
+	for _, issuer := range cfg.Issuers {
+		a.issuers[issuer.Issuer] = issuer
+
+		if !issuer.HasStaticKeys() {
+			continue
+		}
+
+		keySet, err := loadStaticKeys(issuer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "issuer %q", issuer.Issuer)
+		}
+
+		a.staticKeys[issuer.Issuer] = keySet
+	}
+
+	if len(a.staticKeys) == len(a.issuers) {
+		return a, nil
+	}
+
+	cache, err := jwkfetch.NewCache(ctx, httprc.NewClient())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issuer := range cfg.Issuers {
+		if issuer.JWKSURL == "" {
+			continue
+		}
+
+		if err := cache.Register(
+			ctx,
+			issuer.JWKSURL,
+			jwkfetch.WithWaitReady(true),
+			jwkfetch.WithMinInterval(cfg.CacheRefreshMinIntervalDuration()),
+			jwkfetch.WithMaxInterval(cfg.CacheRefreshMaxIntervalDuration()),
+		); err != nil {
+			return nil, errors.Wrapf(err, "issuer %q", issuer.Issuer)
+		}
+	}
+
+	a.cache = cache
+
+	return a, nil
+}
+
+// Authenticate, verifies the token, which is expected to be a string in JWS compact serialization format,
+// and returns the caller, with the scope of the rules matching the claims of the token.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Caller, error) {
+	unverified, err := tokenSegment(token, 1)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	iss, _ := unverified["iss"].(string)
+
+	issuer, ok := a.issuers[iss]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownIssuer, "%q", iss)
+	}
+
+	if err := verifyAlgorithm(token, issuer.Algorithms); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	keySet, err := a.keySet(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedOptions := []jwt.ParseOption{
+		jwt.WithValidate(true),
+		jwt.WithAcceptableSkew(a.cfg.AcceptableTimeSkewDuration()),
+	}
+
+	if issuer.HasStaticKeys() {
+		// PEM encoded public keys carry neither a key id nor an algorithm.
+		verifiedOptions = append(verifiedOptions, jwt.WithKeySet(keySet, jws.WithInferAlgorithmFromKey(true), jws.WithRequireKid(false)))
+	} else {
+		verifiedOptions = append(verifiedOptions, jwt.WithKeySet(keySet))
+	}
+
+	if _, err := jwt.ParseString(token, verifiedOptions...); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	// the signature is verified, the claims of the token can be trusted.
+	claims := unverified
+
+	if !hasAudience(claims, issuer.Audiences) {
+		return nil, errors.Wrap(ErrInvalidToken, "audience not allowed")
+	}
+
+	subjectClaim := issuer.SubjectClaim
+	if subjectClaim == "" {
+		subjectClaim = defaultSubjectClaim
+	}
+
+	subject, ok := claimValue(claims, subjectClaim).(string)
+	if !ok || subject == "" {
+		return nil, errors.Wrapf(ErrInvalidToken, "no subject found in claim %q", subjectClaim)
+	}
+
+	s, err := scope(a.rules, claims)
+	if err != nil {
+		return nil, errors.Wrap(err, subject)
+	}
+
+	return &Caller{Scope: s, name: subject, issuer: iss}, nil
+}
+
+// keySet, returns the static key set of the issuer, or the key set fetched from its JWKS URL.
+func (a *Authenticator) keySet(ctx context.Context, issuer *Issuer) (jwk.Set, error) {
+	if keySet, ok := a.staticKeys[issuer.Issuer]; ok {
+		return keySet, nil
+	}
+
+	keySet, err := a.cache.Fetch(ctx, issuer.JWKSURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the key set of issuer %q", issuer.Issuer)
+	}
+
+	return keySet, nil
+}
+
+// loadStaticKeys, returns the key set of the issuer JWKS file and PEM encoded public keys.
+func loadStaticKeys(issuer *Issuer) (jwk.Set, error) {
+	keySet := jwk.NewSet()
+
+	if issuer.JWKSFile != "" {
+		fileSet, err := jwk.ReadFile(issuer.JWKSFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read jwks file %q", issuer.JWKSFile)
+		}
+
+		for i := range fileSet.Len() {
+			key, _ := fileSet.Key(i)
+			if err := keySet.AddKey(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, pem := range issuer.PublicKeys {
+		pemSet, err := jwk.Parse([]byte(pem), jwk.WithPEM(true))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse public_keys[%d]", i)
+		}
+
+		for j := range pemSet.Len() {
+			key, _ := pemSet.Key(j)
+			if err := keySet.AddKey(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return keySet, nil
+}
@@ -0,0 +1,137 @@
+package bearer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/pkg/errors"
+)
+
+const (
+	claimPathSeparator = "."
+	jwsSegments        = 3
+)
+
+// claimValue, returns the value of the claim, the claim name is either a top level claim name,
+// like https://example.com/roles, or a dot separated path of a nested claim, like realm_access.roles.
+func claimValue(claims map[string]any, claim string) any {
+	if v, ok := claims[claim]; ok {
+		return v
+	}
+
+	var v any = claims
+
+	for _, name := range strings.Split(claim, claimPathSeparator) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		v = m[name]
+	}
+
+	return v
+}
+
+// containsValue, returns true when the claim value equals the value, contains it as an element of
+// an array, or as a space separated element of a string, like the OAuth 2.0 scope claim.
+func containsValue(v any, value string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == value || slices.Contains(strings.Fields(v), value)
+	case []any:
+		return slices.ContainsFunc(v, func(e any) bool { return fmt.Sprint(e) == value })
+	case bool, float64:
+		return fmt.Sprint(v) == value
+	default:
+		return false
+	}
+}
+
+// scope, returns the scope of the rules matching the claims, ErrNotAllowed when no rule matches,
+// all methods are allowed when no rules are configured.
+func scope(rules []*rule, claims map[string]any) (keys.Scope, error) {
+	if len(rules) == 0 {
+		return keys.Scope{}, nil
+	}
+
+	allow := []string{}
+
+	for _, r := range rules {
+		if r.matches(claims) {
+			allow = append(allow, r.allow...)
+		}
+	}
+
+	if len(allow) == 0 {
+		return keys.Scope{}, ErrNotAllowed
+	}
+
+	return keys.NewScope(allow)
+}
+
+// hasAudience, returns true when the aud claim contains one of the audiences, or no audiences are configured.
+func hasAudience(claims map[string]any, audiences []string) bool {
+	if len(audiences) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(audiences, func(aud string) bool {
+		return containsExact(claims["aud"], aud)
+	})
+}
+
+// containsExact, returns true when the string claim equals the value, or the array claim contains it.
+func containsExact(v any, value string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == value
+	case []any:
+		return slices.Contains(v, any(value))
+	default:
+		return false
+	}
+}
+
+// verifyAlgorithm, verifies the signing algorithm of the token header is allowed, when algorithms are configured.
+func verifyAlgorithm(token string, algorithms []string) error {
+	if len(algorithms) == 0 {
+		return nil
+	}
+
+	header, err := tokenSegment(token, 0)
+	if err != nil {
+		return err
+	}
+
+	alg, _ := header["alg"].(string)
+	if !slices.Contains(algorithms, alg) {
+		return errors.Errorf("signing algorithm %q not allowed", alg)
+	}
+
+	return nil
+}
+
+// tokenSegment, returns the decoded JSON of the header (0) or payload (1) segment of a compact serialized token.
+func tokenSegment(token string, index int) (map[string]any, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != jwsSegments {
+		return nil, errors.Errorf("invalid token format")
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(segments[index])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid token encoding")
+	}
+
+	v := map[string]any{}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return nil, errors.Wrap(err, "invalid token json")
+	}
+
+	return v, nil
+}
@@ -0,0 +1,112 @@
+package bearer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	cfg := &Config{
+		Rules: []*Rule{
+			{Claim: "scope", Contains: "directory.read", Allow: []string{"reader"}},
+			{Claim: "scope", Contains: "directory.write", Allow: []string{"writer", "model"}},
+			{Claim: "realm_access.roles", Contains: "admin"},
+		},
+	}
+
+	rules := make([]*rule, 0, len(cfg.Rules))
+
+	for _, r := range cfg.Rules {
+		rule, err := newRule(r)
+		require.NoError(t, err)
+
+		rules = append(rules, rule)
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		method  string
+		allowed bool
+	}{
+		{"read scope", map[string]any{"scope": "openid directory.read"}, "/aserto.directory.reader.v3.Reader/GetObject", true},
+		{"read scope write", map[string]any{"scope": "openid directory.read"}, "/aserto.directory.writer.v3.Writer/SetObject", false},
+		{"write scope", map[string]any{"scope": "directory.write"}, "/aserto.directory.writer.v3.Writer/SetObject", true},
+		{"array scope", map[string]any{"scope": []any{"directory.read", "directory.write"}}, "/aserto.directory.model.v3.Model/GetManifest", true},
+		{"nested role", map[string]any{"realm_access": map[string]any{"roles": []any{"admin"}}}, "/aserto.authorizer.v2.Authorizer/Is", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scope(rules, tt.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, s.Allows(tt.method))
+		})
+	}
+
+	_, err := scope(rules, map[string]any{"scope": "openid"})
+	require.ErrorIs(t, err, ErrNotAllowed)
+
+	// without rules, a verified token is allowed to call all services.
+	s, err := scope(nil, map[string]any{})
+	require.NoError(t, err)
+	assert.True(t, s.Allows("/aserto.directory.writer.v3.Writer/SetObject"))
+}
+
+func TestHasAudience(t *testing.T) {
+	assert.True(t, hasAudience(map[string]any{}, nil))
+	assert.True(t, hasAudience(map[string]any{"aud": "topaz"}, []string{"topaz"}))
+	assert.True(t, hasAudience(map[string]any{"aud": []any{"other", "topaz"}}, []string{"topaz"}))
+	assert.False(t, hasAudience(map[string]any{"aud": "other"}, []string{"topaz"}))
+	assert.False(t, hasAudience(map[string]any{}, []string{"topaz"}))
+}
+
+func TestVerifyAlgorithm(t *testing.T) {
+	header, err := json.Marshal(map[string]any{"alg": "HS256"})
+	require.NoError(t, err)
+
+	token := base64.RawURLEncoding.EncodeToString(header) + ".e30.sig"
+
+	require.NoError(t, verifyAlgorithm(token, nil))
+	require.NoError(t, verifyAlgorithm(token, []string{"RS256", "HS256"}))
+	require.Error(t, verifyAlgorithm(token, []string{"RS256"}))
+	require.Error(t, verifyAlgorithm("not-a-token", []string{"RS256"}))
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Enabled: true,
+			Issuers: []*Issuer{{Issuer: "https://issuer.example.com", JWKSURL: "https://issuer.example.com/jwks.json"}},
+			Rules:   []*Rule{{Claim: "scope", Contains: "directory.read", Allow: []string{"reader"}}},
+		}
+	}
+
+	require.NoError(t, valid().Validate())
+	require.NoError(t, (&Config{}).Validate())
+
+	tests := map[string]func(*Config){
+		"no issuers":     func(c *Config) { c.Issuers = nil },
+		"no issuer":      func(c *Config) { c.Issuers[0].Issuer = "" },
+		"duplicate":      func(c *Config) { c.Issuers = append(c.Issuers, c.Issuers[0]) },
+		"no keys":        func(c *Config) { c.Issuers[0].JWKSURL = "" },
+		"url and static": func(c *Config) { c.Issuers[0].JWKSFile = "jwks.json" },
+		"bad url":        func(c *Config) { c.Issuers[0].JWKSURL = "file:///jwks.json" },
+		"negative skew":  func(c *Config) { c.AcceptableTimeSkewSeconds = -1 },
+		"rule claim":     func(c *Config) { c.Rules[0].Claim = "" },
+		"rule contains":  func(c *Config) { c.Rules[0].Contains = "" },
+		"rule service":   func(c *Config) { c.Rules[0].Allow = []string{"reeder"} },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid()
+			mutate(cfg)
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
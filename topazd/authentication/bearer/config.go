@@ -0,0 +1,167 @@
+// Package bearer, JWT bearer token authentication, tokens are verified using the keys of the
+// configured issuers, fetched from their JWKS URL or loaded from static key sources, and are
+// scoped to a list of services or gRPC method globs by rules on their claims.
+package bearer
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSubjectClaim            = "sub"
+	defaultCacheRefreshMinInterval = 5 * time.Minute
+	defaultCacheRefreshMaxInterval = 15 * time.Minute
+)
+
+// Config, bearer token authentication configuration.
+type Config struct {
+	// Enabled, accept `Authorization: Bearer <jwt>` for API calls.
+	Enabled bool `json:"enabled"`
+	// AcceptableTimeSkewSeconds, duration which the exp, nbf and iat claims may differ.
+	AcceptableTimeSkewSeconds int `json:"acceptable_time_skew_seconds"`
+	// CacheRefreshMinInterval, CacheRefreshMaxInterval, refresh intervals of the fetched key sets (duration strings).
+	CacheRefreshMinInterval string `json:"cache_refresh_min_interval"`
+	CacheRefreshMaxInterval string `json:"cache_refresh_max_interval"`
+	// Issuers, the trusted token issuers.
+	Issuers []*Issuer `json:"issuers"`
+	// Rules, the services or gRPC method globs a token can call, based on its claims,
+	// a verified token is allowed to call all services when empty.
+	Rules []*Rule `json:"rules"`
+}
+
+// Issuer, a trusted token issuer, its keys are fetched from the JWKS URL, or loaded from the
+// JWKS file and PEM encoded public keys.
+type Issuer struct {
+	Issuer       string   `json:"issuer"`
+	Audiences    []string `json:"audiences"`     // the aud claim must contain one of the audiences, when set
+	JWKSURL      string   `json:"jwks_url"`      // URL of the JWKS of the issuer
+	JWKSFile     string   `json:"jwks_file"`     // path of a static JWKS file
+	PublicKeys   []string `json:"public_keys"`   // static PEM encoded public keys
+	Algorithms   []string `json:"algorithms"`    // allowed signing algorithms, when empty any algorithm of the key set is allowed
+	SubjectClaim string   `json:"subject_claim"` // claim, or dot separated nested claim path, used as caller identity (default sub)
+}
+
+// HasStaticKeys, returns true when the issuer keys are provided in the configuration.
+func (i *Issuer) HasStaticKeys() bool {
+	return i.JWKSFile != "" || len(i.PublicKeys) > 0
+}
+
+// Rule, allows tokens with a claim containing the value, to call the services or gRPC method globs.
+type Rule struct {
+	// Claim, claim name, or dot separated nested claim path, like scope or realm_access.roles.
+	Claim string `json:"claim"`
+	// Contains, value of the claim, an element of an array claim, or a space separated element of a string claim.
+	Contains string `json:"contains"`
+	// Allow, the services (reader, writer, authorizer, ...) or gRPC method globs the token can call, all when empty.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// Validate, validates the configuration, without loading or fetching the issuer keys.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if len(c.Issuers) == 0 {
+		return errors.New("issuers not set")
+	}
+
+	if c.AcceptableTimeSkewSeconds < 0 {
+		return errors.New("acceptable_time_skew_seconds must be positive or 0")
+	}
+
+	issuers := map[string]bool{}
+
+	for i, issuer := range c.Issuers {
+		if issuer == nil || issuer.Issuer == "" {
+			return errors.Errorf("issuers[%d].issuer not set", i)
+		}
+
+		if issuers[issuer.Issuer] {
+			return errors.Errorf("issuers[%d].issuer %q is not unique", i, issuer.Issuer)
+		}
+
+		issuers[issuer.Issuer] = true
+
+		switch {
+		case issuer.JWKSURL != "" && issuer.HasStaticKeys():
+			return errors.Errorf("issuers[%d]: jwks_url and static keys are mutually exclusive", i)
+		case issuer.JWKSURL != "":
+			u, err := url.Parse(issuer.JWKSURL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return errors.Errorf("issuers[%d]: invalid jwks_url %q", i, issuer.JWKSURL)
+			}
+		case !issuer.HasStaticKeys():
+			return errors.Errorf("issuers[%d]: jwks_url, jwks_file or public_keys not set", i)
+		}
+	}
+
+	for i, rule := range c.Rules {
+		if _, err := newRule(rule); err != nil {
+			return errors.Wrapf(err, "rules[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// AcceptableTimeSkewDuration, returns the acceptable time skew.
+func (c *Config) AcceptableTimeSkewDuration() time.Duration {
+	return time.Duration(c.AcceptableTimeSkewSeconds) * time.Second
+}
+
+// CacheRefreshMinIntervalDuration, returns the minimum refresh interval of the fetched key sets.
+func (c *Config) CacheRefreshMinIntervalDuration() time.Duration {
+	d, err := time.ParseDuration(c.CacheRefreshMinInterval)
+	if err != nil {
+		return defaultCacheRefreshMinInterval
+	}
+
+	return d
+}
+
+// CacheRefreshMaxIntervalDuration, returns the maximum refresh interval of the fetched key sets.
+func (c *Config) CacheRefreshMaxIntervalDuration() time.Duration {
+	d, err := time.ParseDuration(c.CacheRefreshMaxInterval)
+	if err != nil {
+		return defaultCacheRefreshMaxInterval
+	}
+
+	return d
+}
+
+type rule struct {
+	claim    string
+	contains string
+	allow    []string
+}
+
+func newRule(r *Rule) (*rule, error) {
+	if r == nil || r.Claim == "" {
+		return nil, errors.New("claim not set")
+	}
+
+	if r.Contains == "" {
+		return nil, errors.Errorf("claim %q: contains not set", r.Claim)
+	}
+
+	if _, err := keys.NewScope(r.Allow); err != nil {
+		return nil, errors.Wrapf(err, "claim %q", r.Claim)
+	}
+
+	allow := r.Allow
+	if len(allow) == 0 {
+		allow = []string{"*"}
+	}
+
+	return &rule{claim: r.Claim, contains: r.Contains, allow: allow}, nil
+}
+
+// matches, returns true when the claim of the token contains the value of the rule.
+func (r *rule) matches(claims map[string]any) bool {
+	return containsValue(claimValue(claims, r.claim), r.contains)
+}
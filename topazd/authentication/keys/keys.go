@@ -0,0 +1,285 @@
+// Package keys, named API keys, with optional hashed values, expiry and a list of the services
+// or gRPC method globs the key is allowed to call.
+package keys
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HashPrefix, prefix of a hashed key value, followed by the hex encoded SHA-256 hash of the key.
+const HashPrefix = "sha256:"
+
+var (
+	ErrInvalidKey = errors.New("invalid api key")
+	ErrExpiredKey = errors.New("expired api key")
+)
+
+// servicePatterns, the method globs of the services, which can be used in the allow list of a key.
+var servicePatterns = map[string][]string{ //nolint:gochecknoglobals
+	"authorizer": {
+		"/aserto.authorizer.v2.Authorizer/*",
+		"/aserto.topaz.outcome.v1.Outcome/*",
+		"/aserto.topaz.batch.v1.Batch/*",
+		"/aserto.topaz.filter.v1.Filter/*",
+		"/aserto.topaz.policy.v1.PolicyStatus/*",
+		"/v1/data",
+		"/authzen.access.v1.Access/*",
+	},
+	"reader": {
+		"/aserto.directory.reader.v3.Reader/*",
+		"/aserto.topaz.report.v1.Report/*",
+		"/authzen.access.v1.Access/*",
+	},
+	"access":    {"/authzen.access.v1.Access/*"},
+	"writer":    {"/aserto.directory.writer.v3.Writer/*"},
+	"model":     {"/aserto.directory.model.v3.Model/*"},
+	"importer":  {"/aserto.directory.importer.v3.Importer/*"},
+	"exporter":  {"/aserto.directory.exporter.v3.Exporter/*"},
+	"ext_authz": {"/envoy.service.auth.v3.Authorization/*"},
+	"console":   {"/api/v2/config"},
+}
+
+// Key, a named API key.
+type Key struct {
+	// Name, identifies the key in the logs and decision logs.
+	Name string `json:"name"`
+	// Key, the plain text value of the key, either key or hash must be set.
+	Key string `json:"key,omitempty"`
+	// Hash, the hashed value of the key, sha256:<hex encoded SHA-256 hash of the key>.
+	Hash string `json:"hash,omitempty"`
+	// Allow, the services (reader, writer, authorizer, ...) or gRPC method globs
+	// (/aserto.directory.reader.v3.Reader/Get*) the key can call, all when empty.
+	Allow []string `json:"allow,omitempty"`
+	// Expires, RFC 3339 time after which the key is rejected, never when empty.
+	Expires string `json:"expires,omitempty"`
+}
+
+// Services, returns the service names which can be used in the allow list of a key.
+func Services() []string {
+	names := make([]string, 0, len(servicePatterns))
+	for name := range servicePatterns {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Scope, the gRPC methods and HTTP paths a caller is allowed to call.
+type Scope struct {
+	patterns []string
+}
+
+// NewScope, returns the scope of the allow list of services or method globs, all methods when empty.
+func NewScope(allow []string) (Scope, error) {
+	patterns, err := allowPatterns(allow)
+	if err != nil {
+		return Scope{}, err
+	}
+
+	return Scope{patterns: patterns}, nil
+}
+
+// Allows, returns true when the scope allows calling the gRPC method or HTTP path.
+func (s Scope) Allows(method string) bool {
+	if s.patterns == nil {
+		return true
+	}
+
+	for _, pattern := range s.patterns {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Identity, an authenticated key.
+type Identity struct {
+	Scope
+
+	name    string
+	hash    []byte
+	expires time.Time
+}
+
+// Name, returns the name of the key.
+func (i *Identity) Name() string {
+	return i.name
+}
+
+// Keyring, the configured API keys.
+type Keyring struct {
+	keys []*Identity
+}
+
+// New, returns the keyring of the scoped keys, and of the legacy keys, which are allowed to
+// call all services, and are named after their account, when set.
+func New(scoped []*Key, legacy map[string]string) (*Keyring, error) {
+	ring := &Keyring{keys: make([]*Identity, 0, len(scoped)+len(legacy))}
+	names := map[string]bool{}
+
+	for i, key := range scoped {
+		id, err := newIdentity(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scoped_keys[%d]", i)
+		}
+
+		if names[id.name] {
+			return nil, errors.Errorf("scoped_keys[%d]: name %q is not unique", i, id.name)
+		}
+
+		names[id.name] = true
+		ring.keys = append(ring.keys, id)
+	}
+
+	legacyKeys := make([]string, 0, len(legacy))
+	for key := range legacy {
+		legacyKeys = append(legacyKeys, key)
+	}
+
+	sort.Strings(legacyKeys)
+
+	for _, key := range legacyKeys {
+		ring.keys = append(ring.keys, &Identity{name: legacy[key], hash: hash(key)})
+	}
+
+	return ring, nil
+}
+
+// Len, returns the number of keys.
+func (r *Keyring) Len() int {
+	return len(r.keys)
+}
+
+// Authenticate, returns the identity of the key, ErrInvalidKey when the key is unknown,
+// and ErrExpiredKey when the key has expired at the given time.
+func (r *Keyring) Authenticate(key string, now time.Time) (*Identity, error) {
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	h := hash(key)
+
+	for _, id := range r.keys {
+		if subtle.ConstantTimeCompare(h, id.hash) != 1 {
+			continue
+		}
+
+		if !id.expires.IsZero() && now.After(id.expires) {
+			return id, ErrExpiredKey
+		}
+
+		return id, nil
+	}
+
+	return nil, ErrInvalidKey
+}
+
+func newIdentity(key *Key) (*Identity, error) {
+	if key == nil || key.Name == "" {
+		return nil, errors.New("name not set")
+	}
+
+	id := &Identity{name: key.Name}
+
+	switch {
+	case key.Key != "" && key.Hash != "":
+		return nil, errors.Errorf("key %q: key and hash are mutually exclusive", key.Name)
+	case key.Key != "":
+		id.hash = hash(key.Key)
+	case key.Hash != "":
+		h, err := parseHash(key.Hash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %q", key.Name)
+		}
+
+		id.hash = h
+	default:
+		return nil, errors.Errorf("key %q: key or hash not set", key.Name)
+	}
+
+	scope, err := NewScope(key.Allow)
+	if err != nil {
+		return nil, errors.Wrapf(err, "key %q", key.Name)
+	}
+
+	id.Scope = scope
+
+	if key.Expires != "" {
+		expires, err := time.Parse(time.RFC3339, key.Expires)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %q: invalid expires", key.Name)
+		}
+
+		id.expires = expires
+	}
+
+	return id, nil
+}
+
+// allowPatterns, returns the method globs of the allow list, nil when all methods are allowed.
+func allowPatterns(allow []string) ([]string, error) {
+	if len(allow) == 0 {
+		return nil, nil
+	}
+
+	patterns := []string{}
+
+	for _, entry := range allow {
+		if entry == "*" {
+			return nil, nil
+		}
+
+		if p, ok := servicePatterns[entry]; ok {
+			patterns = append(patterns, p...)
+			continue
+		}
+
+		if !strings.HasPrefix(entry, "/") {
+			return nil, errors.Errorf("unknown service %q, expected one of %v or a method glob", entry, Services())
+		}
+
+		if _, err := path.Match(entry, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid method glob %q", entry)
+		}
+
+		patterns = append(patterns, entry)
+	}
+
+	return patterns, nil
+}
+
+func parseHash(value string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(value, HashPrefix)
+	if !ok {
+		return nil, errors.Errorf("hash must start with %q", HashPrefix)
+	}
+
+	h, err := hex.DecodeString(encoded)
+	if err != nil || len(h) != sha256.Size {
+		return nil, errors.New("hash must be a hex encoded SHA-256 hash")
+	}
+
+	return h, nil
+}
+
+func hash(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+// Hash, returns the hashed value of the key, which can be used as the hash of a scoped key.
+func Hash(key string) string {
+	return HashPrefix + hex.EncodeToString(hash(key))
+}
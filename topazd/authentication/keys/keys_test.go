@@ -0,0 +1,104 @@
+package keys_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ring, err := keys.New([]*keys.Key{
+		{Name: "reader", Key: "r-secret", Allow: []string{"reader", "authorizer"}},
+		{Name: "admin", Hash: keys.Hash("a-secret")},
+		{Name: "temp", Key: "t-secret", Expires: "2026-01-01T00:00:00Z"},
+	}, map[string]string{"legacy-secret": "legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, ring.Len())
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	id, err := ring.Authenticate("r-secret", now)
+	require.NoError(t, err)
+	assert.Equal(t, "reader", id.Name())
+
+	id, err = ring.Authenticate("a-secret", now)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", id.Name())
+
+	id, err = ring.Authenticate("legacy-secret", now)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", id.Name())
+
+	_, err = ring.Authenticate("t-secret", now)
+	require.ErrorIs(t, err, keys.ErrExpiredKey)
+
+	_, err = ring.Authenticate("t-secret", now.AddDate(-1, 0, 0))
+	require.NoError(t, err)
+
+	_, err = ring.Authenticate("unknown", now)
+	require.ErrorIs(t, err, keys.ErrInvalidKey)
+
+	_, err = ring.Authenticate("", now)
+	require.ErrorIs(t, err, keys.ErrInvalidKey)
+}
+
+func TestAllows(t *testing.T) {
+	ring, err := keys.New([]*keys.Key{
+		{Name: "reader", Key: "r-secret", Allow: []string{"reader", "/aserto.authorizer.v2.Authorizer/Is"}},
+		{Name: "getter", Key: "g-secret", Allow: []string{"/aserto.directory.reader.v3.Reader/Get*"}},
+		{Name: "admin", Key: "a-secret", Allow: []string{"*"}},
+	}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		key     string
+		method  string
+		allowed bool
+	}{
+		{"r-secret", "/aserto.directory.reader.v3.Reader/GetObject", true},
+		{"r-secret", "/aserto.directory.reader.v3.Reader/Check", true},
+		{"r-secret", "/authzen.access.v1.Access/Evaluation", true},
+		{"r-secret", "/aserto.authorizer.v2.Authorizer/Is", true},
+		{"r-secret", "/aserto.authorizer.v2.Authorizer/Query", false},
+		{"r-secret", "/aserto.directory.writer.v3.Writer/SetObject", false},
+		{"r-secret", "/aserto.directory.model.v3.Model/DeleteManifest", false},
+		{"g-secret", "/aserto.directory.reader.v3.Reader/GetObjects", true},
+		{"g-secret", "/aserto.directory.reader.v3.Reader/Checks", false},
+		{"a-secret", "/aserto.directory.model.v3.Model/DeleteManifest", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+tt.method, func(t *testing.T) {
+			id, err := ring.Authenticate(tt.key, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, id.Allows(tt.method))
+		})
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := map[string]*keys.Key{
+		"no name":       {Key: "secret"},
+		"no key":        {Name: "k"},
+		"key and hash":  {Name: "k", Key: "secret", Hash: keys.Hash("secret")},
+		"bad hash":      {Name: "k", Hash: "sha256:abc"},
+		"hash prefix":   {Name: "k", Hash: "md5:abc"},
+		"bad service":   {Name: "k", Key: "secret", Allow: []string{"reeder"}},
+		"bad glob":      {Name: "k", Key: "secret", Allow: []string{"/aserto.[/Get"}},
+		"bad expires":   {Name: "k", Key: "secret", Expires: "tomorrow"},
+		"nil key entry": nil,
+	}
+
+	for name, key := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := keys.New([]*keys.Key{key}, nil)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := keys.New([]*keys.Key{{Name: "k", Key: "a"}, {Name: "k", Key: "b"}}, nil)
+	require.Error(t, err)
+}
@@ -5,31 +5,133 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/header"
+	"github.com/aserto-dev/topaz/internal/reload"
 	"github.com/aserto-dev/topaz/pkg/config"
+	"github.com/aserto-dev/topaz/topazd/authentication/bearer"
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/aserto-dev/topaz/topazd/authentication/mtls"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/samber/lo"
 	"google.golang.org/grpc"
 )
 
 type APIKeyAuthMiddleware struct {
-	apiAuth map[string]string
-	cfg     *config.AuthnConfig
-	logger  *zerolog.Logger
+	ctx    context.Context
+	state  atomic.Pointer[authState]
+	certs  *mtls.Authenticator
+	logger *zerolog.Logger
+}
+
+// authState, the API keys, the bearer token authenticator and the call options of the middleware,
+// replaced as a whole by Reload.
+type authState struct {
+	keys   *keys.Keyring
+	tokens *bearer.Authenticator
+	cfg    *config.AuthnConfig
+	cancel context.CancelFunc
 }
 
 func NewAPIKeyAuthMiddleware(
 	ctx context.Context,
-	cfg *config.AuthnConfig,
+	cfg *config.Config,
 	logger *zerolog.Logger,
 ) (*APIKeyAuthMiddleware, error) {
-	return &APIKeyAuthMiddleware{
-		apiAuth: cfg.APIKeys,
-		cfg:     cfg,
-		logger:  logger,
-	}, nil
+	certs, err := newClientCertAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &APIKeyAuthMiddleware{
+		ctx:    ctx,
+		certs:  certs,
+		logger: logger,
+	}
+
+	if err := a.Reload(&cfg.Auth); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Reload, replaces the API keys, the bearer token authentication and the call options of the
+// middleware, the calls in progress complete with the previous configuration. The client
+// certificate authentication is part of the TLS configuration of the servers, and is not reloaded.
+func (a *APIKeyAuthMiddleware) Reload(cfg *config.AuthnConfig) error {
+	change, err := a.PrepareReload(cfg)
+	if err != nil {
+		return err
+	}
+
+	reload.Commit(change)
+
+	return nil
+}
+
+// PrepareReload, builds the API keys and the bearer token authentication of the configuration, the
+// change replaces the state of the middleware when committed, the key set cache of the bearer token
+// authenticator which is replaced, or discarded, is stopped.
+func (a *APIKeyAuthMiddleware) PrepareReload(cfg *config.AuthnConfig) (*reload.Change, error) {
+	keyring, err := keys.New(cfg.ScopedKeys, cfg.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+
+	var tokens *bearer.Authenticator
+	if cfg.Bearer.Enabled {
+		if tokens, err = bearer.New(ctx, &cfg.Bearer); err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "failed to create bearer token authenticator")
+		}
+	}
+
+	state := &authState{
+		keys:   keyring,
+		tokens: tokens,
+		cfg:    cfg,
+		cancel: cancel,
+	}
+
+	commit := func() {
+		if prev := a.state.Swap(state); prev != nil {
+			prev.cancel()
+		}
+	}
+
+	return reload.NewChange(commit, cancel), nil
+}
+
+// newClientCertAuthenticator, returns the client certificate authenticator, when mTLS is enabled,
+// the gRPC certificates of the services are trusted to forward the client certificates of the gateways.
+func newClientCertAuthenticator(cfg *config.Config) (*mtls.Authenticator, error) {
+	if !cfg.Auth.MTLS.Enabled {
+		return nil, nil //nolint:nilnil // mTLS disabled.
+	}
+
+	cas := []string{cfg.Auth.MTLS.ClientCACertPath}
+	certs := []string{}
+
+	for _, service := range cfg.APIConfig.Services {
+		cas = append(cas, service.GRPC.Certs.CA)
+		certs = append(certs, service.GRPC.Certs.Cert)
+	}
+
+	roots, err := mtls.CertPool(cas...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load client CA certificates")
+	}
+
+	return mtls.New(&cfg.Auth.MTLS, roots, lo.Uniq(certs)...)
 }
 
 func (a *APIKeyAuthMiddleware) Unary() grpc.UnaryServerInterceptor {
@@ -61,11 +163,29 @@ func (a *APIKeyAuthMiddleware) Stream() grpc.StreamServerInterceptor {
 
 func (a *APIKeyAuthMiddleware) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		newCtx, err := a.authenticate(
-			r.Context(),
-			r.URL.Path,
-			httpAuthHeader(r),
-		)
+		newCtx, err := a.httpAuthenticate(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%q", err.Error()), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(newCtx))
+	})
+}
+
+// PathHandler, authenticates the HTTP requests as calls of the path, used by the handlers serving
+// a tree of documents below the path, which are allowed, and configured, as a single API.
+func (a *APIKeyAuthMiddleware) PathHandler(path string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, err := a.clientCert(func() (*mtls.Caller, error) {
+			return a.certs.AuthenticateConn(r.TLS, "")
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%q", err.Error()), http.StatusUnauthorized)
+			return
+		}
+
+		newCtx, err := a.authenticate(r.Context(), path, httpAuthHeader(r), caller)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("%q", err.Error()), http.StatusUnauthorized)
 			return
@@ -75,37 +195,146 @@ func (a *APIKeyAuthMiddleware) Handler(next http.Handler) http.Handler {
 	})
 }
 
+// authenticate, authenticates the call of the method or path, by the verified client certificate
+// of the caller, when mTLS is enabled and the caller presented one, otherwise by the bearer token,
+// when bearer authentication is enabled and the caller presented one, or by the API key.
 func (a *APIKeyAuthMiddleware) authenticate(
 	ctx context.Context,
 	path, authHeader string,
+	caller *mtls.Caller,
 ) (context.Context, error) {
-	options := a.cfg.Options.ForPath(path)
+	state := a.state.Load()
+	options := state.cfg.Options.ForPath(path)
 
 	if options.EnableAnonymous {
 		return ctx, nil
 	}
 
-	// if no API keys are defined or EnableAPIKey is not set, allow the request
-	if (len(a.cfg.APIKeys) == 0) || !options.EnableAPIKey {
+	// if no API keys are defined and mTLS and bearer tokens are disabled, or EnableAPIKey is not set, allow the request
+	if !a.enabled(state) || !options.EnableAPIKey {
 		return ctx, nil
 	}
 
+	if caller != nil {
+		if !caller.Allows(path) {
+			a.logger.Debug().Str("client_identity", caller.Name()).Str("method", path).Msg("client certificate not allowed")
+			return ctx, aerr.ErrAuthorizationFailed.Msgf("client %q is not allowed to call %s", caller.Name(), path)
+		}
+
+		return header.ContextWithClientIdentity(ctx, caller.Name()), nil
+	}
+
+	if token, ok := bearerToken(state, authHeader); ok {
+		caller, err := a.authorizeToken(ctx, state, token, path)
+		if err != nil {
+			return ctx, err
+		}
+
+		return header.ContextWithTokenSubject(ctx, caller.Name()), nil
+	}
+
 	basicAPIKey, err := parseAuthHeader(authHeader, "basic")
 	if err != nil {
-		a.logger.Trace().Err(err).Str("auth_header", authHeader).Msg("failed to parse basic auth header")
+		a.logger.Trace().Err(err).Msg("failed to parse basic auth header")
 	}
 
-	// allow the request if the API key is present in the config
-	if _, ok := a.cfg.APIKeys[basicAPIKey]; ok {
-		return ctx, nil
+	id, err := a.authorize(state, basicAPIKey, path)
+	if err != nil {
+		return ctx, err
+	}
+
+	return header.ContextWithAPIKeyName(ctx, id.Name()), nil
+}
+
+// authorize, returns the identity of the API key, when the key is valid, and is allowed to call the method or path.
+func (a *APIKeyAuthMiddleware) authorize(state *authState, apiKey, path string) (*keys.Identity, error) {
+	id, err := state.keys.Authenticate(apiKey, time.Now())
+	if err != nil {
+		if id != nil {
+			a.logger.Debug().Err(err).Str("api_key", id.Name()).Msg("api key rejected")
+		}
+
+		return nil, aerr.ErrAuthenticationFailed
+	}
+
+	if !id.Allows(path) {
+		a.logger.Debug().Str("api_key", id.Name()).Str("method", path).Msg("api key not allowed")
+		return nil, aerr.ErrAuthorizationFailed.Msgf("api key %q is not allowed to call %s", id.Name(), path)
+	}
+
+	return id, nil
+}
+
+// authorizeToken, returns the caller of the bearer token, when the token is valid, and is allowed to call the method or path.
+func (a *APIKeyAuthMiddleware) authorizeToken(ctx context.Context, state *authState, token, path string) (*bearer.Caller, error) {
+	caller, err := state.tokens.Authenticate(ctx, token)
+	if err != nil {
+		a.logger.Debug().Err(err).Msg("bearer token rejected")
+		return nil, aerr.ErrAuthenticationFailed
+	}
+
+	if !caller.Allows(path) {
+		a.logger.Debug().Str("token_subject", caller.Name()).Str("method", path).Msg("bearer token not allowed")
+		return nil, aerr.ErrAuthorizationFailed.Msgf("token subject %q is not allowed to call %s", caller.Name(), path)
 	}
 
-	return ctx, aerr.ErrAuthenticationFailed
+	return caller, nil
+}
+
+// bearerToken, returns the token of a bearer authorization header, when bearer authentication is enabled.
+func bearerToken(state *authState, authHeader string) (string, bool) {
+	if state.tokens == nil {
+		return "", false
+	}
+
+	token, err := parseAuthHeader(authHeader, "bearer")
+	if err != nil {
+		return "", false
+	}
+
+	return token, true
+}
+
+// enabled, returns true when API keys, client certificates or bearer tokens authenticate the calls.
+func (a *APIKeyAuthMiddleware) enabled(state *authState) bool {
+	return state.keys.Len() > 0 || a.certs != nil || state.tokens != nil
 }
 
 func (a *APIKeyAuthMiddleware) grpcAuthenticate(ctx context.Context) (context.Context, error) {
 	method, _ := grpc.Method(ctx)
-	return a.authenticate(ctx, method, grpcAuthHeader(ctx))
+
+	caller, err := a.clientCert(func() (*mtls.Caller, error) { return a.certs.Authenticate(ctx) })
+	if err != nil {
+		return ctx, err
+	}
+
+	return a.authenticate(ctx, method, grpcAuthHeader(ctx), caller)
+}
+
+func (a *APIKeyAuthMiddleware) httpAuthenticate(r *http.Request) (context.Context, error) {
+	caller, err := a.clientCert(func() (*mtls.Caller, error) {
+		return a.certs.AuthenticateConn(r.TLS, "")
+	})
+	if err != nil {
+		return r.Context(), err
+	}
+
+	return a.authenticate(r.Context(), r.URL.Path, httpAuthHeader(r), caller)
+}
+
+// clientCert, returns the caller of the client certificate, when mTLS is enabled.
+func (a *APIKeyAuthMiddleware) clientCert(authenticate func() (*mtls.Caller, error)) (*mtls.Caller, error) {
+	if a.certs == nil {
+		return nil, nil //nolint:nilnil // mTLS disabled.
+	}
+
+	caller, err := authenticate()
+	if err != nil {
+		a.logger.Debug().Err(err).Msg("client certificate rejected")
+		return nil, aerr.ErrAuthenticationFailed
+	}
+
+	return caller, nil
 }
 
 func grpcAuthHeader(ctx context.Context) string {
@@ -0,0 +1,319 @@
+// Package mtls, client certificate authentication, the verified client certificate is mapped to
+// a caller identity, by its subject common name or subject alternative name, and the identity is
+// scoped to a list of services or gRPC method globs, like the scoped API keys.
+package mtls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/keys"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MetadataClientCert, metadata key of the base64 encoded (DER) client certificate, forwarded by the
+// gateway to the gRPC endpoint, it is only accepted from connections presenting a server certificate.
+const MetadataClientCert = "x-topaz-client-cert"
+
+// Sources of the caller identity of a client certificate.
+const (
+	IdentitySubjectCN = "subject_cn"
+	IdentityURISAN    = "uri_san"
+	IdentityDNSSAN    = "dns_san"
+	IdentityEmailSAN  = "email_san"
+)
+
+var (
+	ErrNoIdentity      = errors.New("client certificate without identity")
+	ErrUnknownIdentity = errors.New("client certificate identity not allowed")
+	ErrInvalidCert     = errors.New("invalid forwarded client certificate")
+)
+
+// Config, client certificate authentication configuration.
+type Config struct {
+	// Enabled, verify client certificates, and authenticate callers presenting one.
+	Enabled bool `json:"enabled"`
+	// ClientCACertPath, the CA bundle verifying the client certificates, in addition to the CA of the service certificates.
+	ClientCACertPath string `json:"client_ca_cert_path"`
+	// IdentityFrom, the source of the caller identity, subject_cn, uri_san, dns_san or email_san,
+	// defaults to the first URI, DNS or email SAN, or the subject common name.
+	IdentityFrom string `json:"identity_from"`
+	// Identities, the allowed caller identities and their scopes, any verified identity is
+	// allowed to call all services when empty.
+	Identities []*Identity `json:"identities"`
+}
+
+// Identity, a caller identity, or a glob of caller identities, and the services or gRPC
+// method globs it is allowed to call.
+type Identity struct {
+	Name  string   `json:"name"`
+	Allow []string `json:"allow,omitempty"`
+}
+
+// Caller, an authenticated client certificate identity.
+type Caller struct {
+	keys.Scope
+
+	name string
+}
+
+// Name, returns the caller identity.
+func (c *Caller) Name() string {
+	return c.name
+}
+
+type scopedIdentity struct {
+	pattern string
+	scope   keys.Scope
+}
+
+// Authenticator, maps verified client certificates to caller identities.
+type Authenticator struct {
+	identityFrom string
+	identities   []scopedIdentity
+	roots        *x509.CertPool
+	trusted      map[[sha256.Size]byte]bool
+}
+
+// New, returns the authenticator of the configuration, roots verify forwarded client certificates,
+// trusted are the paths of the server certificates, used by the gateway to forward them.
+func New(cfg *Config, roots *x509.CertPool, trusted ...string) (*Authenticator, error) {
+	switch cfg.IdentityFrom {
+	case "", IdentitySubjectCN, IdentityURISAN, IdentityDNSSAN, IdentityEmailSAN:
+	default:
+		return nil, errors.Errorf("unknown identity_from %q", cfg.IdentityFrom)
+	}
+
+	a := &Authenticator{
+		identityFrom: cfg.IdentityFrom,
+		roots:        roots,
+		trusted:      map[[sha256.Size]byte]bool{},
+	}
+
+	for i, id := range cfg.Identities {
+		if id == nil || id.Name == "" {
+			return nil, errors.Errorf("identities[%d].name not set", i)
+		}
+
+		if _, err := path.Match(id.Name, ""); err != nil {
+			return nil, errors.Wrapf(err, "identities[%d]: invalid name glob %q", i, id.Name)
+		}
+
+		scope, err := keys.NewScope(id.Allow)
+		if err != nil {
+			return nil, errors.Wrapf(err, "identities[%d]", i)
+		}
+
+		a.identities = append(a.identities, scopedIdentity{pattern: id.Name, scope: scope})
+	}
+
+	for _, certPath := range trusted {
+		if certPath == "" {
+			continue
+		}
+
+		certs, err := readCerts(certPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load server certificate")
+		}
+
+		for _, cert := range certs {
+			a.trusted[sha256.Sum256(cert.Raw)] = true
+		}
+	}
+
+	return a, nil
+}
+
+// Authenticate, returns the caller of the verified client certificate of the gRPC peer, or of the
+// client certificate forwarded by the gateway, nil when the caller did not present a certificate.
+func (a *Authenticator) Authenticate(ctx context.Context) (*Caller, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, nil //nolint:nilnil // no certificate.
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, nil //nolint:nilnil // no certificate.
+	}
+
+	var forwarded string
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataClientCert); len(values) > 0 {
+		forwarded = values[0]
+	}
+
+	return a.AuthenticateConn(&info.State, forwarded)
+}
+
+// AuthenticateConn, returns the caller of the verified client certificate of the connection, when
+// the connection presents a trusted server certificate, the forwarded client certificate is used.
+func (a *Authenticator) AuthenticateConn(state *tls.ConnectionState, forwarded string) (*Caller, error) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, nil //nolint:nilnil // no certificate.
+	}
+
+	leaf := state.VerifiedChains[0][0]
+
+	if a.trusted[sha256.Sum256(leaf.Raw)] {
+		if forwarded == "" {
+			return nil, nil //nolint:nilnil // gateway call without client certificate.
+		}
+
+		cert, err := a.verifyForwarded(forwarded)
+		if err != nil {
+			return nil, err
+		}
+
+		leaf = cert
+	}
+
+	return a.caller(leaf)
+}
+
+// Forward, returns the metadata value of the verified client certificate of the connection, to
+// be forwarded by the gateway, empty when the caller did not present a certificate.
+func Forward(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(state.VerifiedChains[0][0].Raw)
+}
+
+func (a *Authenticator) verifyForwarded(forwarded string) (*x509.Certificate, error) {
+	der, err := base64.StdEncoding.DecodeString(forwarded)
+	if err != nil {
+		return nil, ErrInvalidCert
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, ErrInvalidCert
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     a.roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, errors.Wrap(ErrInvalidCert, err.Error())
+	}
+
+	return cert, nil
+}
+
+func (a *Authenticator) caller(cert *x509.Certificate) (*Caller, error) {
+	name := a.identity(cert)
+	if name == "" {
+		return nil, ErrNoIdentity
+	}
+
+	if len(a.identities) == 0 {
+		return &Caller{name: name}, nil
+	}
+
+	for _, id := range a.identities {
+		if ok, _ := path.Match(id.pattern, name); ok {
+			return &Caller{Scope: id.scope, name: name}, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrUnknownIdentity, name)
+}
+
+// identity, returns the caller identity of the certificate.
+func (a *Authenticator) identity(cert *x509.Certificate) string {
+	uri := ""
+	if len(cert.URIs) > 0 {
+		uri = cert.URIs[0].String()
+	}
+
+	dns := first(cert.DNSNames)
+	email := first(cert.EmailAddresses)
+
+	switch a.identityFrom {
+	case IdentitySubjectCN:
+		return cert.Subject.CommonName
+	case IdentityURISAN:
+		return uri
+	case IdentityDNSSAN:
+		return dns
+	case IdentityEmailSAN:
+		return email
+	}
+
+	for _, name := range []string{uri, dns, email, cert.Subject.CommonName} {
+		if name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// CertPool, returns the pool of the certificates of the PEM files, empty paths are skipped.
+func CertPool(paths ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+
+	for _, certPath := range paths {
+		if certPath == "" {
+			continue
+		}
+
+		certs, err := readCerts(certPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+	}
+
+	return pool, nil
+}
+
+func readCerts(certPath string) ([]*x509.Certificate, error) {
+	buf, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", certPath)
+	}
+
+	certs := []*x509.Certificate{}
+
+	for block, rest := pem.Decode(buf); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %q", certPath)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.Errorf("no certificates in %q", certPath)
+	}
+
+	return certs, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,215 @@
+package mtls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/topaz/topazd/authentication/mtls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, tmpl *x509.Certificate) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+
+	if tmpl.ExtKeyUsage == nil {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	return pool
+}
+
+func conn(cert *x509.Certificate) *tls.ConnectionState {
+	return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+}
+
+func writePEM(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+
+	certPath := filepath.Join(t.TempDir(), "cert.crt")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600))
+
+	return certPath
+}
+
+func TestIdentity(t *testing.T) {
+	ca := newCA(t)
+	uri, _ := url.Parse("spiffe://example.org/ns/prod/sa/billing")
+
+	cert := ca.issue(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "billing"},
+		DNSNames:       []string{"billing.example.org"},
+		URIs:           []*url.URL{uri},
+		EmailAddresses: []string{"billing@example.org"},
+	})
+
+	tests := map[string]string{
+		"":                     "spiffe://example.org/ns/prod/sa/billing",
+		mtls.IdentitySubjectCN: "billing",
+		mtls.IdentityURISAN:    "spiffe://example.org/ns/prod/sa/billing",
+		mtls.IdentityDNSSAN:    "billing.example.org",
+		mtls.IdentityEmailSAN:  "billing@example.org",
+	}
+
+	for from, expected := range tests {
+		t.Run(from, func(t *testing.T) {
+			a, err := mtls.New(&mtls.Config{Enabled: true, IdentityFrom: from}, ca.pool())
+			require.NoError(t, err)
+
+			caller, err := a.AuthenticateConn(conn(cert), "")
+			require.NoError(t, err)
+			assert.Equal(t, expected, caller.Name())
+		})
+	}
+
+	cnOnly := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "cli"}})
+
+	a, err := mtls.New(&mtls.Config{Enabled: true, IdentityFrom: mtls.IdentityDNSSAN}, ca.pool())
+	require.NoError(t, err)
+
+	_, err = a.AuthenticateConn(conn(cnOnly), "")
+	require.ErrorIs(t, err, mtls.ErrNoIdentity)
+
+	caller, err := a.AuthenticateConn(nil, "")
+	require.NoError(t, err)
+	assert.Nil(t, caller)
+}
+
+func TestScope(t *testing.T) {
+	ca := newCA(t)
+
+	a, err := mtls.New(&mtls.Config{
+		Enabled:      true,
+		IdentityFrom: mtls.IdentitySubjectCN,
+		Identities: []*mtls.Identity{
+			{Name: "reader-*", Allow: []string{"reader"}},
+			{Name: "admin"},
+		},
+	}, ca.pool())
+	require.NoError(t, err)
+
+	reader, err := a.AuthenticateConn(conn(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reader-1"}})), "")
+	require.NoError(t, err)
+	assert.Equal(t, "reader-1", reader.Name())
+	assert.True(t, reader.Allows("/aserto.directory.reader.v3.Reader/GetObject"))
+	assert.False(t, reader.Allows("/aserto.directory.writer.v3.Writer/SetObject"))
+
+	admin, err := a.AuthenticateConn(conn(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "admin"}})), "")
+	require.NoError(t, err)
+	assert.True(t, admin.Allows("/aserto.directory.writer.v3.Writer/SetObject"))
+
+	_, err = a.AuthenticateConn(conn(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "writer"}})), "")
+	require.ErrorIs(t, err, mtls.ErrUnknownIdentity)
+}
+
+func TestForwarded(t *testing.T) {
+	ca := newCA(t)
+	other := newCA(t)
+
+	server := ca.issue(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "topaz-grpc"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	})
+	client := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}})
+	untrusted := other.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}})
+
+	a, err := mtls.New(&mtls.Config{Enabled: true}, ca.pool(), writePEM(t, server))
+	require.NoError(t, err)
+
+	// the gateway presents the server certificate, and forwards the client certificate.
+	caller, err := a.AuthenticateConn(conn(server), mtls.Forward(conn(client)))
+	require.NoError(t, err)
+	assert.Equal(t, "billing", caller.Name())
+
+	// gateway call without client certificate.
+	caller, err = a.AuthenticateConn(conn(server), "")
+	require.NoError(t, err)
+	assert.Nil(t, caller)
+
+	_, err = a.AuthenticateConn(conn(server), mtls.Forward(conn(untrusted)))
+	require.ErrorIs(t, err, mtls.ErrInvalidCert)
+
+	_, err = a.AuthenticateConn(conn(server), "not-a-cert")
+	require.ErrorIs(t, err, mtls.ErrInvalidCert)
+
+	// the forwarded certificate is ignored on connections of other callers.
+	caller, err = a.AuthenticateConn(conn(client), mtls.Forward(conn(untrusted)))
+	require.NoError(t, err)
+	assert.Equal(t, "billing", caller.Name())
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := mtls.New(&mtls.Config{IdentityFrom: "subject"}, nil)
+	require.Error(t, err)
+
+	_, err = mtls.New(&mtls.Config{Identities: []*mtls.Identity{{Name: "svc", Allow: []string{"reeder"}}}}, nil)
+	require.Error(t, err)
+
+	_, err = mtls.New(&mtls.Config{Identities: []*mtls.Identity{{}}}, nil)
+	require.Error(t, err)
+
+	_, err = mtls.New(&mtls.Config{}, nil, filepath.Join(t.TempDir(), "missing.crt"))
+	require.Error(t, err)
+}
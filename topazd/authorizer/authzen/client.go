@@ -0,0 +1,40 @@
+package authzen
+
+import (
+	"context"
+
+	dsa "github.com/authzen/access.go/api/access/v1"
+	"google.golang.org/grpc"
+)
+
+// LocalClient, returns an access client which calls the access server in-process, used to reach the
+// directory access service when its endpoint serves the policy backed access service instead.
+func LocalClient(server dsa.AccessServer) dsa.AccessClient {
+	return &localClient{server: server}
+}
+
+type localClient struct {
+	server dsa.AccessServer
+}
+
+var _ dsa.AccessClient = (*localClient)(nil)
+
+func (c *localClient) Evaluation(ctx context.Context, in *dsa.EvaluationRequest, _ ...grpc.CallOption) (*dsa.EvaluationResponse, error) {
+	return c.server.Evaluation(ctx, in)
+}
+
+func (c *localClient) Evaluations(ctx context.Context, in *dsa.EvaluationsRequest, _ ...grpc.CallOption) (*dsa.EvaluationsResponse, error) {
+	return c.server.Evaluations(ctx, in)
+}
+
+func (c *localClient) SubjectSearch(ctx context.Context, in *dsa.SubjectSearchRequest, _ ...grpc.CallOption) (*dsa.SubjectSearchResponse, error) {
+	return c.server.SubjectSearch(ctx, in)
+}
+
+func (c *localClient) ResourceSearch(ctx context.Context, in *dsa.ResourceSearchRequest, _ ...grpc.CallOption) (*dsa.ResourceSearchResponse, error) {
+	return c.server.ResourceSearch(ctx, in)
+}
+
+func (c *localClient) ActionSearch(ctx context.Context, in *dsa.ActionSearchRequest, _ ...grpc.CallOption) (*dsa.ActionSearchResponse, error) {
+	return c.server.ActionSearch(ctx, in)
+}
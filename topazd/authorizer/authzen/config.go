@@ -0,0 +1,106 @@
+// Package authzen, implements the AuthZEN access evaluation API using the topaz policies, the
+// subject, action, resource and context of an evaluation request are the input of the policy
+// selected by the policy path template, the search APIs are served by the directory.
+package authzen
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPolicyRoot, the policy root when no policy root is configured.
+	DefaultPolicyRoot = "authzen"
+	// DefaultPolicyPath, the policy path template when no policy path template is configured.
+	DefaultPolicyPath = "{{ .Root }}.{{ .Resource.Type }}.{{ .Action.Name }}"
+	// DefaultDecision, the evaluated decision when no decision is configured.
+	DefaultDecision = "allowed"
+)
+
+// Config, policy backed AuthZEN configuration.
+type Config struct {
+	// Enabled, serve the AuthZEN evaluation endpoints using the policies, instead of the directory.
+	Enabled bool `json:"enabled"`
+	// PolicyRoot, the policy root, available to the policy path template as .Root.
+	PolicyRoot string `json:"policy_root"`
+	// PolicyPath, text/template of the policy path, executed with the PathParams of the evaluation.
+	PolicyPath string `json:"policy_path"`
+	// Decision, the evaluated decision, either a boolean or a structured outcome.
+	Decision string `json:"decision"`
+}
+
+// PathParams, the parameters of the policy path template, the values are sanitized, characters
+// which are not valid in a rego reference are replaced by '_'.
+type PathParams struct {
+	Root     string
+	Subject  Entity
+	Action   Entity
+	Resource Entity
+}
+
+// Entity, the type, id and name of a subject, action or resource.
+type Entity struct {
+	Type string
+	ID   string
+	Name string
+}
+
+func (c *Config) root() string {
+	if c.PolicyRoot == "" {
+		return DefaultPolicyRoot
+	}
+
+	return c.PolicyRoot
+}
+
+func (c *Config) decision() string {
+	if c.Decision == "" {
+		return DefaultDecision
+	}
+
+	return c.Decision
+}
+
+// pathTemplate, returns the parsed policy path template.
+func (c *Config) pathTemplate() (*template.Template, error) {
+	text := c.PolicyPath
+	if text == "" {
+		text = DefaultPolicyPath
+	}
+
+	tmpl, err := template.New("policy_path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid authzen policy path template %q", text)
+	}
+
+	return tmpl, nil
+}
+
+// policyPath, returns the policy path of the template executed with the path params.
+func policyPath(tmpl *template.Template, params *PathParams) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", errors.Wrap(err, "failed to execute policy path template")
+	}
+
+	path := strings.Trim(buf.String(), ".")
+	if path == "" || strings.Contains(path, "..") {
+		return "", errors.Errorf("invalid policy path %q", path)
+	}
+
+	return path, nil
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
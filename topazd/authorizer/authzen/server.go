@@ -0,0 +1,311 @@
+package authzen
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	dsa "github.com/authzen/access.go/api/access/v1"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// OptionEvaluationsSemantic, evaluations request option selecting the evaluation semantic.
+	OptionEvaluationsSemantic = "evaluations_semantic"
+	// ExecuteAll, evaluates all evaluations of the request, the default semantic.
+	ExecuteAll = "execute_all"
+	// DenyOnFirstDeny, stops evaluating at the first evaluation which is denied.
+	DenyOnFirstDeny = "deny_on_first_deny"
+	// PermitOnFirstPermit, stops evaluating at the first evaluation which is permitted.
+	PermitOnFirstPermit = "permit_on_first_permit"
+	// ContextError, response context field containing the error of a failed evaluation.
+	ContextError = "error"
+)
+
+// Input fields of the policy input of an evaluation.
+const (
+	InputSubject  = "subject"
+	InputAction   = "action"
+	InputResource = "resource"
+	InputContext  = "context"
+)
+
+// Authorizer, evaluates the decisions of the policy context for the policy input.
+type Authorizer interface {
+	IsInput(ctx context.Context, pc *api.PolicyContext, ic *api.IdentityContext, input map[string]any) (
+		[]*authorizer.Decision, outcome.Outcomes, error)
+	// DirectoryAccess, returns the access client of the directory, which serves the search APIs.
+	DirectoryAccess() dsa.AccessClient
+}
+
+// Server, policy backed AuthZEN access server, the evaluations are served by the policies
+// and the searches are forwarded to the directory.
+type Server struct {
+	dsa.UnimplementedAccessServer
+
+	authz    Authorizer
+	dir      dsa.AccessServer
+	root     string
+	decision string
+	path     *template.Template
+	logger   *zerolog.Logger
+}
+
+var _ dsa.AccessServer = (*Server)(nil)
+
+func NewServer(cfg *Config, authz Authorizer, logger *zerolog.Logger) (*Server, error) {
+	tmpl, err := cfg.pathTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	log := logger.With().Str("component", "authzen").Logger()
+
+	return &Server{
+		authz:    authz,
+		root:     cfg.root(),
+		decision: cfg.decision(),
+		path:     tmpl,
+		logger:   &log,
+	}, nil
+}
+
+// SetDirectory, serves the searches using the in-process directory access service, instead of
+// the access client of the authorizer, used when the directory shares the endpoint of the
+// authorizer, where the access service is served by this server.
+func (s *Server) SetDirectory(dir dsa.AccessServer) {
+	s.dir = dir
+}
+
+// Evaluation, evaluates the decision of the policy selected by the subject, action and resource of the request.
+func (s *Server) Evaluation(ctx context.Context, req *dsa.EvaluationRequest) (*dsa.EvaluationResponse, error) {
+	return s.evaluate(ctx, req)
+}
+
+// Evaluations, evaluates the evaluations of the request, the subject, action, resource and context
+// of the request are the defaults of the evaluations, an evaluation which fails is denied, and the
+// error is returned in the context of the evaluation response.
+func (s *Server) Evaluations(ctx context.Context, req *dsa.EvaluationsRequest) (*dsa.EvaluationsResponse, error) {
+	semantic, err := evaluationsSemantic(req.GetOptions())
+	if err != nil {
+		return &dsa.EvaluationsResponse{}, err
+	}
+
+	evaluations := req.GetEvaluations()
+	if len(evaluations) == 0 {
+		evaluations = []*dsa.EvaluationRequest{{}}
+	}
+
+	resp := &dsa.EvaluationsResponse{Evaluations: make([]*dsa.EvaluationResponse, 0, len(evaluations))}
+
+	for _, item := range evaluations {
+		result, err := s.evaluate(ctx, withDefaults(item, req))
+		if err != nil {
+			s.logger.Debug().Err(err).Msg("evaluation failed")
+			result = &dsa.EvaluationResponse{
+				Decision: false,
+				Context:  &structpb.Struct{Fields: map[string]*structpb.Value{ContextError: structpb.NewStringValue(err.Error())}},
+			}
+		}
+
+		resp.Evaluations = append(resp.Evaluations, result)
+
+		if (semantic == DenyOnFirstDeny && !result.GetDecision()) || (semantic == PermitOnFirstPermit && result.GetDecision()) {
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+// SubjectSearch, is forwarded to the directory.
+func (s *Server) SubjectSearch(ctx context.Context, req *dsa.SubjectSearchRequest) (*dsa.SubjectSearchResponse, error) {
+	if s.dir != nil {
+		return s.dir.SubjectSearch(ctx, req)
+	}
+
+	return s.authz.DirectoryAccess().SubjectSearch(ctx, req)
+}
+
+// ResourceSearch, is forwarded to the directory.
+func (s *Server) ResourceSearch(ctx context.Context, req *dsa.ResourceSearchRequest) (*dsa.ResourceSearchResponse, error) {
+	if s.dir != nil {
+		return s.dir.ResourceSearch(ctx, req)
+	}
+
+	return s.authz.DirectoryAccess().ResourceSearch(ctx, req)
+}
+
+// ActionSearch, is forwarded to the directory.
+func (s *Server) ActionSearch(ctx context.Context, req *dsa.ActionSearchRequest) (*dsa.ActionSearchResponse, error) {
+	if s.dir != nil {
+		return s.dir.ActionSearch(ctx, req)
+	}
+
+	return s.authz.DirectoryAccess().ActionSearch(ctx, req)
+}
+
+func (s *Server) evaluate(ctx context.Context, req *dsa.EvaluationRequest) (*dsa.EvaluationResponse, error) {
+	if err := verifyRequest(req); err != nil {
+		return nil, err
+	}
+
+	path, err := policyPath(s.path, s.pathParams(req))
+	if err != nil {
+		return nil, aerr.ErrInvalidArgument.Err(err)
+	}
+
+	pc := &api.PolicyContext{Path: path, Decisions: []string{s.decision}}
+	ic := &api.IdentityContext{Type: api.IdentityType_IDENTITY_TYPE_MANUAL, Identity: req.GetSubject().GetId()}
+
+	decisions, structured, err := s.authz.IsInput(ctx, pc, ic, Input(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dsa.EvaluationResponse{}
+
+	for _, d := range decisions {
+		if d.GetDecision() == s.decision {
+			resp.Decision = d.GetIs()
+		}
+	}
+
+	if obj, ok := structured[s.decision]; ok {
+		if resp.Context, err = responseContext(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *Server) pathParams(req *dsa.EvaluationRequest) *PathParams {
+	return &PathParams{
+		Root: s.root,
+		Subject: Entity{
+			Type: sanitize(req.GetSubject().GetType()),
+			ID:   sanitize(req.GetSubject().GetId()),
+		},
+		Action: Entity{
+			Name: sanitize(req.GetAction().GetName()),
+		},
+		Resource: Entity{
+			Type: sanitize(req.GetResource().GetType()),
+			ID:   sanitize(req.GetResource().GetId()),
+		},
+	}
+}
+
+// Input, returns the policy input of the evaluation request.
+func Input(req *dsa.EvaluationRequest) map[string]any {
+	return map[string]any{
+		InputSubject: map[string]any{
+			"type":       req.GetSubject().GetType(),
+			"id":         req.GetSubject().GetId(),
+			"properties": asMap(req.GetSubject().GetProperties()),
+		},
+		InputAction: map[string]any{
+			"name":       req.GetAction().GetName(),
+			"properties": asMap(req.GetAction().GetProperties()),
+		},
+		InputResource: map[string]any{
+			"type":       req.GetResource().GetType(),
+			"id":         req.GetResource().GetId(),
+			"properties": asMap(req.GetResource().GetProperties()),
+		},
+		InputContext: asMap(req.GetContext()),
+	}
+}
+
+func verifyRequest(req *dsa.EvaluationRequest) error {
+	switch {
+	case req.GetSubject().GetType() == "":
+		return aerr.ErrInvalidArgument.Msg("subject type not set")
+	case req.GetAction().GetName() == "":
+		return aerr.ErrInvalidArgument.Msg("action name not set")
+	case req.GetResource().GetType() == "":
+		return aerr.ErrInvalidArgument.Msg("resource type not set")
+	default:
+		return nil
+	}
+}
+
+// withDefaults, returns the evaluation with the subject, action, resource and context of the
+// evaluations request, when not set by the evaluation.
+func withDefaults(item *dsa.EvaluationRequest, req *dsa.EvaluationsRequest) *dsa.EvaluationRequest {
+	out := &dsa.EvaluationRequest{
+		Subject:  item.GetSubject(),
+		Action:   item.GetAction(),
+		Resource: item.GetResource(),
+		Context:  item.GetContext(),
+	}
+
+	if out.Subject == nil {
+		out.Subject = req.GetSubject()
+	}
+
+	if out.Action == nil {
+		out.Action = req.GetAction()
+	}
+
+	if out.Resource == nil {
+		out.Resource = req.GetResource()
+	}
+
+	if out.Context == nil {
+		out.Context = req.GetContext()
+	}
+
+	return out
+}
+
+func evaluationsSemantic(options *structpb.Struct) (string, error) {
+	v, ok := options.GetFields()[OptionEvaluationsSemantic]
+	if !ok {
+		return ExecuteAll, nil
+	}
+
+	switch semantic := v.GetStringValue(); semantic {
+	case ExecuteAll, DenyOnFirstDeny, PermitOnFirstPermit:
+		return semantic, nil
+	default:
+		return "", aerr.ErrInvalidArgument.Msgf("unknown %s %q", OptionEvaluationsSemantic, semantic)
+	}
+}
+
+// responseContext, returns the fields of the structured outcome, other than the decision, as the response context.
+func responseContext(obj map[string]any) (*structpb.Struct, error) {
+	fields := make(map[string]any, len(obj))
+
+	for k, v := range obj {
+		if k == outcome.AllowedField {
+			continue
+		}
+
+		fields[k] = v
+	}
+
+	if len(fields) == 0 {
+		return nil, nil //nolint:nilnil // no context.
+	}
+
+	result, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, aerr.ErrBadQuery.Err(err).Msg("invalid decision context")
+	}
+
+	return result, nil
+}
+
+func asMap(s *structpb.Struct) map[string]any {
+	if s == nil {
+		return map[string]any{}
+	}
+
+	return s.AsMap()
+}
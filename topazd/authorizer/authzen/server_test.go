@@ -0,0 +1,224 @@
+package authzen_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	"github.com/aserto-dev/topaz/topazd/authorizer/authzen"
+	dsa "github.com/authzen/access.go/api/access/v1"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type fakeAuthorizer struct {
+	paths    []string
+	ic       *api.IdentityContext
+	input    map[string]any
+	allowed  map[string]bool
+	outcomes map[string]outcome.Outcomes
+	err      error
+}
+
+func (f *fakeAuthorizer) IsInput(_ context.Context, pc *api.PolicyContext, ic *api.IdentityContext, input map[string]any) (
+	[]*authorizer.Decision, outcome.Outcomes, error,
+) {
+	f.paths = append(f.paths, pc.GetPath())
+	f.ic = ic
+	f.input = input
+
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	return []*authorizer.Decision{{Decision: pc.GetDecisions()[0], Is: f.allowed[pc.GetPath()]}}, f.outcomes[pc.GetPath()], nil
+}
+
+func (f *fakeAuthorizer) DirectoryAccess() dsa.AccessClient {
+	return nil
+}
+
+func newServer(t *testing.T, cfg *authzen.Config, authz authzen.Authorizer) *authzen.Server {
+	t.Helper()
+
+	logger := zerolog.Nop()
+
+	srv, err := authzen.NewServer(cfg, authz, &logger)
+	require.NoError(t, err)
+
+	return srv
+}
+
+func evaluation(subject, action, resource string) *dsa.EvaluationRequest {
+	return &dsa.EvaluationRequest{
+		Subject:  &dsa.Subject{Type: "user", Id: subject},
+		Action:   &dsa.Action{Name: action},
+		Resource: &dsa.Resource{Type: resource, Id: "1"},
+	}
+}
+
+func TestEvaluation(t *testing.T) {
+	authz := &fakeAuthorizer{
+		allowed: map[string]bool{"authzen.todo.can_read": true},
+		outcomes: map[string]outcome.Outcomes{
+			"authzen.todo.can_read": {"allowed": {"allowed": true, "reason": "owner"}},
+		},
+	}
+
+	req := evaluation("beth@the-smiths.com", "can_read", "todo")
+	req.Context = &structpb.Struct{Fields: map[string]*structpb.Value{"ip": structpb.NewStringValue("10.0.0.1")}}
+
+	resp, err := newServer(t, &authzen.Config{}, authz).Evaluation(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.True(t, resp.GetDecision())
+	assert.Equal(t, map[string]any{"reason": "owner"}, resp.GetContext().AsMap())
+	assert.Equal(t, []string{"authzen.todo.can_read"}, authz.paths)
+	assert.Equal(t, api.IdentityType_IDENTITY_TYPE_MANUAL, authz.ic.GetType())
+	assert.Equal(t, "beth@the-smiths.com", authz.ic.GetIdentity())
+	assert.Equal(t, map[string]any{
+		"subject":  map[string]any{"type": "user", "id": "beth@the-smiths.com", "properties": map[string]any{}},
+		"action":   map[string]any{"name": "can_read", "properties": map[string]any{}},
+		"resource": map[string]any{"type": "todo", "id": "1", "properties": map[string]any{}},
+		"context":  map[string]any{"ip": "10.0.0.1"},
+	}, authz.input)
+}
+
+func TestEvaluationPolicyPath(t *testing.T) {
+	authz := &fakeAuthorizer{}
+	cfg := &authzen.Config{PolicyRoot: "acme", PolicyPath: "{{ .Root }}.{{ .Subject.Type }}.{{ .Action.Name }}", Decision: "permit"}
+
+	resp, err := newServer(t, cfg, authz).Evaluation(context.Background(), evaluation("beth", "GET /todos", "todo"))
+	require.NoError(t, err)
+
+	assert.False(t, resp.GetDecision())
+	assert.Nil(t, resp.GetContext())
+	assert.Equal(t, []string{"acme.user.GET__todos"}, authz.paths)
+}
+
+func TestEvaluationInvalid(t *testing.T) {
+	srv := newServer(t, &authzen.Config{}, &fakeAuthorizer{})
+
+	_, err := srv.Evaluation(context.Background(), &dsa.EvaluationRequest{Subject: &dsa.Subject{Type: "user"}})
+	require.Error(t, err)
+
+	logger := zerolog.Nop()
+	_, err = authzen.NewServer(&authzen.Config{PolicyPath: "{{ .Root "}, &fakeAuthorizer{}, &logger)
+	require.Error(t, err)
+}
+
+func TestEvaluations(t *testing.T) {
+	authz := &fakeAuthorizer{
+		allowed: map[string]bool{"authzen.todo.can_read": true},
+	}
+
+	req := &dsa.EvaluationsRequest{
+		Subject:  &dsa.Subject{Type: "user", Id: "beth"},
+		Resource: &dsa.Resource{Type: "todo", Id: "1"},
+		Evaluations: []*dsa.EvaluationRequest{
+			{Action: &dsa.Action{Name: "can_read"}},
+			{Action: &dsa.Action{Name: "can_delete"}},
+			{Action: &dsa.Action{Name: "can_write"}, Resource: &dsa.Resource{Type: "list"}},
+			{},
+		},
+	}
+
+	resp, err := newServer(t, &authzen.Config{}, authz).Evaluations(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.GetEvaluations(), 4)
+
+	assert.True(t, resp.GetEvaluations()[0].GetDecision())
+	assert.False(t, resp.GetEvaluations()[1].GetDecision())
+	assert.False(t, resp.GetEvaluations()[2].GetDecision())
+	assert.False(t, resp.GetEvaluations()[3].GetDecision())
+	assert.Contains(t, resp.GetEvaluations()[3].GetContext().AsMap()[authzen.ContextError], "action name not set")
+	assert.Equal(t, []string{"authzen.todo.can_read", "authzen.todo.can_delete", "authzen.list.can_write"}, authz.paths)
+}
+
+func TestEvaluationsSemantic(t *testing.T) {
+	req := func(semantic string) *dsa.EvaluationsRequest {
+		return &dsa.EvaluationsRequest{
+			Subject:  &dsa.Subject{Type: "user", Id: "beth"},
+			Resource: &dsa.Resource{Type: "todo", Id: "1"},
+			Options: &structpb.Struct{Fields: map[string]*structpb.Value{
+				authzen.OptionEvaluationsSemantic: structpb.NewStringValue(semantic),
+			}},
+			Evaluations: []*dsa.EvaluationRequest{
+				{Action: &dsa.Action{Name: "can_delete"}},
+				{Action: &dsa.Action{Name: "can_read"}},
+				{Action: &dsa.Action{Name: "can_write"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		semantic string
+		expected []bool
+	}{
+		{authzen.ExecuteAll, []bool{false, true, false}},
+		{authzen.DenyOnFirstDeny, []bool{false}},
+		{authzen.PermitOnFirstPermit, []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.semantic, func(t *testing.T) {
+			authz := &fakeAuthorizer{allowed: map[string]bool{"authzen.todo.can_read": true}}
+
+			resp, err := newServer(t, &authzen.Config{}, authz).Evaluations(context.Background(), req(tt.semantic))
+			require.NoError(t, err)
+
+			decisions := make([]bool, 0, len(resp.GetEvaluations()))
+			for _, e := range resp.GetEvaluations() {
+				decisions = append(decisions, e.GetDecision())
+			}
+
+			assert.Equal(t, tt.expected, decisions)
+		})
+	}
+
+	_, err := newServer(t, &authzen.Config{}, &fakeAuthorizer{}).Evaluations(context.Background(), req("first_wins"))
+	require.Error(t, err)
+}
+
+func TestEvaluationsError(t *testing.T) {
+	authz := &fakeAuthorizer{err: aerr.ErrInvalidPolicyID.Msg("undefined policy context")}
+
+	resp, err := newServer(t, &authzen.Config{}, authz).Evaluations(context.Background(), &dsa.EvaluationsRequest{
+		Subject:  &dsa.Subject{Type: "user", Id: "beth"},
+		Action:   &dsa.Action{Name: "can_read"},
+		Resource: &dsa.Resource{Type: "todo", Id: "1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEvaluations(), 1)
+
+	assert.False(t, resp.GetEvaluations()[0].GetDecision())
+	assert.Contains(t, resp.GetEvaluations()[0].GetContext().AsMap(), authzen.ContextError)
+}
+
+type fakeDirectory struct {
+	dsa.UnimplementedAccessServer
+
+	req *dsa.EvaluationRequest
+}
+
+func (f *fakeDirectory) Evaluation(_ context.Context, req *dsa.EvaluationRequest) (*dsa.EvaluationResponse, error) {
+	f.req = req
+
+	return &dsa.EvaluationResponse{Decision: true}, nil
+}
+
+func TestLocalClient(t *testing.T) {
+	dir := &fakeDirectory{}
+	req := evaluation("beth@the-smiths.com", "can_read", "todo")
+
+	resp, err := authzen.LocalClient(dir).Evaluation(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.True(t, resp.GetDecision())
+	assert.Same(t, req, dir.req)
+}
@@ -16,7 +16,7 @@ const azActionSearchHelp string = `az.action_search({
 	"subject": {"type": "", "id": "", "properties": {}},
 	"action": {"name": "", "properties": {}},
 	"resource": {"type": "", "id": "", "properties": {}},
-	"context": {},
+	"context": {"include_relations": false},
 	"page": {
 		"limit": 100,
 		"token": ""
@@ -34,7 +34,7 @@ func RegisterActionSearch(logger *zerolog.Logger, fnName string, ac dsa.AccessCl
 		func(bctx rego.BuiltinContext, op1 *ast.Term) (*ast.Term, error) {
 			var args dsa.ActionSearchRequest
 
-			if err := ast.As(op1.Value, &args); err != nil {
+			if err := builtins.ValueToProto(op1.Value, &args); err != nil {
 				return nil, err
 			}
 
@@ -31,7 +31,7 @@ func RegisterEvaluation(logger *zerolog.Logger, fnName string, ac dsa.AccessClie
 		func(bctx rego.BuiltinContext, op1 *ast.Term) (*ast.Term, error) {
 			var args dsa.EvaluationRequest
 
-			if err := ast.As(op1.Value, &args); err != nil {
+			if err := builtins.ValueToProto(op1.Value, &args); err != nil {
 				return nil, err
 			}
 
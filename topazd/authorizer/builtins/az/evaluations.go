@@ -40,7 +40,7 @@ func RegisterEvaluations(logger *zerolog.Logger, fnName string, ac dsa.AccessCli
 		func(bctx rego.BuiltinContext, op1 *ast.Term) (*ast.Term, error) {
 			var args dsa.EvaluationsRequest
 
-			if err := ast.As(op1.Value, &args); err != nil {
+			if err := builtins.ValueToProto(op1.Value, &args); err != nil {
 				return nil, err
 			}
 
@@ -35,7 +35,7 @@ func RegisterSubjectSearch(logger *zerolog.Logger, fnName string, ac dsa.AccessC
 		func(bctx rego.BuiltinContext, op1 *ast.Term) (*ast.Term, error) {
 			var args dsa.SubjectSearchRequest
 
-			if err := ast.As(op1.Value, &args); err != nil {
+			if err := builtins.ValueToProto(op1.Value, &args); err != nil {
 				return nil, err
 			}
 
@@ -39,12 +39,19 @@ func RegisterCheck(logger *zerolog.Logger, fnName string, dr reader.ReaderClient
 				return ast.StringTerm(dsCheckHelp), nil
 			}
 
+			// when tracing is enabled, collect the relation paths explaining the check outcome.
+			if bctx.TraceEnabled {
+				args.Trace = true
+			}
+
 			resp, err := dr.Check(bctx.Context, &args)
 			if err != nil {
 				builtins.TraceError(&bctx, fnName, err)
 				return nil, err
 			}
 
+			builtins.TraceNote(&bctx, fnName, resp.GetContext())
+
 			return ast.BooleanTerm(resp.GetCheck()), nil
 		}
 }
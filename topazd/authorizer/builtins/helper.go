@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
 	"github.com/open-policy-agent/opa/v1/topdown"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -34,6 +36,22 @@ func ProtoToBuf(w io.Writer, msg proto.Message) error {
 	return nil
 }
 
+// ValueToProto, unmarshal the rego value into the proto message, in contrast to ast.As,
+// well-known types like structpb.Struct (context and properties fields) are preserved.
+func ValueToProto(v ast.Value, msg proto.Message) error {
+	x, err := ast.JSON(v)
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(buf, msg)
+}
+
 // TraceError.
 func TraceError(bctx *topdown.BuiltinContext, fnName string, err error) {
 	if bctx.TraceEnabled {
@@ -46,6 +64,23 @@ func TraceError(bctx *topdown.BuiltinContext, fnName string, err error) {
 	}
 }
 
+// TraceNote, emits a note event containing the marshaled proto message, when tracing is enabled.
+func TraceNote(bctx *topdown.BuiltinContext, fnName string, msg proto.Message) {
+	if !bctx.TraceEnabled || len(bctx.QueryTracers) == 0 {
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ProtoToBuf(buf, msg); err != nil {
+		return
+	}
+
+	bctx.QueryTracers[0].TraceEvent(topdown.Event{
+		Op:      topdown.NoteOp,
+		Message: fmt.Sprintf("%s explain:%s", fnName, buf.String()),
+	})
+}
+
 type Message[T any] interface {
 	proto.Message
 	*T
@@ -71,3 +106,18 @@ func ResponseToTerm[T any, M Message[T]](resp M) (*ast.Term, error) {
 
 	return ast.NewTerm(v), nil
 }
+
+// Traced, wraps the built-in function in a span named after the function, the directory and
+// access calls of the built-in are children of the span.
+func Traced(fn *rego.Function, impl rego.Builtin1) (*rego.Function, rego.Builtin1) {
+	return fn, func(bctx rego.BuiltinContext, op1 *ast.Term) (*ast.Term, error) {
+		ctx, span := telemetry.Start(bctx.Context, fn.Name)
+		bctx.Context = ctx
+
+		result, err := impl(bctx, op1)
+
+		telemetry.End(span, err)
+
+		return result, err
+	}
+}
@@ -0,0 +1,75 @@
+// Package registry, lists the custom built-ins registered with the topaz runtime, the
+// directory (ds.*) and authZen (az.*) functions, the single list used by the runtime, the
+// policy compile checks, the policy unit tests and the decision replay.
+package registry
+
+import (
+	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/az"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/ds"
+	dsa "github.com/authzen/access.go/api/access/v1"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/rs/zerolog"
+)
+
+// Builtin, the declaration and implementation of a custom built-in.
+type Builtin struct {
+	Decl *rego.Function
+	Impl rego.Builtin1
+}
+
+// Builtins, returns the custom built-ins of the topaz runtime, backed by the directory reader
+// and access clients, the declarations do not depend on the clients, which can be nil when
+// the built-ins are not evaluated.
+func Builtins(logger *zerolog.Logger, dsClient dsr.ReaderClient, acClient dsa.AccessClient) []*Builtin {
+	return []*Builtin{
+		// directory get functions
+		newBuiltin(ds.RegisterIdentity(logger, builtins.DSIdentity, dsClient)),
+		newBuiltin(ds.RegisterUser(logger, builtins.DSUser, dsClient)),
+		newBuiltin(ds.RegisterObject(logger, builtins.DSObject, dsClient)),
+		newBuiltin(ds.RegisterRelation(logger, builtins.DSRelation, dsClient)),
+		newBuiltin(ds.RegisterRelations(logger, builtins.DSRelations, dsClient)),
+		newBuiltin(ds.RegisterGraph(logger, builtins.DSGraph, dsClient)),
+
+		// authorization check functions
+		newBuiltin(ds.RegisterCheck(logger, builtins.DSCheck, dsClient)),
+		newBuiltin(ds.RegisterChecks(logger, builtins.DSChecks, dsClient)),
+
+		// authZen built-ins
+		newBuiltin(az.RegisterEvaluation(logger, builtins.AZEvaluation, acClient)),
+		newBuiltin(az.RegisterEvaluations(logger, builtins.AZEvaluations, acClient)),
+		newBuiltin(az.RegisterSubjectSearch(logger, builtins.AZSubjectSearch, acClient)),
+		newBuiltin(az.RegisterResourceSearch(logger, builtins.AZResourceSearch, acClient)),
+		newBuiltin(az.RegisterActionSearch(logger, builtins.AZActionSearch, acClient)),
+	}
+}
+
+// newBuiltin, returns the built-in, traced in a span named after the function.
+func newBuiltin(decl *rego.Function, impl rego.Builtin1) *Builtin {
+	decl, impl = builtins.Traced(decl, impl)
+
+	return &Builtin{Decl: decl, Impl: impl}
+}
+
+// Decls, returns the declarations of the custom built-ins.
+func Decls(logger *zerolog.Logger) []*rego.Function {
+	fns := Builtins(logger, nil, nil)
+
+	decls := make([]*rego.Function, 0, len(fns))
+	for _, fn := range fns {
+		decls = append(decls, fn.Decl)
+	}
+
+	return decls
+}
+
+// Functions, returns the custom built-ins as rego options, for the evaluation of queries outside of the runtime.
+func Functions(fns []*Builtin) []func(*rego.Rego) {
+	opts := make([]func(*rego.Rego), 0, len(fns))
+	for _, fn := range fns {
+		opts = append(opts, rego.Function1(fn.Decl, fn.Impl))
+	}
+
+	return opts
+}
@@ -0,0 +1,115 @@
+// Package extauthz, implements the Envoy external authorization (ext_authz) gRPC service,
+// the HTTP request attributes of the check request are evaluated as an Is request by the
+// topaz authorizer.
+package extauthz
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPolicyPath, the policy path evaluated when no policy path template is configured.
+	DefaultPolicyPath = "envoy.authz"
+	// DefaultDecision, the decision evaluated when no decision is configured.
+	DefaultDecision = "allowed"
+	// HeadersField, the field of the structured decision outcome holding the response headers.
+	HeadersField = "headers"
+)
+
+// Config, ext_authz service configuration.
+type Config struct {
+	// PolicyPath, text/template of the policy path, executed with the PathParams of the request,
+	// for example "myapp.{{ .Method }}.{{ .Path }}".
+	PolicyPath string `json:"policy_path"`
+	// Decision, the evaluated decision, either a boolean or a structured outcome.
+	Decision string `json:"decision"`
+	// Headers, the names of the headers, of the headers field of a structured decision outcome,
+	// returned to envoy, on allow as upstream request headers, on deny as response headers.
+	Headers []string `json:"headers"`
+}
+
+// PathParams, the parameters of the policy path template.
+type PathParams struct {
+	// Method, the HTTP method, e.g. GET.
+	Method string
+	// Path, the URL path as a dot separated policy path, e.g. /api/todos/1 becomes api.todos._1.
+	Path string
+	// Segments, the URL path segments.
+	Segments []string
+	// Host, the HTTP host.
+	Host string
+}
+
+// decision, returns the configured decision or the default decision.
+func (c *Config) decision() string {
+	if c.Decision == "" {
+		return DefaultDecision
+	}
+
+	return c.Decision
+}
+
+// pathTemplate, returns the parsed policy path template.
+func (c *Config) pathTemplate() (*template.Template, error) {
+	text := c.PolicyPath
+	if text == "" {
+		text = DefaultPolicyPath
+	}
+
+	tmpl, err := template.New("policy_path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid ext_authz policy path template %q", text)
+	}
+
+	return tmpl, nil
+}
+
+// policyPath, returns the policy path of the template executed with the path params.
+func policyPath(tmpl *template.Template, params *PathParams) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", errors.Wrap(err, "failed to execute policy path template")
+	}
+
+	return strings.Trim(buf.String(), "."), nil
+}
+
+// newPathParams, returns the path params of the request method, host and URL path, the path
+// segments are sanitized, characters which are not valid in a rego reference are replaced by '_',
+// and a segment starting with a digit is prefixed with '_'.
+func newPathParams(method, host, path string) *PathParams {
+	segments := []string{}
+
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "" {
+			continue
+		}
+
+		segment = strings.Map(sanitize, segment)
+		if segment[0] >= '0' && segment[0] <= '9' {
+			segment = "_" + segment
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return &PathParams{
+		Method:   strings.ToUpper(method),
+		Path:     strings.Join(segments, "."),
+		Segments: segments,
+		Host:     host,
+	}
+}
+
+func sanitize(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+		return r
+	default:
+		return '_'
+	}
+}
@@ -0,0 +1,222 @@
+package extauthz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"text/template"
+
+	cerr "github.com/aserto-dev/errors"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/rs/zerolog"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	headerAuthorization = "authorization"
+	bearerScheme        = "bearer"
+)
+
+// Authorizer, evaluates and logs the decisions of an Is request, and returns the structured outcomes,
+// implemented by the outcome service of the authorizer.
+type Authorizer interface {
+	Is(ctx context.Context, req *outcome.IsRequest) (*outcome.IsResponse, error)
+}
+
+// Server, the envoy ext_authz authorization server.
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+
+	authorizer Authorizer
+	tmpl       *template.Template
+	decision   string
+	headers    []string
+	logger     *zerolog.Logger
+}
+
+var _ authv3.AuthorizationServer = (*Server)(nil)
+
+// NewServer, returns an ext_authz server evaluating the check requests using the authorizer.
+func NewServer(cfg *Config, authz Authorizer, logger *zerolog.Logger) (*Server, error) {
+	tmpl, err := cfg.pathTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	log := logger.With().Str("component", "ext_authz").Logger()
+
+	return &Server{
+		authorizer: authz,
+		tmpl:       tmpl,
+		decision:   cfg.decision(),
+		headers:    cfg.Headers,
+		logger:     &log,
+	}, nil
+}
+
+// Check, evaluates the HTTP request attributes of the check request, the request is allowed
+// when the decision is true, denied with 403 when false, and with 401 when the bearer token
+// cannot be resolved to a user.
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	isReq, err := s.IsRequest(req)
+	if err != nil {
+		return nil, aerr.ErrInvalidArgument.Err(err).Msg("failed to map check request")
+	}
+
+	resp, err := s.authorizer.Is(ctx, isReq)
+
+	switch {
+	case cerr.Equals(err, aerr.ErrAuthenticationFailed):
+		s.logger.Debug().Err(err).Str("path", isReq.GetPolicyContext().GetPath()).Msg("unauthenticated")
+		return deniedResponse(codes.Unauthenticated, typev3.StatusCode_Unauthorized, nil), nil
+	case err != nil:
+		return nil, err
+	}
+
+	headers := s.responseHeaders(resp.GetOutcomes()[s.decision].AsMap())
+
+	for _, d := range resp.GetDecisions() {
+		if d.GetDecision() == s.decision && d.GetIs() {
+			return okResponse(headers), nil
+		}
+	}
+
+	return deniedResponse(codes.PermissionDenied, typev3.StatusCode_Forbidden, headers), nil
+}
+
+// IsRequest, returns the Is request of the check request, the policy path is the executed
+// path template, the identity is the bearer token of the authorization header, and the
+// resource context holds the method, path, query, host and the remaining request headers.
+func (s *Server) IsRequest(req *authv3.CheckRequest) (*outcome.IsRequest, error) {
+	httpReq := req.GetAttributes().GetRequest().GetHttp()
+
+	path, query, _ := strings.Cut(httpReq.GetPath(), "?")
+	if query == "" {
+		query = httpReq.GetQuery()
+	}
+
+	policyPath, err := policyPath(s.tmpl, newPathParams(httpReq.GetMethod(), httpReq.GetHost(), path))
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]any{}
+	token := ""
+
+	for key, value := range httpReq.GetHeaders() {
+		if strings.EqualFold(key, headerAuthorization) {
+			token = bearerToken(value)
+			continue
+		}
+
+		headers[strings.ToLower(key)] = value
+	}
+
+	resource, err := structpb.NewStruct(map[string]any{
+		"method":  httpReq.GetMethod(),
+		"path":    path,
+		"query":   query,
+		"host":    httpReq.GetHost(),
+		"headers": headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &api.IdentityContext{Type: api.IdentityType_IDENTITY_TYPE_NONE}
+	if token != "" {
+		identity = &api.IdentityContext{Type: api.IdentityType_IDENTITY_TYPE_JWT, Identity: token}
+	}
+
+	return &outcome.IsRequest{
+		PolicyContext: &api.PolicyContext{
+			Path:      policyPath,
+			Decisions: []string{s.decision},
+		},
+		IdentityContext: identity,
+		ResourceContext: resource,
+	}, nil
+}
+
+// responseHeaders, returns the configured headers of the headers field of the structured outcome.
+func (s *Server) responseHeaders(obj map[string]any) []*corev3.HeaderValueOption {
+	values, ok := obj[HeadersField].(map[string]any)
+	if !ok || len(s.headers) == 0 {
+		return nil
+	}
+
+	headers := []*corev3.HeaderValueOption{}
+
+	for _, name := range s.headers {
+		for key, value := range values {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+
+			v, err := headerValue(value)
+			if err != nil {
+				s.logger.Warn().Err(err).Str("header", name).Msg("invalid header value")
+				continue
+			}
+
+			headers = append(headers, &corev3.HeaderValueOption{
+				Header:       &corev3.HeaderValue{Key: strings.ToLower(name), Value: v},
+				AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+			})
+		}
+	}
+
+	return headers
+}
+
+// headerValue, returns string values as is, and the JSON encoding of other values.
+func headerValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func bearerToken(value string) string {
+	scheme, token, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+func okResponse(headers []*corev3.HeaderValueOption) *authv3.CheckResponse {
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{
+			OkResponse: &authv3.OkHttpResponse{Headers: headers},
+		},
+	}
+}
+
+func deniedResponse(code codes.Code, httpCode typev3.StatusCode, headers []*corev3.HeaderValueOption) *authv3.CheckResponse {
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: http.StatusText(int(httpCode))},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{
+			DeniedResponse: &authv3.DeniedHttpResponse{
+				Status:  &typev3.HttpStatus{Code: httpCode},
+				Headers: headers,
+			},
+		},
+	}
+}
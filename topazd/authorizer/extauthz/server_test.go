@@ -0,0 +1,180 @@
+package extauthz_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	"github.com/aserto-dev/topaz/topazd/authorizer/extauthz"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeAuthorizer struct {
+	req      *outcome.IsRequest
+	allowed  bool
+	outcomes outcome.Outcomes
+	err      error
+}
+
+func (f *fakeAuthorizer) Is(_ context.Context, req *outcome.IsRequest) (*outcome.IsResponse, error) {
+	f.req = req
+	if f.err != nil {
+		return &outcome.IsResponse{}, f.err
+	}
+
+	outcomes, err := f.outcomes.Structs()
+	if err != nil {
+		return &outcome.IsResponse{}, err
+	}
+
+	return &outcome.IsResponse{
+		Decisions: []*authorizer.Decision{{Decision: req.GetPolicyContext().GetDecisions()[0], Is: f.allowed}},
+		Outcomes:  outcomes,
+	}, nil
+}
+
+func checkRequest(method, path string, headers map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Method:  method,
+					Path:    path,
+					Host:    "todo.example.com",
+					Headers: headers,
+				},
+			},
+		},
+	}
+}
+
+func newServer(t *testing.T, cfg *extauthz.Config, authz extauthz.Authorizer) *extauthz.Server {
+	t.Helper()
+
+	logger := zerolog.Nop()
+
+	srv, err := extauthz.NewServer(cfg, authz, &logger)
+	require.NoError(t, err)
+
+	return srv
+}
+
+func TestIsRequest(t *testing.T) {
+	srv := newServer(t, &extauthz.Config{PolicyPath: "todo.{{ .Method }}.{{ .Path }}"}, &fakeAuthorizer{})
+
+	req, err := srv.IsRequest(checkRequest("get", "/api/todos/a-1?done=true", map[string]string{
+		"authorization": "Bearer ey.token",
+		"x-request-id":  "42",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "todo.GET.api.todos.a_1", req.GetPolicyContext().GetPath())
+	assert.Equal(t, []string{extauthz.DefaultDecision}, req.GetPolicyContext().GetDecisions())
+	assert.Equal(t, api.IdentityType_IDENTITY_TYPE_JWT, req.GetIdentityContext().GetType())
+	assert.Equal(t, "ey.token", req.GetIdentityContext().GetIdentity())
+
+	resource := req.GetResourceContext().AsMap()
+	assert.Equal(t, "/api/todos/a-1", resource["path"])
+	assert.Equal(t, "done=true", resource["query"])
+	assert.Equal(t, "todo.example.com", resource["host"])
+	assert.Equal(t, map[string]any{"x-request-id": "42"}, resource["headers"])
+}
+
+func TestIsRequestNumericSegment(t *testing.T) {
+	srv := newServer(t, &extauthz.Config{PolicyPath: "todo.{{ .Method }}.{{ .Path }}"}, &fakeAuthorizer{})
+
+	req, err := srv.IsRequest(checkRequest("GET", "/api/todos/1", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "todo.GET.api.todos._1", req.GetPolicyContext().GetPath())
+	assert.Equal(t, "/api/todos/1", req.GetResourceContext().AsMap()["path"])
+}
+
+func TestIsRequestAnonymous(t *testing.T) {
+	srv := newServer(t, &extauthz.Config{}, &fakeAuthorizer{})
+
+	req, err := srv.IsRequest(checkRequest("POST", "/", map[string]string{"authorization": "Basic dXNlcg=="}))
+	require.NoError(t, err)
+
+	assert.Equal(t, extauthz.DefaultPolicyPath, req.GetPolicyContext().GetPath())
+	assert.Equal(t, api.IdentityType_IDENTITY_TYPE_NONE, req.GetIdentityContext().GetType())
+}
+
+func TestInvalidTemplate(t *testing.T) {
+	logger := zerolog.Nop()
+
+	_, err := extauthz.NewServer(&extauthz.Config{PolicyPath: "{{ .Method "}, &fakeAuthorizer{}, &logger)
+	require.Error(t, err)
+}
+
+func TestCheckAllowed(t *testing.T) {
+	authz := &fakeAuthorizer{
+		allowed: true,
+		outcomes: outcome.Outcomes{
+			"allowed": {"allowed": true, "headers": map[string]any{"X-User-ID": "rick", "x-roles": []any{"admin"}, "x-secret": "s"}},
+		},
+	}
+
+	srv := newServer(t, &extauthz.Config{Headers: []string{"x-user-id", "x-roles"}}, authz)
+
+	resp, err := srv.Check(context.Background(), checkRequest("GET", "/", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode())
+	require.NotNil(t, resp.GetOkResponse())
+
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+
+	assert.Equal(t, map[string]string{"x-user-id": "rick", "x-roles": `["admin"]`}, headers)
+}
+
+func TestCheckDenied(t *testing.T) {
+	authz := &fakeAuthorizer{
+		outcomes: outcome.Outcomes{
+			"allowed": {"allowed": false, "headers": map[string]any{"x-reason": "not an owner"}},
+		},
+	}
+
+	srv := newServer(t, &extauthz.Config{Headers: []string{"x-reason"}}, authz)
+
+	resp, err := srv.Check(context.Background(), checkRequest("DELETE", "/api/todos/1", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(codes.PermissionDenied), resp.GetStatus().GetCode())
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, typev3.StatusCode_Forbidden, resp.GetDeniedResponse().GetStatus().GetCode())
+	require.Len(t, resp.GetDeniedResponse().GetHeaders(), 1)
+	assert.Equal(t, "not an owner", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+}
+
+func TestCheckUnauthenticated(t *testing.T) {
+	authz := &fakeAuthorizer{err: aerr.ErrAuthenticationFailed.Msg("invalid token")}
+
+	srv := newServer(t, &extauthz.Config{}, authz)
+
+	resp, err := srv.Check(context.Background(), checkRequest("GET", "/", map[string]string{"authorization": "Bearer bad"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(codes.Unauthenticated), resp.GetStatus().GetCode())
+	assert.Equal(t, typev3.StatusCode_Unauthorized, resp.GetDeniedResponse().GetStatus().GetCode())
+}
+
+func TestCheckError(t *testing.T) {
+	authz := &fakeAuthorizer{err: aerr.ErrBadQuery.Msg("undefined results")}
+
+	srv := newServer(t, &extauthz.Config{}, authz)
+
+	_, err := srv.Check(context.Background(), checkRequest("GET", "/", nil))
+	require.Error(t, err)
+}
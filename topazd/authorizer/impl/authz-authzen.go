@@ -0,0 +1,50 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	dsa "github.com/authzen/access.go/api/access/v1"
+)
+
+// IsInput, evaluates and logs the decisions of the policy context for the given policy input,
+// used by the policy backed AuthZEN evaluations, which build the input from the evaluation
+// request, instead of resolving the identity context.
+func (s *AuthorizerServer) IsInput(
+	ctx context.Context,
+	pc *api.PolicyContext,
+	ic *api.IdentityContext,
+	input map[string]any,
+) ([]*authorizer.Decision, outcome.Outcomes, error) {
+	s.logger.Debug().Str("api", "authzen").Str("path", pc.GetPath()).Interface("input", input).Msg("is")
+
+	rt, err := s.resolver.GetRuntimeResolver().GetRuntime(ctx, "", pc.GetPath())
+	if err != nil {
+		return nil, nil, aerr.ErrInvalidPolicyID.Err(err).Msg("undefined policy context")
+	}
+
+	query, err := s.prepareIsQuery(ctx, rt, pc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decisions, structured, err := evalIsQuery(ctx, query, input, pc, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &authorizer.IsRequest{PolicyContext: pc, IdentityContext: ic}
+	if err := s.logIsDecision(ctx, rt, req, input, decisions, structured.Annotations()); err != nil {
+		return decisions, structured, err
+	}
+
+	return decisions, structured, nil
+}
+
+// DirectoryAccess, returns the AuthZEN access client of the directory.
+func (s *AuthorizerServer) DirectoryAccess() dsa.AccessClient {
+	return dsa.NewAccessClient(s.resolver.GetDirectoryResolver().GetConn())
+}
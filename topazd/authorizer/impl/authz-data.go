@@ -0,0 +1,105 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/internal/dataapi"
+	"github.com/aserto-dev/topaz/internal/header"
+	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AnnotationResult, decision annotation containing the JSON encoded result of a data API evaluation.
+const AnnotationResult string = "result"
+
+// DataEvaluate, evaluates data.<path> of the OPA compatible data API, against the runtime of the
+// policy instance selected by name or by the path prefix, the input is passed as is.
+func (s *AuthorizerServer) DataEvaluate(ctx context.Context, req *dataapi.Request) (*dataapi.Response, error) {
+	s.logger.Debug().Str("api", "data").Str("path", req.Path).Msg("data")
+
+	rt, err := s.resolver.GetRuntimeResolver().GetRuntime(ctx, req.Instance, req.PolicyPath())
+	if err != nil {
+		return nil, aerr.ErrInvalidPolicyID.Err(err).Msg("undefined policy context")
+	}
+
+	resp, err := dataapi.Evaluate(ctx, rt.GetPluginsManager().GetCompiler(), rt.GetPluginsManager().Store, req)
+	if err != nil {
+		return nil, aerr.ErrBadQuery.Err(err).Msgf("data evaluation failed: path=%s", req.PolicyPath())
+	}
+
+	resp.DecisionID = uuid.NewString()
+
+	if err := s.logDataDecision(ctx, rt, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// logDataDecision, logs the data API decision to the decision logger of the policy instance
+// runtime, when configured, a boolean result is logged as the outcome of the path.
+func (s *AuthorizerServer) logDataDecision(ctx context.Context, rt *runtime.Runtime, req *dataapi.Request, resp *dataapi.Response) error {
+	dlPlugin := topaz_file_decision_logger.Lookup(rt.GetPluginsManager())
+	if dlPlugin == nil {
+		return nil
+	}
+
+	annotations := map[string]string{}
+	outcomes := map[string]bool{}
+
+	if resp.Result != nil {
+		if allowed, ok := (*resp.Result).(bool); ok {
+			outcomes[req.PolicyPath()] = allowed
+		}
+
+		buf, err := json.Marshal(*resp.Result)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal decision result")
+		}
+
+		annotations[AnnotationResult] = string(buf)
+	}
+
+	if dlPlugin.CaptureInput() && req.Input != nil {
+		buf, err := json.Marshal(*req.Input)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal decision input")
+		}
+
+		annotations[topaz_file_decision_logger.AnnotationInput] = string(buf)
+	}
+
+	if name := header.ExtractAPIKeyName(ctx); name != "" {
+		annotations[topaz_file_decision_logger.AnnotationAPIKey] = name
+	}
+
+	if name := header.ExtractClientIdentity(ctx); name != "" {
+		annotations[topaz_file_decision_logger.AnnotationClientIdentity] = name
+	}
+
+	if subject := header.ExtractTokenSubject(ctx); subject != "" {
+		annotations[topaz_file_decision_logger.AnnotationTokenSubject] = subject
+	}
+
+	d := api.Decision{
+		Id:        resp.DecisionID,
+		Timestamp: timestamppb.New(time.Now().In(time.UTC)),
+		Path:      req.PolicyPath(),
+		Policy: &api.DecisionPolicy{
+			Context: &api.PolicyContext{Path: req.PolicyPath()},
+		},
+		User:        &api.DecisionUser{},
+		Outcomes:    outcomes,
+		Annotations: annotations,
+	}
+
+	return dlPlugin.LogDecision(ctx, &d)
+}
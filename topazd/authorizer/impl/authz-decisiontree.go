@@ -30,7 +30,7 @@ func (s *AuthorizerServer) DecisionTree(ctx context.Context, req *authorizer.Dec
 
 	log.Debug().Interface("input", input).Msg("decision_tree")
 
-	rt, err := s.getRuntime(ctx)
+	rt, err := s.getRuntime(ctx, req)
 	if err != nil {
 		return &authorizer.DecisionTreeResponse{}, err
 	}
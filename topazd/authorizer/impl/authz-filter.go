@@ -0,0 +1,35 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/filter"
+)
+
+var _ filter.FilterServer = (*AuthorizerServer)(nil)
+
+// Filter, compiles the request query with the request unknowns (e.g. input.resource), and translates
+// the partial evaluation result into a filter, optionally rendered as a SQL where clause or MongoDB query.
+func (s *AuthorizerServer) Filter(ctx context.Context, req *filter.FilterRequest) (*filter.FilterResponse, error) {
+	if len(req.GetUnknowns()) == 0 {
+		return nil, aerr.ErrInvalidArgument.Msg("unknowns not set")
+	}
+
+	resp, err := s.Compile(ctx, req.CompileRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := filter.FromPartial(resp.GetResult().AsMap(), req.GetUnknowns())
+	if err != nil {
+		return nil, aerr.ErrInvalidArgument.Err(err).Msg("compile result cannot be translated into a filter")
+	}
+
+	result, err := filter.Render(node, req.GetDialect(), req.GetMapping())
+	if err != nil {
+		return nil, aerr.ErrInvalidArgument.Err(err).Msg("filter cannot be rendered")
+	}
+
+	return result, nil
+}
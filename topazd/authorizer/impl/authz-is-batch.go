@@ -0,0 +1,157 @@
+package impl
+
+import (
+	"context"
+	"maps"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/internal/batch"
+
+	"github.com/google/uuid"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// DefaultBatchConcurrency, maximum number of concurrent evaluations per batch.
+	DefaultBatchConcurrency int = 8
+	// MaxBatchSize, maximum number of resource contexts per batch.
+	MaxBatchSize int = 1000
+)
+
+type batchServer struct {
+	authz *AuthorizerServer
+}
+
+var _ batch.BatchServer = (*batchServer)(nil)
+
+// Batch, returns the batch decision service of the authorizer.
+func (s *AuthorizerServer) Batch() batch.BatchServer {
+	return &batchServer{authz: s}
+}
+
+// Is, evaluates the decisions of the policy context for the identity context and each resource context.
+func (b *batchServer) Is(ctx context.Context, req *batch.IsRequest) (*batch.IsResponse, error) {
+	return b.authz.IsBatch(ctx, req)
+}
+
+// IsBatch, evaluates the decisions of the policy context for the identity context and each resource context.
+//
+// The identity is resolved, and the query is prepared, once per batch, the resource contexts are evaluated
+// concurrently, and the results are returned in the order of the resource contexts. A failed evaluation is
+// reported in the result of the resource context, and does not fail the batch. Every decision is logged with
+// the batch id annotation shared by the batch.
+func (s *AuthorizerServer) IsBatch(ctx context.Context, req *batch.IsRequest) (*batch.IsResponse, error) {
+	log := s.logger.With().Str("api", "is_batch").Logger()
+
+	if err := s.isBatchVerifyRequest(req); err != nil {
+		return nil, err
+	}
+
+	identityInput := map[string]any{}
+	if err := s.resolveIdentityContext(ctx, req.GetIdentityContext(), identityInput); err != nil {
+		return nil, err
+	}
+
+	identityInput[InputPolicy] = req.GetPolicyContext()
+
+	rt, err := s.getRuntime(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := s.prepareIsQuery(ctx, rt, req.GetPolicyContext())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &batch.IsResponse{
+		BatchId: uuid.NewString(),
+		Results: make([]*batch.Result, len(req.GetResourceContexts())),
+	}
+
+	log.Debug().Str("batch_id", resp.GetBatchId()).Int("size", len(req.GetResourceContexts())).Msg("is_batch")
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(req.Limit(DefaultBatchConcurrency))
+
+	for i, resource := range req.GetResourceContexts() {
+		g.Go(func() error {
+			input := maps.Clone(identityInput)
+			input[InputResource] = resource
+
+			// every result is written to its own index, no locking required.
+			resp.Results[i] = s.isBatchEval(gCtx, rt, query, req, input, resp.GetBatchId())
+
+			return gCtx.Err()
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *AuthorizerServer) isBatchEval(
+	ctx context.Context,
+	rt *runtime.Runtime,
+	query rego.PreparedEvalQuery,
+	req *batch.IsRequest,
+	input map[string]any,
+	batchID string,
+) *batch.Result {
+	decisions, structured, err := evalIsQuery(ctx, query, input, req.GetPolicyContext(), req.GetStrict())
+	if err != nil {
+		return &batch.Result{Error: err.Error()}
+	}
+
+	annotations := structured.Annotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[batch.AnnotationBatchID] = batchID
+
+	if err := s.logIsDecision(ctx, rt, req, input, decisions, annotations); err != nil {
+		s.logger.Warn().Err(err).Str("batch_id", batchID).Msg("failed to log decision")
+	}
+
+	outcomes, err := structured.Structs()
+	if err != nil {
+		return &batch.Result{Error: err.Error()}
+	}
+
+	return &batch.Result{Decisions: getOutcomes(decisions), Outcomes: outcomes}
+}
+
+func (*AuthorizerServer) isBatchVerifyRequest(req *batch.IsRequest) error {
+	if req.GetPolicyContext() == nil {
+		return aerr.ErrInvalidArgument.Msg("policy context not set")
+	}
+
+	if req.GetPolicyContext().GetPath() == "" {
+		return aerr.ErrInvalidArgument.Msg("policy context path not set")
+	}
+
+	if len(req.GetPolicyContext().GetDecisions()) == 0 {
+		return aerr.ErrInvalidArgument.Msg("policy context decisions not set")
+	}
+
+	if req.GetIdentityContext() == nil {
+		return aerr.ErrInvalidArgument.Msg("identity context not set")
+	}
+
+	if req.GetIdentityContext().GetType() == api.IdentityType_IDENTITY_TYPE_UNKNOWN {
+		return aerr.ErrInvalidArgument.Msg("identity type UNKNOWN")
+	}
+
+	if len(req.GetResourceContexts()) > MaxBatchSize {
+		return aerr.ErrInvalidArgument.Msgf("batch size %d exceeds the maximum of %d resource contexts", len(req.GetResourceContexts()), MaxBatchSize)
+	}
+
+	return nil
+}
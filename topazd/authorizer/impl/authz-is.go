@@ -2,7 +2,9 @@ package impl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
@@ -10,46 +12,106 @@ import (
 	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
 	"github.com/aserto-dev/go-authorizer/pkg/aerr"
 	"github.com/aserto-dev/go-directory/pkg/pb"
+	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/internal/header"
+	"github.com/aserto-dev/topaz/internal/outcome"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/aserto-dev/topaz/topazd/authorizer/plugins/topaz_file_decision_logger"
 
 	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/v1/rego"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-//nolint:funlen
+// Is, evaluates the decisions of the Is request, only boolean decision outcomes are accepted,
+// structured decision outcomes are evaluated by the outcome service.
 func (s *AuthorizerServer) Is(ctx context.Context, req *authorizer.IsRequest) (*authorizer.IsResponse, error) {
+	resp, _, err := s.isOutcomes(ctx, req, true)
+	return resp, err
+}
+
+// isOutcomes, evaluates and logs the decisions of the Is request, in strict mode only boolean decision outcomes are accepted.
+func (s *AuthorizerServer) isOutcomes(
+	ctx context.Context,
+	req *authorizer.IsRequest,
+	strict bool,
+) (*authorizer.IsResponse, outcome.Outcomes, error) {
 	log := s.logger.With().Str("api", "is").Logger()
 
 	if err := s.isVerifyRequest(req); err != nil {
-		return &authorizer.IsResponse{}, err
+		return &authorizer.IsResponse{}, nil, err
 	}
 
-	input, err := s.isSetInput(ctx, req)
+	identityCtx, span := telemetry.Start(ctx, "is.identity", attribute.String("identity.type", req.GetIdentityContext().GetType().String()))
+	input, err := s.isSetInput(identityCtx, req)
+	telemetry.End(span, err)
+
 	if err != nil {
-		return &authorizer.IsResponse{}, err
+		return &authorizer.IsResponse{}, nil, err
 	}
 
 	log.Debug().Interface("input", input).Msg("is")
 
-	rt, err := s.getRuntime(ctx)
+	rt, err := s.getRuntime(ctx, req)
+	if err != nil {
+		return &authorizer.IsResponse{}, nil, err
+	}
+
+	policyAttr := attribute.String("policy.path", req.GetPolicyContext().GetPath())
+
+	prepareCtx, span := telemetry.Start(ctx, "is.prepare", policyAttr)
+	query, err := s.prepareIsQuery(prepareCtx, rt, req.GetPolicyContext())
+	telemetry.End(span, err)
+
+	if err != nil {
+		observeIsDecisions(req.GetPolicyContext().GetPath(), req.GetPolicyContext().GetDecisions(), nil, err)
+		return &authorizer.IsResponse{}, nil, err
+	}
+
+	start := time.Now()
+
+	evalCtx, span := telemetry.Start(ctx, "is.eval", policyAttr, attribute.StringSlice("policy.decisions", req.GetPolicyContext().GetDecisions()))
+	decisions, structured, err := evalIsQuery(evalCtx, query, input, req.GetPolicyContext(), strict)
+	telemetry.End(span, err)
+
+	observeIsEvaluation(req.GetPolicyContext().GetPath(), start)
+	observeIsDecisions(req.GetPolicyContext().GetPath(), req.GetPolicyContext().GetDecisions(), decisions, err)
+
 	if err != nil {
-		return &authorizer.IsResponse{}, err
+		return &authorizer.IsResponse{}, nil, err
+	}
+
+	s.shadowIs(ctx, rt, req, input, decisions, strict)
+
+	resp := &authorizer.IsResponse{
+		Decisions: decisions,
+	}
+
+	if err := s.logIsDecision(ctx, rt, req, input, decisions, structured.Annotations()); err != nil {
+		return resp, structured, err
 	}
 
-	// The Rego query body and its prepared form depend only on the policy
-	// path and the decisions list — both stable for the lifetime of the
-	// active OPA compiler. Cache the PreparedEvalQuery so repeated Is()
-	// calls for the same (path, decisions) skip the parse + plan work and
-	// stop fighting each other on the compiler's internal locks. The cache
-	// is invalidated whenever the compiler is rotated (bundle reload).
-	policyPath := req.GetPolicyContext().GetPath()
-	decisions := req.GetPolicyContext().GetDecisions()
+	return resp, structured, nil
+}
+
+// prepareIsQuery, returns the prepared query evaluating the decisions of the policy context.
+//
+// The Rego query body and its prepared form depend only on the policy
+// path and the decisions list — both stable for the lifetime of the
+// active OPA compiler. Cache the PreparedEvalQuery so repeated Is()
+// calls for the same (path, decisions) skip the parse + plan work and
+// stop fighting each other on the compiler's internal locks. The cache
+// is invalidated whenever the compiler is rotated (bundle reload).
+func (s *AuthorizerServer) prepareIsQuery(ctx context.Context, rt *runtime.Runtime, pc *api.PolicyContext) (rego.PreparedEvalQuery, error) {
+	policyPath := pc.GetPath()
+	decisions := pc.GetDecisions()
 	preparedKey := cacheKey(policyPath, decisions)
 
-	query, err := s.preparedQueries.getOrPrepare(ctx, rt, preparedKey, func(ctx context.Context) (rego.PreparedEvalQuery, error) {
+	return s.queryCache(rt).getOrPrepare(ctx, rt, preparedKey, func(ctx context.Context) (rego.PreparedEvalQuery, error) {
 		queryStmt := strings.Builder{}
 
 		for i, decision := range decisions {
@@ -84,45 +146,99 @@ func (s *AuthorizerServer) Is(ctx context.Context, req *authorizer.IsRequest) (*
 
 		return prepared, nil
 	})
-	if err != nil {
-		return &authorizer.IsResponse{}, err
-	}
-
-	resp := &authorizer.IsResponse{
-		Decisions: []*authorizer.Decision{},
-	}
+}
 
+// evalIsQuery, evaluates the prepared query for the input, and returns the decisions,
+// and the structured (object valued) outcomes, which are accepted unless strict is set.
+func evalIsQuery(
+	ctx context.Context,
+	query rego.PreparedEvalQuery,
+	input map[string]any,
+	pc *api.PolicyContext,
+	strict bool,
+) ([]*authorizer.Decision, outcome.Outcomes, error) {
 	queryResults, err := query.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
-		return resp, aerr.ErrBadQuery.Err(err).Msgf("query evaluation failed: path=%s decisions=%v", policyPath, decisions)
+		return nil, nil, aerr.ErrBadQuery.Err(err).Msgf("query evaluation failed: path=%s decisions=%v", pc.GetPath(), pc.GetDecisions())
 	}
 
 	if len(queryResults) == 0 {
-		return resp, aerr.ErrBadQuery.Err(err).Msgf("undefined results: path=%s decisions=%v", policyPath, decisions)
+		return nil, nil, aerr.ErrBadQuery.Msgf("undefined results: path=%s decisions=%v", pc.GetPath(), pc.GetDecisions())
 	}
 
-	for i, d := range req.GetPolicyContext().GetDecisions() {
+	decisions := make([]*authorizer.Decision, 0, len(pc.GetDecisions()))
+	structured := outcome.Outcomes{}
+
+	for i, d := range pc.GetDecisions() {
 		v, ok := queryResults[0].Bindings[fmt.Sprintf("x%d", i)]
 		if !ok {
-			return nil, errors.Wrapf(err, "failed getting binding for decision [%s]", d)
+			return nil, nil, errors.Errorf("failed getting binding for decision [%s]", d)
 		}
 
-		outcome, ok := v.(bool)
-		if !ok {
-			return nil, errors.Wrapf(err, "non-boolean outcome for decision [%s]: %s", d, v)
+		allowed, obj, err := outcome.Parse(v, strict)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "decision [%s]: %v", d, v)
 		}
 
-		decision := authorizer.Decision{
-			Decision: d,
-			Is:       outcome,
+		if obj != nil {
+			structured[d] = obj
 		}
 
-		resp.Decisions = append(resp.GetDecisions(), &decision)
+		decisions = append(decisions, &authorizer.Decision{
+			Decision: d,
+			Is:       allowed,
+		})
 	}
 
+	return decisions, structured, nil
+}
+
+// isDecisionRequest, the request of a logged decision, an Is request or a batch request.
+type isDecisionRequest interface {
+	GetPolicyContext() *api.PolicyContext
+	GetIdentityContext() *api.IdentityContext
+}
+
+// logIsDecision, logs the decision to the decision logger of the policy instance runtime, when configured.
+func (s *AuthorizerServer) logIsDecision(
+	ctx context.Context,
+	rt *runtime.Runtime,
+	req isDecisionRequest,
+	input map[string]any,
+	decisions []*authorizer.Decision,
+	annotations map[string]string,
+) error {
 	dlPlugin := topaz_file_decision_logger.Lookup(rt.GetPluginsManager())
 	if dlPlugin == nil {
-		return resp, err
+		return nil
+	}
+
+	resource, _ := input[InputResource].(*structpb.Struct)
+
+	annotations = maps.Clone(annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if dlPlugin.CaptureInput() {
+		buf, err := json.Marshal(input)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal decision input")
+		}
+
+		annotations[topaz_file_decision_logger.AnnotationInput] = string(buf)
+	}
+
+	if name := header.ExtractAPIKeyName(ctx); name != "" {
+		annotations[topaz_file_decision_logger.AnnotationAPIKey] = name
+	}
+
+	if name := header.ExtractClientIdentity(ctx); name != "" {
+		annotations[topaz_file_decision_logger.AnnotationClientIdentity] = name
+	}
+
+	if subject := header.ExtractTokenSubject(ctx); subject != "" {
+		annotations[topaz_file_decision_logger.AnnotationTokenSubject] = subject
 	}
 
 	d := api.Decision{
@@ -137,15 +253,12 @@ func (s *AuthorizerServer) Is(ctx context.Context, req *authorizer.IsRequest) (*
 			Id:      getID(input),
 			Email:   getEmail(input),
 		},
-		Resource: req.GetResourceContext(),
-		Outcomes: getOutcomes(resp.GetDecisions()),
+		Resource:    resource,
+		Outcomes:    getOutcomes(decisions),
+		Annotations: annotations,
 	}
 
-	if err := dlPlugin.LogDecision(ctx, &d); err != nil {
-		return resp, err
-	}
-
-	return resp, err
+	return dlPlugin.LogDecision(ctx, &d)
 }
 
 func getOutcomes(decisions []*authorizer.Decision) map[string]bool {
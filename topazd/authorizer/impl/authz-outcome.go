@@ -0,0 +1,43 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/internal/outcome"
+)
+
+type outcomeServer struct {
+	authz *AuthorizerServer
+}
+
+var _ outcome.OutcomeServer = (*outcomeServer)(nil)
+
+// Outcome, returns the outcome service, which returns the structured decision outcomes of the authorizer.
+func (s *AuthorizerServer) Outcome() outcome.OutcomeServer {
+	return &outcomeServer{authz: s}
+}
+
+// Is, evaluates the decisions of the request, and returns the boolean decisions and the structured outcomes.
+func (o *outcomeServer) Is(ctx context.Context, req *outcome.IsRequest) (*outcome.IsResponse, error) {
+	resp, structured, err := o.authz.isOutcomes(ctx, &authorizer.IsRequest{
+		IdentityContext: req.GetIdentityContext(),
+		PolicyContext:   req.GetPolicyContext(),
+		ResourceContext: req.GetResourceContext(),
+		PolicyInstance:  req.GetPolicyInstance(),
+	}, req.GetStrict())
+	if err != nil {
+		return &outcome.IsResponse{}, err
+	}
+
+	outcomes, err := structured.Structs()
+	if err != nil {
+		return &outcome.IsResponse{}, aerr.ErrBadQuery.Err(err).Msg("invalid decision outcome")
+	}
+
+	return &outcome.IsResponse{
+		Decisions: resp.GetDecisions(),
+		Outcomes:  outcomes,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package impl
+
+import (
+	"context"
+
+	"github.com/aserto-dev/go-authorizer/pkg/aerr"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
+	"github.com/samber/lo"
+)
+
+type policyStatusServer struct {
+	authz *AuthorizerServer
+}
+
+var _ policywatch.PolicyStatusServer = (*policyStatusServer)(nil)
+
+// PolicyStatus, returns the policy status service of the authorizer.
+func (s *AuthorizerServer) PolicyStatus() policywatch.PolicyStatusServer {
+	return &policyStatusServer{authz: s}
+}
+
+// Status, returns the compile status, the loaded revision and the last compile errors,
+// of the watched local policy directories, optionally of a single policy instance.
+func (p *policyStatusServer) Status(ctx context.Context, req *policywatch.StatusRequest) (*policywatch.StatusResponse, error) {
+	rtResolver := p.authz.resolver.GetRuntimeResolver()
+
+	instance := req.GetInstance()
+	if instance != "" && !lo.Contains(rtResolver.Instances(), instance) {
+		return nil, aerr.ErrInvalidPolicyID.Msgf("unknown policy instance %q", instance)
+	}
+
+	resp := &policywatch.StatusResponse{Policies: []*policywatch.InstanceStatus{}}
+
+	for _, status := range rtResolver.PolicyStatus() {
+		if instance == "" || status.Instance == instance {
+			resp.Policies = append(resp.Policies, status.Proto())
+		}
+	}
+
+	return resp, nil
+}
@@ -3,14 +3,20 @@ package impl
 import (
 	"context"
 	goruntime "runtime"
+	"sync/atomic"
 	"time"
 
 	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2/api"
 	"github.com/aserto-dev/go-authorizer/pkg/aerr"
 	runtime "github.com/aserto-dev/runtime"
 
+	"github.com/aserto-dev/topaz/internal/reload"
+	"github.com/aserto-dev/topaz/internal/tsync"
 	"github.com/aserto-dev/topaz/pkg/config"
 	"github.com/aserto-dev/topaz/topazd/authorizer/resolvers"
+	"github.com/aserto-dev/topaz/topazd/directory"
+	"github.com/aserto-dev/topaz/topazd/service/builder"
 	"github.com/aserto-dev/topaz/topazd/version"
 
 	"github.com/open-policy-agent/opa/v1/server/types"
@@ -22,6 +28,7 @@ const (
 	InputIdentity string = "identity"
 	InputPolicy   string = "policy"
 	InputResource string = "resource"
+	InputClaims   string = "claims"
 )
 
 const cleanupTimeout = 30 * time.Second
@@ -29,14 +36,21 @@ const cleanupTimeout = 30 * time.Second
 type AuthorizerServer struct {
 	cfg         *config.Common
 	logger      *zerolog.Logger
-	jwtResolver *jwtResolver
+	jwtResolver atomic.Pointer[jwtResolver]
 	resolver    *resolvers.Resolvers
 
+	// identities caches the identity to user resolution of the directory.
+	identities *directory.IdentityCache
+
+	// shadow evaluates the shadow policy for a sample of the Is requests.
+	shadow *shadowEvaluator
+
 	// preparedQueries memoizes the rego.PreparedEvalQuery values produced
-	// for each (policy path, decisions) tuple seen via Is(). Without this
-	// cache every Is() call re-parses + re-plans the same Rego query and
-	// serializes goroutines on the OPA compiler's internal structures.
-	preparedQueries *preparedQueryCache
+	// for each (policy path, decisions) tuple seen via Is(), with a cache
+	// per policy instance runtime. Without this cache every Is() call
+	// re-parses + re-plans the same Rego query and serializes goroutines
+	// on the OPA compiler's internal structures.
+	preparedQueries tsync.Map[*runtime.Runtime, *preparedQueryCache]
 }
 
 func NewAuthorizerServer(
@@ -56,22 +70,73 @@ func NewAuthorizerServer(
 		return nil, err
 	}
 
+	identities := directory.NewIdentityCache(cfg.IdentityCache.MaxSize(), cfg.IdentityCache.TTLDuration())
+
+	if err := builder.RegisterCollectors(directory.IdentityCacheCollectors()...); err != nil {
+		newLogger.Warn().Err(err).Msg("failed to register identity cache metrics")
+	}
+
+	shadow := newShadowEvaluator(&newLogger, cfg.Shadow)
+
+	if err := builder.RegisterCollectors(ShadowCollectors()...); err != nil {
+		newLogger.Warn().Err(err).Msg("failed to register shadow evaluation metrics")
+	}
+
+	if err := builder.RegisterCollectors(AuthorizerCollectors()...); err != nil {
+		newLogger.Warn().Err(err).Msg("failed to register authorization metrics")
+	}
+
+	server := &AuthorizerServer{
+		cfg:        cfg,
+		logger:     &newLogger,
+		resolver:   rf,
+		identities: identities,
+		shadow:     shadow,
+	}
+	server.jwtResolver.Store(jwtResolver)
+
 	go func() { //nolint:gosec // G118 - cleanup cannot use request context as it is already cancelled.
 		<-ctx.Done()
 
 		cleanupCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
 		defer cancel()
 
-		_ = jwtResolver.Stop(cleanupCtx)
+		_ = server.jwtResolver.Load().Stop(cleanupCtx)
+
+		identities.Close()
+		shadow.Close()
 	}()
 
-	return &AuthorizerServer{
-		cfg:             cfg,
-		logger:          &newLogger,
-		jwtResolver:     jwtResolver,
-		resolver:        rf,
-		preparedQueries: newPreparedQueryCache(),
-	}, nil
+	return server, nil
+}
+
+// PrepareJWT, starts the JWT resolver of the configuration, the key sets of the issuers are loaded
+// before the change is returned, the change replaces the JWT resolver of the identity resolution
+// when committed, the resolver which is replaced, or discarded, is stopped.
+func (s *AuthorizerServer) PrepareJWT(ctx context.Context, cfg *config.JWT) (*reload.Change, error) {
+	resolver, err := NewJWTResolver(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolver.Start(ctx); err != nil {
+		_ = resolver.Stop(ctx)
+		return nil, err
+	}
+
+	stop := func(r *jwtResolver) {
+		cleanupCtx, cancel := context.WithTimeout(ctx, cleanupTimeout)
+		defer cancel()
+
+		if err := r.Stop(cleanupCtx); err != nil {
+			s.logger.Warn().Err(err).Msg("failed to stop jwt resolver")
+		}
+	}
+
+	commit := func() { stop(s.jwtResolver.Swap(resolver)) }
+	discard := func() { stop(resolver) }
+
+	return reload.NewChange(commit, discard), nil
 }
 
 func (s *AuthorizerServer) Info(ctx context.Context, req *authorizer.InfoRequest) (*authorizer.InfoResponse, error) {
@@ -88,15 +153,43 @@ func (s *AuthorizerServer) Info(ctx context.Context, req *authorizer.InfoRequest
 	return res, nil
 }
 
-func (s *AuthorizerServer) getRuntime(ctx context.Context) (*runtime.Runtime, error) {
-	rt, err := s.resolver.GetRuntimeResolver().GetRuntime(ctx)
+// policyInstanceRequest, request carrying the (deprecated) policy instance, used to select the policy instance by name.
+type policyInstanceRequest interface {
+	GetPolicyInstance() *api.PolicyInstance
+}
+
+// policyContextRequest, request carrying the policy context, used to select the policy instance by policy path prefix.
+type policyContextRequest interface {
+	GetPolicyContext() *api.PolicyContext
+}
+
+// getRuntime, returns the runtime of the policy instance selected by the request,
+// by policy instance name, by policy path prefix, or the default runtime.
+func (s *AuthorizerServer) getRuntime(ctx context.Context, req policyInstanceRequest) (*runtime.Runtime, error) {
+	path := ""
+	if pcr, ok := req.(policyContextRequest); ok {
+		path = pcr.GetPolicyContext().GetPath()
+	}
+
+	rt, err := s.resolver.GetRuntimeResolver().GetRuntime(ctx, req.GetPolicyInstance().GetName(), path)
 	if err != nil {
-		return nil, aerr.ErrInvalidPolicyID.Msg("undefined policy context")
+		return nil, aerr.ErrInvalidPolicyID.Err(err).Msg("undefined policy context")
 	}
 
 	return rt, err
 }
 
+// queryCache, returns the prepared query cache of the policy instance runtime.
+func (s *AuthorizerServer) queryCache(rt *runtime.Runtime) *preparedQueryCache {
+	if c, ok := s.preparedQueries.Load(rt); ok {
+		return c
+	}
+
+	c, _ := s.preparedQueries.LoadOrStore(rt, newPreparedQueryCache())
+
+	return c
+}
+
 func traceLevelToExplainModeV2(t authorizer.TraceLevel) types.ExplainModeV1 {
 	switch t {
 	case authorizer.TraceLevel_TRACE_LEVEL_UNKNOWN:
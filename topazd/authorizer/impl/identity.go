@@ -10,7 +10,6 @@ import (
 	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
 	"github.com/aserto-dev/go-directory/pkg/pb"
 	"github.com/aserto-dev/topaz/pkg/grpcc"
-	"github.com/aserto-dev/topaz/topazd/directory"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 )
@@ -20,6 +19,7 @@ const identityResolutionTimeout = 60 * time.Second
 // resolveIdentityContext.
 func (s *AuthorizerServer) resolveIdentityContext(ctx context.Context, identityContext *api.IdentityContext, input map[string]any) error {
 	if identityContext == nil {
+		identityResolutionFailures.WithLabelValues(api.IdentityType_IDENTITY_TYPE_UNKNOWN.String(), reasonIdentityContextMissing).Inc()
 		return aerr.ErrInvalidArgument.Msg("identity context not set")
 	}
 
@@ -33,14 +33,28 @@ func (s *AuthorizerServer) resolveIdentityContext(ctx context.Context, identityC
 	defer cancel()
 
 	// Step 1: save identity context in to input.identity.
-	input[InputIdentity] = grpcc.ProtoToAny(identityContext)
+	identityInput := grpcc.ProtoToAny(identityContext)
+	input[InputIdentity] = identityInput
 
 	// Step 2: resolve identity from identity context
-	identity, err := s.resolveSubjectFromIdentityContext(ctx, identityContext)
+	identity, claims, err := s.resolveSubjectFromIdentityContext(ctx, identityContext)
 	if err != nil {
+		reason := reasonIdentityInvalid
+		if identityContext.GetType() == api.IdentityType_IDENTITY_TYPE_JWT {
+			reason = reasonTokenInvalid
+		}
+
+		identityResolutionFailures.WithLabelValues(identityContext.GetType().String(), identityFailureReason(ctx, err, reason)).Inc()
+
 		return aerr.ErrAuthenticationFailed.WithGRPCStatus(codes.NotFound).Msg("failed to resolve identity context")
 	}
 
+	// the JWT claims are available to policies as input.identity.claims, only when the token
+	// signature is verified, in insecure mode the claims are not set, as anyone can forge them.
+	if m, ok := identityInput.(map[string]any); ok && claims != nil {
+		m[InputClaims] = claims
+	}
+
 	// if IDENTITY_TYPE_MANUAL, there resulting user object is an empty JSON object.
 	if identityContext.GetType() == api.IdentityType_IDENTITY_TYPE_MANUAL {
 		input[InputUser] = pb.NewStruct()
@@ -50,6 +64,8 @@ func (s *AuthorizerServer) resolveIdentityContext(ctx context.Context, identityC
 	// Step 3: resolve user from identity.
 	user, err := s.resolveUserFromSubject(ctx, identity)
 	if err != nil {
+		identityResolutionFailures.WithLabelValues(identityContext.GetType().String(), identityFailureReason(ctx, err, reasonUserNotFound)).Inc()
+
 		return aerr.ErrAuthenticationFailed.WithGRPCStatus(codes.NotFound).Msg("failed to resolve user from identity")
 	}
 
@@ -59,33 +75,44 @@ func (s *AuthorizerServer) resolveIdentityContext(ctx context.Context, identityC
 	return nil
 }
 
-func (s *AuthorizerServer) resolveSubjectFromIdentityContext(ctx context.Context, identityContext *api.IdentityContext) (string, error) {
+// resolveSubjectFromIdentityContext, returns the subject of the identity context, and the claims
+// when the identity is a JWT with a verified signature.
+func (s *AuthorizerServer) resolveSubjectFromIdentityContext(ctx context.Context, identityContext *api.IdentityContext) (string, map[string]any, error) {
 	if identityContext.GetIdentity() == "" {
-		return "", errors.Errorf("identity value not set (type: %s)", identityContext.GetType().String())
+		return "", nil, errors.Errorf("identity value not set (type: %s)", identityContext.GetType().String())
 	}
 
 	switch identityContext.GetType() {
 	case api.IdentityType_IDENTITY_TYPE_SUB:
-		return identityContext.GetIdentity(), nil
+		return identityContext.GetIdentity(), nil, nil
 
 	case api.IdentityType_IDENTITY_TYPE_JWT:
-		return s.jwtResolver.ResolveSubject(ctx, identityContext.GetIdentity())
+		resolved, err := s.jwtResolver.Load().Resolve(ctx, identityContext.GetIdentity())
+		if err != nil {
+			return "", nil, err
+		}
+
+		if !resolved.Verified {
+			return resolved.Subject, nil, nil
+		}
+
+		return resolved.Subject, resolved.Claims, nil
 
 	case api.IdentityType_IDENTITY_TYPE_MANUAL:
-		return identityContext.GetIdentity(), nil
+		return identityContext.GetIdentity(), nil, nil
 
 	case api.IdentityType_IDENTITY_TYPE_NONE:
 		fallthrough
 
 	default:
-		return "", errors.Errorf("invalid identity type %s", identityContext.GetType().String())
+		return "", nil, errors.Errorf("invalid identity type %s", identityContext.GetType().String())
 	}
 }
 
 func (s *AuthorizerServer) resolveUserFromSubject(ctx context.Context, subject string) (*dsc.Object, error) {
 	client := dsr.NewReaderClient(s.resolver.GetDirectoryResolver().GetConn())
 
-	objResp, err := directory.ResolveIdentity(ctx, client, subject)
+	objResp, err := s.identities.ResolveIdentity(ctx, client, subject)
 	if err != nil {
 		return nil, err
 	}
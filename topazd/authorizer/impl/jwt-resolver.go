@@ -2,23 +2,45 @@ package impl
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
 
 	"github.com/aserto-dev/topaz/pkg/config"
 	"github.com/pkg/errors"
 
 	"github.com/jwx-go/jwkfetch/v4"
 	"github.com/lestrrat-go/httprc/v3"
+	"github.com/lestrrat-go/jwx/v4/jwk"
+	"github.com/lestrrat-go/jwx/v4/jws"
 	"github.com/lestrrat-go/jwx/v4/jwt"
 )
 
+const (
+	defaultSubjectClaim string = "sub"
+	claimPathSeparator  string = "."
+	jwsSegments         int    = 3
+)
+
 type jwtResolver struct {
 	cache          *jwkfetch.Cache
 	jwtConfig      *config.JWT
 	issuerToConfig map[string]*OidcConfiguration
+	issuers        map[string]*config.JWTIssuer
+	staticKeys     map[string]jwk.Set
 	oidcClient     *OidcClient
 }
 
-func NewJWTResolver(ctx context.Context, config *config.JWT) (*jwtResolver, error) {
+// resolvedToken, the subject and the claims of a token, the signature of the token is not
+// verified when the resolver runs in insecure mode (no issuer key sets are configured).
+type resolvedToken struct {
+	Subject  string
+	Claims   map[string]any
+	Verified bool
+}
+
+func NewJWTResolver(ctx context.Context, cfg *config.JWT) (*jwtResolver, error) {
 	// create cache instance
 	cache, err := jwkfetch.NewCache(ctx, httprc.NewClient())
 	if err != nil {
@@ -27,17 +49,38 @@ func NewJWTResolver(ctx context.Context, config *config.JWT) (*jwtResolver, erro
 
 	resolver := jwtResolver{
 		cache:          cache,
-		jwtConfig:      config,
+		jwtConfig:      cfg,
 		issuerToConfig: make(map[string]*OidcConfiguration),
+		issuers:        make(map[string]*config.JWTIssuer),
+		staticKeys:     make(map[string]jwk.Set),
 		oidcClient:     NewOidcClient(),
 	}
 
+	for _, issuer := range cfg.Issuers {
+		resolver.issuers[issuer.Issuer] = issuer
+	}
+
 	return &resolver, nil
 }
 
 func (r *jwtResolver) Start(ctx context.Context) error {
-	// hydrate the configs map for each allowed issuer, registered in config.jwt.allowed_issuers.
-	for _, configAllowedIssuer := range r.jwtConfig.AllowedIssuers {
+	// load the static key sets of the issuers registered in config.jwt.issuers.
+	for _, issuer := range r.jwtConfig.Issuers {
+		if !issuer.HasStaticKeys() {
+			continue
+		}
+
+		keySet, err := loadStaticKeys(issuer)
+		if err != nil {
+			return errors.Wrapf(err, "issuer %q", issuer.Issuer)
+		}
+
+		r.staticKeys[issuer.Issuer] = keySet
+	}
+
+	// hydrate the configs map for each allowed issuer, registered in config.jwt.allowed_issuers,
+	// and each issuer registered in config.jwt.issuers without a static key source.
+	for _, configAllowedIssuer := range r.discoveryIssuers() {
 		config, err := r.oidcClient.FetchAndValidateConfig(ctx, configAllowedIssuer)
 		if err != nil {
 			return err
@@ -75,8 +118,31 @@ func (r *jwtResolver) Stop(ctx context.Context) error {
 	return r.cache.Shutdown(ctx)
 }
 
+// discoveryIssuers, returns the issuers which keys are retrieved using OIDC discovery.
+func (r *jwtResolver) discoveryIssuers() []string {
+	issuers := slices.Clone(r.jwtConfig.AllowedIssuers)
+
+	for _, issuer := range r.jwtConfig.Issuers {
+		if !issuer.HasStaticKeys() && !slices.Contains(issuers, issuer.Issuer) {
+			issuers = append(issuers, issuer.Issuer)
+		}
+	}
+
+	return issuers
+}
+
 // ResolveSubject, token is expected to be a string in JWS compact serialization format.
 func (r *jwtResolver) ResolveSubject(ctx context.Context, token string) (string, error) {
+	resolved, err := r.Resolve(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	return resolved.Subject, nil
+}
+
+// Resolve, verifies the token and returns the subject, using the claim mapping of the issuer, and the token claims.
+func (r *jwtResolver) Resolve(ctx context.Context, token string) (*resolvedToken, error) {
 	unverifiedOptions := []jwt.ParseOption{
 		jwt.WithVerify(false),
 		jwt.WithValidate(false),
@@ -84,74 +150,221 @@ func (r *jwtResolver) ResolveSubject(ctx context.Context, token string) (string,
 
 	unverifiedToken, err := jwt.ParseString(token, unverifiedOptions...)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	unverifiedIssuer, ok := unverifiedToken.Issuer()
 	if !ok || unverifiedIssuer == "" {
-		return "", errors.Errorf("unverified token does not contain an issuer field")
+		return nil, errors.Errorf("unverified token does not contain an issuer field")
 	}
 
-	// if not OIDC config URLs registered, we can only resolve insecurely as there are no JWKS key sets.
-	if len(r.issuerToConfig) == 0 {
-		return r.resolveSubjectInsecure(token)
-	}
+	issuer := r.issuerConfig(unverifiedIssuer)
 
-	// look up JKWS URI from OIDC config using the unverified issuer value.
-	cfg, ok := r.issuerToConfig[unverifiedIssuer]
-	if !ok {
-		return "", errors.Errorf("No mapping found of issuer %q to JWKS URI", unverifiedIssuer)
+	// if no OIDC config URLs or static keys are registered, we can only resolve insecurely as there are no key sets.
+	if len(r.issuerToConfig) == 0 && len(r.staticKeys) == 0 {
+		return r.resolveInsecure(token, issuer)
 	}
 
-	// fetch JWKS key set from the cache.
-	keySet, err := r.cache.Fetch(ctx, cfg.JwksURI)
+	keySet, err := r.keySet(ctx, unverifiedIssuer)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if err := verifyAlgorithm(token, issuer.Algorithms); err != nil {
+		return nil, err
 	}
 
 	verifiedOptions := []jwt.ParseOption{
 		jwt.WithValidate(true),
 		jwt.WithAcceptableSkew(r.jwtConfig.AcceptableTimeSkewDuration()),
-		jwt.WithKeySet(keySet),
+	}
+
+	if issuer.HasStaticKeys() {
+		// PEM encoded public keys carry neither a key id nor an algorithm.
+		verifiedOptions = append(verifiedOptions, jwt.WithKeySet(keySet, jws.WithInferAlgorithmFromKey(true), jws.WithRequireKid(false)))
+	} else {
+		verifiedOptions = append(verifiedOptions, jwt.WithKeySet(keySet))
 	}
 
 	if r.jwtConfig.ExpectedAudience != "" {
 		verifiedOptions = append(verifiedOptions, jwt.WithAudience(r.jwtConfig.ExpectedAudience))
 	}
 
-	// verify JWT using JWKS key set.
-	verifiedToken, err := jwt.ParseString(token, verifiedOptions...)
+	for _, claim := range issuer.RequiredClaims {
+		verifiedOptions = append(verifiedOptions, jwt.WithRequiredClaim(claim))
+	}
+
+	// verify JWT using the key set.
+	if _, err := jwt.ParseString(token, verifiedOptions...); err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolveClaims(token, issuer)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// get the subject used to resolve the to the User object instance.
-	subject, ok := verifiedToken.Subject()
+	resolved.Verified = true
+
+	return resolved, nil
+}
+
+// keySet, returns the static key set of the issuer, or the key set retrieved using OIDC discovery.
+func (r *jwtResolver) keySet(ctx context.Context, issuer string) (jwk.Set, error) {
+	if keySet, ok := r.staticKeys[issuer]; ok {
+		return keySet, nil
+	}
+
+	// look up JKWS URI from OIDC config using the unverified issuer value.
+	cfg, ok := r.issuerToConfig[issuer]
 	if !ok {
-		return "", errors.Errorf("no verified subject found")
+		return nil, errors.Errorf("No mapping found of issuer %q to JWKS URI", issuer)
+	}
+
+	// fetch JWKS key set from the cache.
+	return r.cache.Fetch(ctx, cfg.JwksURI)
+}
+
+// issuerConfig, returns the configuration of the issuer, or the default configuration (subject claim sub).
+func (r *jwtResolver) issuerConfig(issuer string) *config.JWTIssuer {
+	if cfg, ok := r.issuers[issuer]; ok {
+		return cfg
 	}
 
-	return subject, nil
+	return &config.JWTIssuer{Issuer: issuer}
 }
 
-func (r *jwtResolver) resolveSubjectInsecure(token string) (string, error) {
+func (r *jwtResolver) resolveInsecure(token string, issuer *config.JWTIssuer) (*resolvedToken, error) {
 	verifiedOptions := []jwt.ParseOption{
 		jwt.WithVerify(false),
 		jwt.WithValidate(true),
 		jwt.WithAcceptableSkew(r.jwtConfig.AcceptableTimeSkewDuration()),
 	}
 
+	for _, claim := range issuer.RequiredClaims {
+		verifiedOptions = append(verifiedOptions, jwt.WithRequiredClaim(claim))
+	}
+
 	// verify JWT
-	verifiedToken, err := jwt.ParseString(token, verifiedOptions...)
+	if _, err := jwt.ParseString(token, verifiedOptions...); err != nil {
+		return nil, err
+	}
+
+	return resolveClaims(token, issuer)
+}
+
+// resolveClaims, returns the claims of a validated token, and the subject claim mapped by the issuer configuration.
+func resolveClaims(token string, issuer *config.JWTIssuer) (*resolvedToken, error) {
+	claims, err := tokenSegment(token, 1)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	subjectClaim := issuer.SubjectClaim
+	if subjectClaim == "" {
+		subjectClaim = defaultSubjectClaim
 	}
 
 	// get the subject used to resolve the to the User object instance.
-	subject, ok := verifiedToken.Subject()
-	if !ok {
-		return "", errors.Errorf("no verified subject found")
+	subject, ok := claimValue(claims, subjectClaim).(string)
+	if !ok || subject == "" {
+		return nil, errors.Errorf("no verified subject found in claim %q", subjectClaim)
+	}
+
+	return &resolvedToken{Subject: subject, Claims: claims}, nil
+}
+
+// claimValue, returns the value of the claim, the claim name is either a top level claim name,
+// like https://example.com/email, or a dot separated path of a nested claim, like realm_access.email.
+func claimValue(claims map[string]any, claim string) any {
+	if v, ok := claims[claim]; ok {
+		return v
+	}
+
+	var v any = claims
+
+	for _, name := range strings.Split(claim, claimPathSeparator) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		v = m[name]
+	}
+
+	return v
+}
+
+// verifyAlgorithm, verifies the signing algorithm of the token header is allowed, when algorithms are configured.
+func verifyAlgorithm(token string, algorithms []string) error {
+	if len(algorithms) == 0 {
+		return nil
+	}
+
+	header, err := tokenSegment(token, 0)
+	if err != nil {
+		return err
+	}
+
+	alg, _ := header["alg"].(string)
+	if !slices.Contains(algorithms, alg) {
+		return errors.Errorf("signing algorithm %q not allowed", alg)
+	}
+
+	return nil
+}
+
+// tokenSegment, returns the decoded JSON of the header (0) or payload (1) segment of a compact serialized token.
+func tokenSegment(token string, index int) (map[string]any, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != jwsSegments {
+		return nil, errors.Errorf("invalid token format")
+	}
+
+	buf, err := base64.RawURLEncoding.DecodeString(segments[index])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid token encoding")
+	}
+
+	v := map[string]any{}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return nil, errors.Wrap(err, "invalid token json")
+	}
+
+	return v, nil
+}
+
+// loadStaticKeys, returns the key set of the issuer JWKS file and PEM encoded public keys.
+func loadStaticKeys(issuer *config.JWTIssuer) (jwk.Set, error) {
+	keySet := jwk.NewSet()
+
+	if issuer.JWKSFile != "" {
+		fileSet, err := jwk.ReadFile(issuer.JWKSFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read jwks file %q", issuer.JWKSFile)
+		}
+
+		for i := range fileSet.Len() {
+			key, _ := fileSet.Key(i)
+			if err := keySet.AddKey(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, pem := range issuer.PublicKeys {
+		pemSet, err := jwk.Parse([]byte(pem), jwk.WithPEM(true))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse public_keys[%d]", i)
+		}
+
+		for j := range pemSet.Len() {
+			key, _ := pemSet.Key(j)
+			if err := keySet.AddKey(key); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return subject, nil
+	return keySet, nil
 }
@@ -6,7 +6,9 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -375,6 +377,17 @@ func TestJWTResolverLocal_InsecureMode(t *testing.T) {
 		require.Equal(t, testSubject, subject)
 	})
 
+	t.Run("claims of an unverified token are marked unverified", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		token := signTestToken(t, key, "https://issuer.example/not-configured", "", now, now.Add(time.Hour))
+
+		resolved, err := resolver.Resolve(t.Context(), token)
+		require.NoError(t, err)
+		require.False(t, resolved.Verified)
+	})
+
 	t.Run("expired token is still rejected", func(t *testing.T) {
 		t.Parallel()
 
@@ -385,3 +398,116 @@ func TestJWTResolverLocal_InsecureMode(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+// pemPublicKey returns the PEM encoding of the public key of priv, as configured in config.jwt.issuers[].public_keys.
+func pemPublicKey(t *testing.T, priv *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// signClaimsToken signs a token with the given claims, without a key id, as issued by an issuer with static keys.
+func signClaimsToken(t *testing.T, priv *ecdsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+
+	builder := jwt.NewBuilder().
+		IssuedAt(time.Now()).
+		Expiration(time.Now().Add(time.Hour))
+
+	for k, v := range claims {
+		builder = builder.Claim(k, v)
+	}
+
+	tok, err := builder.Build()
+	require.NoError(t, err)
+
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.ES256(), priv))
+	require.NoError(t, err)
+
+	return string(signed)
+}
+
+// TestJWTResolverStaticKeys covers static PEM key sources, claim mapping,
+// required claims and allowed algorithms, without OIDC discovery.
+func TestJWTResolverStaticKeys(t *testing.T) {
+	t.Parallel()
+
+	const issuer = "https://issuer.example/static"
+
+	priv := newECDSAKey(t)
+
+	resolver, err := NewJWTResolver(t.Context(), &config.JWT{
+		AcceptableTimeSkewSeconds: 5,
+		Issuers: []*config.JWTIssuer{{
+			Issuer:         issuer,
+			SubjectClaim:   "profile.email",
+			RequiredClaims: []string{"profile"},
+			Algorithms:     []string{"ES256"},
+			PublicKeys:     []string{pemPublicKey(t, priv)},
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, resolver.Start(t.Context()))
+
+	t.Run("nested subject claim", func(t *testing.T) {
+		t.Parallel()
+
+		token := signClaimsToken(t, priv, map[string]any{
+			"iss":     issuer,
+			"sub":     testSubject,
+			"profile": map[string]any{"email": "euang@acmecorp.com"},
+		})
+
+		resolved, err := resolver.Resolve(t.Context(), token)
+		require.NoError(t, err)
+		require.Equal(t, "euang@acmecorp.com", resolved.Subject)
+		require.Equal(t, testSubject, resolved.Claims["sub"])
+		require.True(t, resolved.Verified)
+	})
+
+	t.Run("missing required claim is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		token := signClaimsToken(t, priv, map[string]any{"iss": issuer, "sub": testSubject})
+
+		_, err := resolver.Resolve(t.Context(), token)
+		require.Error(t, err)
+	})
+
+	t.Run("token signed by another key is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		token := signClaimsToken(t, newECDSAKey(t), map[string]any{
+			"iss":     issuer,
+			"profile": map[string]any{"email": "euang@acmecorp.com"},
+		})
+
+		_, err := resolver.Resolve(t.Context(), token)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown issuer is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		token := signClaimsToken(t, priv, map[string]any{
+			"iss":     "https://issuer.example/other",
+			"profile": map[string]any{"email": "euang@acmecorp.com"},
+		})
+
+		_, err := resolver.Resolve(t.Context(), token)
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	token := signClaimsToken(t, newECDSAKey(t), map[string]any{"iss": "https://issuer.example"})
+
+	require.NoError(t, verifyAlgorithm(token, nil))
+	require.NoError(t, verifyAlgorithm(token, []string{"RS256", "ES256"}))
+	require.Error(t, verifyAlgorithm(token, []string{"RS256"}))
+}
@@ -0,0 +1,113 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	"github.com/aserto-dev/topaz/internal/metrics"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	outcomeAllowed = "allowed"
+	outcomeDenied  = "denied"
+	outcomeError   = "error"
+
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+// identity resolution failure reasons.
+const (
+	reasonIdentityContextMissing = "identity_context_missing"
+	reasonIdentityInvalid        = "identity_invalid"
+	reasonTokenInvalid           = "token_invalid"
+	reasonUserNotFound           = "user_not_found"
+	reasonDirectoryUnavailable   = "directory_unavailable"
+	reasonTimeout                = "timeout"
+)
+
+var (
+	isDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_is_decisions_total",
+			Help: "Number of Is decisions, by policy path, decision and outcome (allowed, denied, error).",
+		},
+		[]string{"path", "decision", "outcome"},
+	)
+
+	isEvaluationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "topaz_is_evaluation_duration_seconds",
+			Help:    "Duration of the policy evaluation of the Is requests, by policy path.",
+			Buckets: metrics.LatencyBuckets,
+		},
+		[]string{"path"},
+	)
+
+	preparedQueryCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_prepared_query_cache_requests_total",
+			Help: "Number of prepared query cache lookups, by result (hit, miss).",
+		},
+		[]string{"result"},
+	)
+
+	identityResolutionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_identity_resolution_failures_total",
+			Help: "Number of failed identity context resolutions, by identity type and reason.",
+		},
+		[]string{"type", "reason"},
+	)
+)
+
+// AuthorizerCollectors, returns the authorization decision metrics collectors.
+func AuthorizerCollectors() []prometheus.Collector {
+	return []prometheus.Collector{isDecisions, isEvaluationDuration, preparedQueryCacheRequests, identityResolutionFailures}
+}
+
+// observeIsDecisions, counts the decisions of an Is request, all decisions are counted as errors when the evaluation failed.
+func observeIsDecisions(path string, requested []string, decisions []*authorizer.Decision, err error) {
+	path = metrics.PolicyPath(path)
+
+	if err != nil {
+		for _, d := range requested {
+			isDecisions.WithLabelValues(path, metrics.Decision(d), outcomeError).Inc()
+		}
+
+		return
+	}
+
+	for _, d := range decisions {
+		outcome := outcomeDenied
+		if d.GetIs() {
+			outcome = outcomeAllowed
+		}
+
+		isDecisions.WithLabelValues(path, metrics.Decision(d.GetDecision()), outcome).Inc()
+	}
+}
+
+// observeIsEvaluation, records the duration of the policy evaluation of an Is request.
+func observeIsEvaluation(path string, start time.Time) {
+	isEvaluationDuration.WithLabelValues(metrics.PolicyPath(path)).Observe(time.Since(start).Seconds())
+}
+
+// identityFailureReason, returns the failure reason of an identity resolution step, a timeout or an
+// unavailable directory take precedence over the reason of the step.
+func identityFailureReason(ctx context.Context, err error, reason string) string {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return reasonTimeout
+	case status.Code(err) == codes.Unavailable:
+		return reasonDirectoryUnavailable
+	default:
+		return reason
+	}
+}
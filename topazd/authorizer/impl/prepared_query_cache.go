@@ -77,9 +77,12 @@ func (c *preparedQueryCache) getOrPrepare(
 	c.ensureCompilerWatcher(rt)
 
 	if v, ok := c.entries.Load(key); ok {
+		preparedQueryCacheRequests.WithLabelValues(cacheHit).Inc()
 		return *v, nil
 	}
 
+	preparedQueryCacheRequests.WithLabelValues(cacheMiss).Inc()
+
 	// Collapse concurrent misses on the same key.
 	v, err, _ := c.prepGroup.Do(key, func() (*rego.PreparedEvalQuery, error) {
 		// Re-check under the singleflight: a concurrent call may have
@@ -0,0 +1,223 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aserto-dev/go-authorizer/aserto/authorizer/v2"
+	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/internal/metrics"
+	"github.com/aserto-dev/topaz/pkg/config"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	shadowMatch   = "match"
+	shadowDiverge = "diverge"
+	shadowError   = "error"
+)
+
+var (
+	shadowEvaluations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "topaz_shadow_evaluations_total",
+			Help: "Number of shadow policy decisions, by policy path, decision and result (match, diverge, error).",
+		},
+		[]string{"path", "decision", "result"},
+	)
+
+	shadowDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "topaz_shadow_dropped_total",
+			Help: "Number of sampled requests not evaluated by the shadow policy, because the maximum of pending evaluations was reached.",
+		},
+	)
+)
+
+// shadowDivergence, divergence log record of a shadow evaluation, which decisions differ from the primary decisions.
+type shadowDivergence struct {
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Path      string          `json:"path"`
+	Identity  string          `json:"identity,omitempty"`
+	UserID    string          `json:"user_id,omitempty"`
+	Resource  map[string]any  `json:"resource,omitempty"`
+	Primary   map[string]bool `json:"primary"`
+	Shadow    map[string]bool `json:"shadow,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// shadowEvaluator, evaluates the shadow policy asynchronously, for a sampled fraction of the Is requests
+// served by the shadowed policy instance. The evaluation never blocks the primary request, a sampled request
+// is dropped when the maximum of pending evaluations is reached.
+type shadowEvaluator struct {
+	logger   *zerolog.Logger
+	cfg      *config.ShadowPolicy
+	inflight chan struct{}
+	timeout  time.Duration
+	file     *lumberjack.Logger
+}
+
+// newShadowEvaluator, returns the shadow evaluator, nil when no shadow policy is configured, or nothing is sampled.
+func newShadowEvaluator(logger *zerolog.Logger, cfg *config.ShadowPolicy) *shadowEvaluator {
+	if cfg == nil || cfg.SampleRate <= 0 {
+		return nil
+	}
+
+	logCfg := cfg.LogConfig()
+
+	file := &lumberjack.Logger{
+		Filename:   logCfg.Filename,
+		MaxSize:    logCfg.MaxSize,
+		MaxAge:     logCfg.MaxAge,
+		MaxBackups: logCfg.MaxBackups,
+	}
+
+	return &shadowEvaluator{
+		logger:   logger,
+		cfg:      cfg,
+		inflight: make(chan struct{}, cfg.Inflight()),
+		timeout:  cfg.TimeoutDuration(),
+		file:     file,
+	}
+}
+
+// ShadowCollectors, returns the shadow evaluation metrics collectors.
+func ShadowCollectors() []prometheus.Collector {
+	return []prometheus.Collector{shadowEvaluations, shadowDropped}
+}
+
+func (e *shadowEvaluator) Close() {
+	if e == nil {
+		return
+	}
+
+	_ = e.file.Close()
+}
+
+// shadowIs, schedules the shadow evaluation of the Is request input, when the request is
+// served by the shadowed policy instance and is sampled.
+func (s *AuthorizerServer) shadowIs(
+	ctx context.Context,
+	rt *runtime.Runtime,
+	req *authorizer.IsRequest,
+	input map[string]any,
+	decisions []*authorizer.Decision,
+	strict bool,
+) {
+	e := s.shadow
+	if e == nil {
+		return
+	}
+
+	shadowRT := s.resolver.GetRuntimeResolver().Shadow()
+	if shadowRT == nil {
+		return
+	}
+
+	if shadowed, err := s.resolver.GetRuntimeResolver().GetRuntime(ctx, e.cfg.Policy, ""); err != nil || shadowed != rt {
+		return
+	}
+
+	if rand.Float64() >= e.cfg.SampleRate { //nolint:gosec // sampling does not require a secure random source.
+		return
+	}
+
+	select {
+	case e.inflight <- struct{}{}:
+	default:
+		shadowDropped.Inc()
+		return
+	}
+
+	// the shadow evaluation outlives the request, it keeps the request values, but not its cancellation.
+	shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.timeout)
+
+	go func() {
+		defer func() { <-e.inflight }()
+		defer cancel()
+
+		s.evalShadow(shadowCtx, shadowRT, req, input, getOutcomes(decisions), strict)
+	}()
+}
+
+func (s *AuthorizerServer) evalShadow(
+	ctx context.Context,
+	rt *runtime.Runtime,
+	req *authorizer.IsRequest,
+	input map[string]any,
+	primary map[string]bool,
+	strict bool,
+) {
+	pc := req.GetPolicyContext()
+
+	divergence := &shadowDivergence{
+		ID:        uuid.NewString(),
+		Timestamp: time.Now().UTC(),
+		Path:      pc.GetPath(),
+		Identity:  req.GetIdentityContext().GetIdentity(),
+		UserID:    getID(input),
+		Primary:   primary,
+	}
+
+	if resource, ok := input[InputResource].(*structpb.Struct); ok {
+		divergence.Resource = resource.AsMap()
+	}
+
+	var decisions []*authorizer.Decision
+
+	query, err := s.prepareIsQuery(ctx, rt, pc)
+	if err == nil {
+		decisions, _, err = evalIsQuery(ctx, query, input, pc, strict)
+	}
+
+	path := metrics.PolicyPath(pc.GetPath())
+
+	if err != nil {
+		for _, d := range pc.GetDecisions() {
+			shadowEvaluations.WithLabelValues(path, metrics.Decision(d), shadowError).Inc()
+		}
+
+		divergence.Error = err.Error()
+		s.shadow.log(divergence)
+
+		return
+	}
+
+	divergence.Shadow = getOutcomes(decisions)
+	diverged := false
+
+	for _, d := range pc.GetDecisions() {
+		result := shadowMatch
+		if divergence.Shadow[d] != primary[d] {
+			result = shadowDiverge
+			diverged = true
+		}
+
+		shadowEvaluations.WithLabelValues(path, metrics.Decision(d), result).Inc()
+	}
+
+	if diverged {
+		s.shadow.log(divergence)
+	}
+}
+
+func (e *shadowEvaluator) log(d *shadowDivergence) {
+	buf, err := json.Marshal(d)
+	if err != nil {
+		e.logger.Warn().Err(err).Msg("failed to marshal shadow divergence")
+		return
+	}
+
+	// the divergence log contains a JSON document per line, lumberjack serializes concurrent writes.
+	if _, err := e.file.Write(append(buf, '\n')); err != nil {
+		e.logger.Warn().Err(err).Msg("failed to write shadow divergence")
+	}
+}
@@ -3,12 +3,14 @@ package edge
 import (
 	"context"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	client "github.com/aserto-dev/go-aserto"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/datasync"
 	"github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	topaz "github.com/aserto-dev/topaz/pkg/config"
 	"github.com/aserto-dev/topaz/topazd/app"
 	"google.golang.org/grpc"
@@ -63,11 +65,14 @@ type Config struct {
 }
 
 type Plugin struct {
+	// mu guards the configuration and the sync context, which are replaced by Reconfigure, while
+	// the scheduler and the sync tasks are running.
+	mu          sync.RWMutex
 	ctx         context.Context
 	cancel      context.CancelFunc
+	config      *Config
 	manager     *plugins.Manager
 	logger      *zerolog.Logger
-	config      *Config
 	topazConfig *topaz.Config
 	syncNow     chan SyncMode
 }
@@ -96,39 +101,49 @@ func newEdgePlugin(logger *zerolog.Logger, cfg *Config, topazConfig *topaz.Confi
 }
 
 func (p *Plugin) Start(ctx context.Context) error {
-	p.logger.Info().Str("id", p.manager.ID).Bool("enabled", p.config.Enabled).Int("interval", p.config.SyncInterval).Msg("EdgePlugin.Start")
+	cfg := p.cfg()
+	p.logger.Info().Str("id", p.manager.ID).Bool("enabled", cfg.Enabled).Int("interval", cfg.SyncInterval).Msg("EdgePlugin.Start")
 
 	p.manager.UpdatePluginStatus(PluginName, &plugins.Status{State: plugins.StateOK})
 
-	go p.scheduler()
+	go p.scheduler(p.syncContext())
 
 	return nil
 }
 
 func (p *Plugin) Stop(ctx context.Context) {
-	p.logger.Info().Str("id", p.manager.ID).Bool("enabled", p.config.Enabled).Int("interval", p.config.SyncInterval).Msg("EdgePlugin.Stop")
+	cfg := p.cfg()
+	p.logger.Info().Str("id", p.manager.ID).Bool("enabled", cfg.Enabled).Int("interval", cfg.SyncInterval).Msg("EdgePlugin.Stop")
 
+	p.mu.RLock()
 	p.cancel()
+	p.mu.RUnlock()
+
 	p.manager.UpdatePluginStatus(PluginName, &plugins.Status{State: plugins.StateNotReady})
 }
 
 func (p *Plugin) Reconfigure(ctx context.Context, config any) {
-	p.logger.Trace().Str("id", p.manager.ID).Interface("cur", p.config).Interface("new", config).Msg("EdgePlugin.Reconfigure")
-
 	newConfig, ok := config.(*Config)
 	if !ok {
 		p.logger.Error().Str("config", "failed type assertion").Msg("EdgePlugin.Reconfigure")
 		return
 	}
 
+	newConfig.ConnectionTimeout = time.Duration(newConfig.Timeout * int(time.Second))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logger.Trace().Str("id", p.manager.ID).Interface("cur", p.config).Interface("new", newConfig).Msg("EdgePlugin.Reconfigure")
+
 	// handle enabled status changed
 	if p.config.Enabled != newConfig.Enabled {
 		p.logger.Info().Str("id", p.manager.ID).Bool("old", p.config.Enabled).Bool("new", newConfig.Enabled).Msg("sync enabled changed")
 
 		if newConfig.Enabled {
-			p.resetContext()
+			p.ctx, p.cancel = context.WithCancel(context.Background())
 
-			go p.scheduler()
+			go p.scheduler(p.ctx)
 		} else {
 			// set health status to NOT_SERVING when plugin switches to disabled.
 			app.SetServiceStatus(p.logger, "sync", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
@@ -136,24 +151,33 @@ func (p *Plugin) Reconfigure(ctx context.Context, config any) {
 		}
 	}
 
-	p.config, ok = config.(*Config)
-	if !ok {
-		p.logger.Error().Str("config", "failed type assertion").Msg("EdgePlugin.Reconfigure")
-		return
-	}
+	// the sync interval of the new configuration applies from the next sync run.
+	p.config = newConfig
 }
 
 func (p *Plugin) SyncNow(mode SyncMode) {
 	p.syncNow <- mode
 }
 
-func (p *Plugin) resetContext() {
-	p.ctx, p.cancel = context.WithCancel(context.Background())
+// cfg, returns the current configuration, which is not modified once it is set.
+func (p *Plugin) cfg() *Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.config
+}
+
+// syncContext, returns the current sync context, which is canceled when the sync is disabled or stopped.
+func (p *Plugin) syncContext() context.Context {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.ctx
 }
 
 const cycles int64 = 4
 
-func (p *Plugin) scheduler() {
+func (p *Plugin) scheduler(ctx context.Context) {
 	// scheduler startup delay 1s
 	interval := time.NewTicker(1 * time.Second)
 	defer interval.Stop()
@@ -169,7 +193,7 @@ func (p *Plugin) scheduler() {
 
 	for {
 		select {
-		case <-p.ctx.Done():
+		case <-ctx.Done():
 			p.logger.Debug().Time("done", time.Now()).Msg(syncScheduler)
 			return
 
@@ -239,11 +263,11 @@ const secsInMin int64 = 60
 // based on the configuration SyncInterval (defined on the EdgeDirectory connection)
 // returning a time.Duration.
 //
-// p.config.SyncInterval 1m-60m
+// SyncInterval 1m-60m
 // 1m -> 60s -> 15s interval
 // 60m -> 3600s -> 900s interval.
 func (p *Plugin) calcInterval() time.Duration {
-	waitInSec := (int64(p.config.SyncInterval) * secsInMin) / cycles
+	waitInSec := (int64(p.cfg().SyncInterval) * secsInMin) / cycles
 	return time.Duration(waitInSec) * time.Second
 }
 
@@ -327,7 +351,7 @@ func (p *Plugin) exec(ctx context.Context, ds *directory.Directory, conn *grpc.C
 		p.logger.Error().Err(err).Msg(syncTask)
 	}
 
-	if p.config.Enabled && err == nil {
+	if p.cfg().Enabled && err == nil {
 		app.SetServiceStatus(p.logger, "sync", grpc_health_v1.HealthCheckResponse_SERVING)
 	}
 
@@ -335,28 +359,30 @@ func (p *Plugin) exec(ctx context.Context, ds *directory.Directory, conn *grpc.C
 }
 
 func (p *Plugin) remoteDirectoryClient() (*grpc.ClientConn, error) {
+	pc := p.cfg()
+
 	cfg := &client.Config{
-		Address:        p.config.Addr,           //
-		APIKey:         p.config.APIKey,         //
-		ClientCertPath: p.config.ClientCertPath, //
-		ClientKeyPath:  p.config.ClientKeyPath,  //
-		CACertPath:     p.config.CACertPath,     //
-		Insecure:       p.config.Insecure,       //
-		NoTLS:          p.config.NoTLS,          //
-		NoProxy:        p.config.NoProxy,        //
-		Headers:        p.config.Headers,        //
+		Address:        pc.Addr,           //
+		APIKey:         pc.APIKey,         //
+		ClientCertPath: pc.ClientCertPath, //
+		ClientKeyPath:  pc.ClientKeyPath,  //
+		CACertPath:     pc.CACertPath,     //
+		Insecure:       pc.Insecure,       //
+		NoTLS:          pc.NoTLS,          //
+		NoProxy:        pc.NoProxy,        //
+		Headers:        pc.Headers,        //
 	}
 
-	conn, err := cfg.Connect()
+	conn, err := cfg.Connect(client.WithDialOptions(telemetry.DialOptions()...))
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(p.ctx, p.config.ConnectionTimeout)
+	ctx, cancel := context.WithTimeout(p.syncContext(), pc.ConnectionTimeout)
 	defer cancel()
 
 	if !conn.WaitForStateChange(ctx, connectivity.Ready) {
-		return nil, errors.Errorf("failed to connect to remote directory %s", p.config.Addr)
+		return nil, errors.Errorf("failed to connect to remote directory %s", pc.Addr)
 	}
 
 	return conn, nil
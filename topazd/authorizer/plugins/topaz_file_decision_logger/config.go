@@ -10,10 +10,23 @@ const (
 	defaultCompress               bool   = false // default is not to perform compression.
 )
 
+// AnnotationInput, decision annotation containing the JSON encoded evaluated input, when capture_input is enabled.
+const AnnotationInput string = "input"
+
+// AnnotationAPIKey, decision annotation containing the name of the API key which authenticated the request.
+const AnnotationAPIKey string = "api_key"
+
+// AnnotationClientIdentity, decision annotation containing the identity of the client certificate which authenticated the request.
+const AnnotationClientIdentity string = "client_identity"
+
+// AnnotationTokenSubject, decision annotation containing the subject of the bearer token which authenticated the request.
+const AnnotationTokenSubject string = "token_subject"
+
 type Config struct {
-	Enabled    bool       `json:"enabled"`
-	Logger     Logger     `json:"logger"`
-	PolicyInfo PolicyInfo `json:"policy_info"`
+	Enabled      bool       `json:"enabled"`
+	CaptureInput bool       `json:"capture_input"` // record the evaluated input, required to replay decisions.
+	Logger       Logger     `json:"logger"`
+	PolicyInfo   PolicyInfo `json:"policy_info"`
 }
 
 type Logger struct {
@@ -35,7 +48,8 @@ type PolicyInfo struct {
 
 func defaultConfig() *Config {
 	return &Config{
-		Enabled: false,
+		Enabled:      false,
+		CaptureInput: false,
 		Logger: Logger{
 			Filename:   defaultFilename,
 			MaxSize:    defaultMaxSize,
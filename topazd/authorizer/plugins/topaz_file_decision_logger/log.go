@@ -11,6 +11,9 @@ import (
 )
 
 func (plugin *Plugin) LogDecision(ctx context.Context, d *api.Decision) error {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+
 	if !plugin.config.Enabled || plugin.fileLogger == nil {
 		return nil
 	}
@@ -30,7 +33,18 @@ func (plugin *Plugin) LogDecision(ctx context.Context, d *api.Decision) error {
 	return nil
 }
 
+// CaptureInput, returns true when the evaluated input is recorded with the decision.
+func (plugin *Plugin) CaptureInput() bool {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+
+	return plugin.config.Enabled && plugin.config.CaptureInput
+}
+
 func (plugin *Plugin) Log(ctx context.Context, event logs.EventV1) error {
+	plugin.mu.RLock()
+	defer plugin.mu.RUnlock()
+
 	if !plugin.config.Enabled || plugin.fileLogger == nil {
 		return nil
 	}
@@ -3,6 +3,7 @@ package topaz_file_decision_logger
 import (
 	"context"
 	"os"
+	"sync"
 
 	"github.com/open-policy-agent/opa/v1/plugins"
 	"github.com/rs/zerolog"
@@ -15,6 +16,8 @@ const (
 )
 
 type Plugin struct {
+	// mu guards the configuration and the file logger, which are replaced by Reconfigure.
+	mu         sync.RWMutex
 	manager    *plugins.Manager
 	config     *Config
 	logger     *zerolog.Logger
@@ -31,21 +34,15 @@ func (p *Plugin) Start(ctx context.Context) error {
 		p.logger.Info().Bool("enabled", p.config.Enabled).Str("file", p.config.Logger.Filename).Msg("running in container")
 	}
 
-	p.fileLogger = &lumberjack.Logger{
-		Filename:   p.config.Logger.Filename,
-		MaxSize:    p.config.Logger.MaxSize,
-		MaxBackups: p.config.Logger.MaxBackups,
-		MaxAge:     p.config.Logger.MaxAge,
-		LocalTime:  p.config.Logger.LocalTime,
-		Compress:   p.config.Logger.Compress,
-	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// verify ability to write from the fileLogger, before handing it to zerolog.
-	if _, err := p.fileLogger.Write([]byte{}); err != nil {
-		p.logger.Error().Bool("enabled", p.config.Enabled).Str("file", p.fileLogger.Filename).Err(err).Msg("file-logger write failed")
+	fileLogger, err := p.openFileLogger(&p.config.Logger)
+	if err != nil {
 		return err
 	}
 
+	p.fileLogger = fileLogger
 	p.dlogger = zerolog.New(p.fileLogger)
 
 	p.manager.UpdatePluginStatus(PluginName, &plugins.Status{State: plugins.StateOK})
@@ -56,6 +53,9 @@ func (p *Plugin) Start(ctx context.Context) error {
 }
 
 func (p *Plugin) Stop(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	p.logger.Info().Bool("enabled", p.config.Enabled).Str("file", p.fileLogger.Filename).Msg("stop")
 
 	if p.fileLogger != nil {
@@ -69,10 +69,58 @@ func (p *Plugin) Stop(ctx context.Context) {
 	p.logger.Info().Bool("enabled", p.config.Enabled).Msg("stopped")
 }
 
+// Reconfigure, replaces the configuration of the plugin, the decision log file is reopened when its
+// settings changed, the current file is kept when the new file cannot be written.
 func (p *Plugin) Reconfigure(ctx context.Context, c any) {
-	if cfg, ok := c.(*Config); ok {
-		p.config = cfg
+	var cfg Config
+
+	switch v := c.(type) {
+	case Config:
+		cfg = v
+	case *Config:
+		cfg = *v
+	default:
+		p.logger.Error().Str("config", "failed type assertion").Msg("reconfigure")
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fileLogger != nil && cfg.Logger != p.config.Logger {
+		fileLogger, err := p.openFileLogger(&cfg.Logger)
+		if err != nil {
+			cfg.Logger = p.config.Logger
+		} else {
+			_ = p.fileLogger.Close()
+			p.fileLogger = fileLogger
+			p.dlogger = zerolog.New(p.fileLogger)
+		}
+	}
+
+	p.config = &cfg
+
+	p.logger.Info().Bool("enabled", p.config.Enabled).Str("file", p.config.Logger.Filename).Msg("reconfigured")
+}
+
+// openFileLogger, returns the rotating file logger of the decision log, the file must be writable.
+func (p *Plugin) openFileLogger(cfg *Logger) (*lumberjack.Logger, error) {
+	fileLogger := &lumberjack.Logger{
+		Filename:   cfg.Filename,
+		MaxSize:    cfg.MaxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAge,
+		LocalTime:  cfg.LocalTime,
+		Compress:   cfg.Compress,
 	}
+
+	// verify ability to write from the fileLogger, before handing it to zerolog.
+	if _, err := fileLogger.Write([]byte{}); err != nil {
+		p.logger.Error().Str("file", fileLogger.Filename).Err(err).Msg("file-logger write failed")
+		return nil, err
+	}
+
+	return fileLogger, nil
 }
 
 func Lookup(m *plugins.Manager) *Plugin {
@@ -0,0 +1,383 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: aserto/topaz/policy/v1/policy_status.proto
+
+package policywatch
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StatusRequest, selects the policy instance, all policy instances when empty.
+type StatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// policy instance name.
+	Instance      string `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_policy_v1_policy_status_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StatusRequest) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+// StatusResponse, the compile status of the watched policy directories.
+type StatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// status per watched policy directory.
+	Policies      []*InstanceStatus `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_policy_v1_policy_status_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusResponse) GetPolicies() []*InstanceStatus {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+// InstanceStatus, the compile status of the watched policy directory of a policy instance.
+type InstanceStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// policy instance name.
+	Instance string `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	// watched policy directory.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// compile state, ok or error.
+	State string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// revision of the loaded policy.
+	Revision string `protobuf:"bytes,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	// time the loaded revision was published.
+	LoadedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=loaded_at,json=loadedAt,proto3" json:"loaded_at,omitempty"`
+	// time of the last compile.
+	CheckedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	// errors of the last compile.
+	Errors        []*PolicyError `protobuf:"bytes,7,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceStatus) Reset() {
+	*x = InstanceStatus{}
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceStatus) ProtoMessage() {}
+
+func (x *InstanceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceStatus.ProtoReflect.Descriptor instead.
+func (*InstanceStatus) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_policy_v1_policy_status_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InstanceStatus) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *InstanceStatus) GetLoadedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LoadedAt
+	}
+	return nil
+}
+
+func (x *InstanceStatus) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *InstanceStatus) GetErrors() []*PolicyError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+// PolicyError, a compile error of the policy, with the location relative to the policy directory.
+type PolicyError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// file of the error.
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// row of the error.
+	Row int32 `protobuf:"varint,2,opt,name=row,proto3" json:"row,omitempty"`
+	// column of the error.
+	Col int32 `protobuf:"varint,3,opt,name=col,proto3" json:"col,omitempty"`
+	// error code.
+	Code string `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	// error message.
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyError) Reset() {
+	*x = PolicyError{}
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyError) ProtoMessage() {}
+
+func (x *PolicyError) ProtoReflect() protoreflect.Message {
+	mi := &file_aserto_topaz_policy_v1_policy_status_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyError.ProtoReflect.Descriptor instead.
+func (*PolicyError) Descriptor() ([]byte, []int) {
+	return file_aserto_topaz_policy_v1_policy_status_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PolicyError) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *PolicyError) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *PolicyError) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *PolicyError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PolicyError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_aserto_topaz_policy_v1_policy_status_proto protoreflect.FileDescriptor
+
+const file_aserto_topaz_policy_v1_policy_status_proto_rawDesc = "" +
+	"\n" +
+	"*aserto/topaz/policy/v1/policy_status.proto\x12\x16aserto.topaz.policy.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"+\n" +
+	"\rStatusRequest\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\"T\n" +
+	"\x0eStatusResponse\x12B\n" +
+	"\bpolicies\x18\x01 \x03(\v2&.aserto.topaz.policy.v1.InstanceStatusR\bpolicies\"\xa3\x02\n" +
+	"\x0eInstanceStatus\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\tR\binstance\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\tR\brevision\x127\n" +
+	"\tloaded_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bloadedAt\x129\n" +
+	"\n" +
+	"checked_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12;\n" +
+	"\x06errors\x18\a \x03(\v2#.aserto.topaz.policy.v1.PolicyErrorR\x06errors\"s\n" +
+	"\vPolicyError\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x10\n" +
+	"\x03row\x18\x02 \x01(\x05R\x03row\x12\x10\n" +
+	"\x03col\x18\x03 \x01(\x05R\x03col\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage2\x86\x01\n" +
+	"\fPolicyStatus\x12v\n" +
+	"\x06Status\x12%.aserto.topaz.policy.v1.StatusRequest\x1a&.aserto.topaz.policy.v1.StatusResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v2/policy/statusBGZEgithub.com/aserto-dev/topaz/topazd/authorizer/policywatch;policywatchb\x06proto3"
+
+var (
+	file_aserto_topaz_policy_v1_policy_status_proto_rawDescOnce sync.Once
+	file_aserto_topaz_policy_v1_policy_status_proto_rawDescData []byte
+)
+
+func file_aserto_topaz_policy_v1_policy_status_proto_rawDescGZIP() []byte {
+	file_aserto_topaz_policy_v1_policy_status_proto_rawDescOnce.Do(func() {
+		file_aserto_topaz_policy_v1_policy_status_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aserto_topaz_policy_v1_policy_status_proto_rawDesc), len(file_aserto_topaz_policy_v1_policy_status_proto_rawDesc)))
+	})
+	return file_aserto_topaz_policy_v1_policy_status_proto_rawDescData
+}
+
+var file_aserto_topaz_policy_v1_policy_status_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_aserto_topaz_policy_v1_policy_status_proto_goTypes = []any{
+	(*StatusRequest)(nil),         // 0: aserto.topaz.policy.v1.StatusRequest
+	(*StatusResponse)(nil),        // 1: aserto.topaz.policy.v1.StatusResponse
+	(*InstanceStatus)(nil),        // 2: aserto.topaz.policy.v1.InstanceStatus
+	(*PolicyError)(nil),           // 3: aserto.topaz.policy.v1.PolicyError
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_aserto_topaz_policy_v1_policy_status_proto_depIdxs = []int32{
+	2, // 0: aserto.topaz.policy.v1.StatusResponse.policies:type_name -> aserto.topaz.policy.v1.InstanceStatus
+	4, // 1: aserto.topaz.policy.v1.InstanceStatus.loaded_at:type_name -> google.protobuf.Timestamp
+	4, // 2: aserto.topaz.policy.v1.InstanceStatus.checked_at:type_name -> google.protobuf.Timestamp
+	3, // 3: aserto.topaz.policy.v1.InstanceStatus.errors:type_name -> aserto.topaz.policy.v1.PolicyError
+	0, // 4: aserto.topaz.policy.v1.PolicyStatus.Status:input_type -> aserto.topaz.policy.v1.StatusRequest
+	1, // 5: aserto.topaz.policy.v1.PolicyStatus.Status:output_type -> aserto.topaz.policy.v1.StatusResponse
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_aserto_topaz_policy_v1_policy_status_proto_init() }
+func file_aserto_topaz_policy_v1_policy_status_proto_init() {
+	if File_aserto_topaz_policy_v1_policy_status_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aserto_topaz_policy_v1_policy_status_proto_rawDesc), len(file_aserto_topaz_policy_v1_policy_status_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aserto_topaz_policy_v1_policy_status_proto_goTypes,
+		DependencyIndexes: file_aserto_topaz_policy_v1_policy_status_proto_depIdxs,
+		MessageInfos:      file_aserto_topaz_policy_v1_policy_status_proto_msgTypes,
+	}.Build()
+	File_aserto_topaz_policy_v1_policy_status_proto = out.File
+	file_aserto_topaz_policy_v1_policy_status_proto_goTypes = nil
+	file_aserto_topaz_policy_v1_policy_status_proto_depIdxs = nil
+}
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: aserto/topaz/policy/v1/policy_status.proto
+
+package policywatch
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PolicyStatus_Status_FullMethodName = "/aserto.topaz.policy.v1.PolicyStatus/Status"
+)
+
+// PolicyStatusClient is the client API for PolicyStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PolicyStatus, reports the compile status of the watched local policy directories.
+type PolicyStatusClient interface {
+	// Status, returns the compile status, the loaded revision and the last compile errors,
+	// of the watched local policy directories, optionally of a single policy instance.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+}
+
+type policyStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPolicyStatusClient(cc grpc.ClientConnInterface) PolicyStatusClient {
+	return &policyStatusClient{cc}
+}
+
+func (c *policyStatusClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, PolicyStatus_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PolicyStatusServer is the server API for PolicyStatus service.
+// All implementations should embed UnimplementedPolicyStatusServer
+// for forward compatibility.
+//
+// PolicyStatus, reports the compile status of the watched local policy directories.
+type PolicyStatusServer interface {
+	// Status, returns the compile status, the loaded revision and the last compile errors,
+	// of the watched local policy directories, optionally of a single policy instance.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+}
+
+// UnimplementedPolicyStatusServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPolicyStatusServer struct{}
+
+func (UnimplementedPolicyStatusServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedPolicyStatusServer) testEmbeddedByValue() {}
+
+// UnsafePolicyStatusServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PolicyStatusServer will
+// result in compilation errors.
+type UnsafePolicyStatusServer interface {
+	mustEmbedUnimplementedPolicyStatusServer()
+}
+
+func RegisterPolicyStatusServer(s grpc.ServiceRegistrar, srv PolicyStatusServer) {
+	// If the following call pancis, it indicates UnimplementedPolicyStatusServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PolicyStatus_ServiceDesc, srv)
+}
+
+func _PolicyStatus_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyStatusServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyStatus_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyStatusServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PolicyStatus_ServiceDesc is the grpc.ServiceDesc for PolicyStatus service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PolicyStatus_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "aserto.topaz.policy.v1.PolicyStatus",
+	HandlerType: (*PolicyStatusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _PolicyStatus_Status_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aserto/topaz/policy/v1/policy_status.proto",
+}
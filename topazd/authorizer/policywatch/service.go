@@ -0,0 +1,33 @@
+package policywatch
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Proto, returns the status message of the policy status service.
+func (s *Status) Proto() *InstanceStatus {
+	status := &InstanceStatus{
+		Instance:  s.Instance,
+		Path:      s.Path,
+		State:     string(s.State),
+		Revision:  s.Revision,
+		CheckedAt: timestamppb.New(s.CheckedAt),
+		Errors:    make([]*PolicyError, 0, len(s.Errors)),
+	}
+
+	if !s.LoadedAt.IsZero() {
+		status.LoadedAt = timestamppb.New(s.LoadedAt)
+	}
+
+	for _, e := range s.Errors {
+		status.Errors = append(status.Errors, &PolicyError{
+			File:    e.File,
+			Row:     int32(e.Row), //nolint:gosec // compile error locations are far below the int32 limit.
+			Col:     int32(e.Col), //nolint:gosec // compile error locations are far below the int32 limit.
+			Code:    e.Code,
+			Message: e.Message,
+		})
+	}
+
+	return status
+}
@@ -0,0 +1,579 @@
+// Package policywatch, supports the local policy development workflow, it watches a
+// local policy directory, compiles the policy on every change, and publishes the policy
+// to the staging directory loaded by the runtime only when it compiles.
+//
+// A failed compile leaves the staging directory, and therefore the policy loaded by the
+// runtime, unchanged, the compile errors are reported by the policy status.
+//
+// The policy is published as a complete version directory of the staging directory, and the
+// current link of the staging directory, the path loaded by the runtime, is flipped to it, so
+// the runtime loads either the previous or the new policy, never a partially published one.
+package policywatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type State string
+
+const (
+	StateOK    State = "ok"
+	StateError State = "error"
+)
+
+const (
+	// debounce, delay between the last change of the policy directory and the compile.
+	debounce = 250 * time.Millisecond
+
+	revisionLen = 12
+	fileMode    = 0o644
+	dirMode     = 0o755
+
+	// currentLink, the link of the staging directory to the current version directory.
+	currentLink = "current"
+	// versionPrefix, the name prefix of the version directories of the staging directory.
+	versionPrefix = "v-"
+)
+
+// CompileError, a compile error of the policy, with the location relative to the policy directory.
+type CompileError struct {
+	File    string `json:"file,omitempty"`
+	Row     int    `json:"row,omitempty"`
+	Col     int    `json:"col,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// Status, the compile status of a watched policy directory.
+type Status struct {
+	Instance  string          `json:"instance"`
+	Path      string          `json:"path"`
+	State     State           `json:"state"`
+	Revision  string          `json:"revision,omitempty"`
+	LoadedAt  time.Time       `json:"loaded_at,omitzero"`
+	CheckedAt time.Time       `json:"checked_at"`
+	Errors    []*CompileError `json:"errors,omitempty"`
+}
+
+// Watcher, compiles the policy of a source directory and publishes it to a staging directory.
+type Watcher struct {
+	logger   *zerolog.Logger
+	instance string
+	source   string
+	staging  string
+	ignore   []string
+	builtins map[string]*ast.Builtin
+
+	mu     sync.RWMutex
+	status Status
+
+	fsw *fsnotify.Watcher
+	wg  sync.WaitGroup
+}
+
+// New, returns a watcher of the source directory, publishing to the staging directory, the
+// builtins are the custom functions registered with the runtime of the policy instance.
+func New(logger *zerolog.Logger, instance, source, staging string, ignore []string, builtins []*rego.Function) *Watcher {
+	wLogger := logger.With().Str("component", "policywatch").Str("policy-instance", instance).Str("path", source).Logger()
+
+	decls := make(map[string]*ast.Builtin, len(builtins))
+	for _, fn := range builtins {
+		decls[fn.Name] = &ast.Builtin{Name: fn.Name, Decl: fn.Decl}
+	}
+
+	return &Watcher{
+		logger:   &wLogger,
+		instance: instance,
+		source:   source,
+		staging:  staging,
+		ignore:   ignore,
+		builtins: decls,
+		status:   Status{Instance: instance, Path: source},
+	}
+}
+
+// Staging, returns the current link of the staging directory, the path to be loaded by the runtime.
+func (w *Watcher) Staging() string {
+	return filepath.Join(w.staging, currentLink)
+}
+
+// Status, returns a copy of the current compile status.
+func (w *Watcher) Status() *Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.statusCopy()
+}
+
+// Sync, compiles the policy of the source directory, and publishes it to the staging
+// directory when it compiles, returns the resulting status.
+func (w *Watcher) Sync() *Status {
+	now := time.Now().UTC()
+
+	revision, compileErrs, err := Compile(w.source, w.ignore, w.builtins)
+
+	switch {
+	case err == nil && len(compileErrs) == 0:
+		err = publish(w.source, w.staging, w.filter())
+	case err == nil:
+		// the runtime starts without the policy, when the policy fails to compile at start.
+		err = ensureCurrent(w.staging)
+	}
+
+	if err != nil {
+		compileErrs = append(compileErrs, &CompileError{Message: err.Error()})
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.CheckedAt = now
+	w.status.Errors = compileErrs
+
+	if len(compileErrs) > 0 {
+		w.status.State = StateError
+
+		w.logger.Error().Int("errors", len(compileErrs)).Str("revision", w.status.Revision).
+			Msg("policy compile failed, keeping last good policy")
+
+		for _, e := range compileErrs {
+			w.logger.Error().Str("file", e.File).Int("row", e.Row).Int("col", e.Col).Msg(e.Message)
+		}
+
+		return w.statusCopy()
+	}
+
+	w.status.State = StateOK
+
+	if revision != w.status.Revision {
+		w.status.Revision = revision
+		w.status.LoadedAt = now
+
+		w.logger.Info().Str("revision", revision).Msg("policy published")
+	}
+
+	return w.statusCopy()
+}
+
+// statusCopy, returns a copy of the status, the caller must hold the lock.
+func (w *Watcher) statusCopy() *Status {
+	status := w.status
+	status.Errors = slices.Clone(w.status.Errors)
+
+	return &status
+}
+
+// Start, watches the source directory, and syncs the policy after every change, until
+// the context is done or the watcher is closed.
+func (w *Watcher) Start(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "failed to create policy directory watcher")
+	}
+
+	if err := addDirs(fsw, w.source); err != nil {
+		_ = fsw.Close()
+		return err
+	}
+
+	w.fsw = fsw
+
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+		w.watch(ctx, fsw)
+	}()
+
+	return nil
+}
+
+func (w *Watcher) watch(ctx context.Context, fsw *fsnotify.Watcher) {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+
+			if event.Has(fsnotify.Create) {
+				if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+					if err := addDirs(fsw, event.Name); err != nil {
+						w.logger.Warn().Err(err).Str("dir", event.Name).Msg("failed to watch directory")
+					}
+				}
+			}
+
+			timer.Reset(debounce)
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+
+			w.logger.Warn().Err(err).Msg("policy directory watcher error")
+
+		case <-timer.C:
+			w.Sync()
+		}
+	}
+}
+
+// Close, stops watching the source directory and removes the staging directory.
+func (w *Watcher) Close() {
+	if w.fsw != nil {
+		_ = w.fsw.Close()
+		w.wg.Wait()
+	}
+
+	if err := os.RemoveAll(w.staging); err != nil {
+		w.logger.Warn().Err(err).Str("staging", w.staging).Msg("failed to remove staging directory")
+	}
+}
+
+func (w *Watcher) filter() loader.Filter {
+	return ignoreFilter(w.ignore)
+}
+
+// Compile, loads the policy directory as a bundle and compiles its modules, returns the
+// revision of the bundle, the manifest revision or a content hash, and the compile errors.
+// The error is only set when the policy directory cannot be read.
+func Compile(dir string, ignore []string, builtins map[string]*ast.Builtin) (string, []*CompileError, error) {
+	if fi, err := os.Stat(dir); err != nil {
+		return "", nil, err
+	} else if !fi.IsDir() {
+		return "", nil, errors.Errorf("%s is not a directory", dir)
+	}
+
+	b, err := loader.NewFileLoader().
+		WithRegoVersion(ast.RegoV0).
+		WithSkipBundleVerification(true).
+		WithFilter(ignoreFilter(ignore)).
+		AsBundle(dir)
+	if err != nil {
+		return "", compileErrors(dir, err), nil
+	}
+
+	modules := make(map[string]*ast.Module, len(b.Modules))
+	for _, m := range b.Modules {
+		modules[m.Path] = m.Parsed
+	}
+
+	compiler := ast.NewCompiler().WithBuiltins(builtins)
+	if compiler.Compile(modules); compiler.Failed() {
+		return "", compileErrors(dir, compiler.Errors), nil
+	}
+
+	return revision(b), nil, nil
+}
+
+// revision, returns the manifest revision of the bundle, or the hash of its content.
+func revision(b *bundle.Bundle) string {
+	if b.Manifest.Revision != "" {
+		return b.Manifest.Revision
+	}
+
+	modules := slices.Clone(b.Modules)
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Path < modules[j].Path })
+
+	h := sha256.New()
+
+	for _, m := range modules {
+		h.Write([]byte(m.Path))
+		h.Write(m.Raw)
+	}
+
+	if data, err := json.Marshal(b.Data); err == nil {
+		h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:revisionLen]
+}
+
+// compileErrors, flattens the loader and compiler errors into compile errors.
+func compileErrors(dir string, err error) []*CompileError {
+	var result []*CompileError
+
+	var walk func(err error)
+
+	walk = func(err error) {
+		var (
+			loaderErrs loader.Errors
+			astErrs    ast.Errors
+			astErr     *ast.Error
+		)
+
+		switch {
+		case errors.As(err, &loaderErrs):
+			for _, e := range loaderErrs {
+				walk(e)
+			}
+		case errors.As(err, &astErrs):
+			for _, e := range astErrs {
+				walk(e)
+			}
+		case errors.As(err, &astErr):
+			ce := &CompileError{Code: astErr.Code, Message: astErr.Message}
+
+			if loc := astErr.Location; loc != nil {
+				ce.File, ce.Row, ce.Col = relPath(dir, loc.File), loc.Row, loc.Col
+			}
+
+			result = append(result, ce)
+		default:
+			result = append(result, &CompileError{Message: err.Error()})
+		}
+	}
+
+	walk(err)
+
+	return result
+}
+
+func relPath(dir, file string) string {
+	if rel, err := filepath.Rel(dir, file); err == nil && filepath.IsLocal(rel) {
+		return filepath.ToSlash(rel)
+	}
+
+	return file
+}
+
+// ignoreFilter, returns the loader filter of the ignore patterns of the local bundles configuration.
+func ignoreFilter(ignore []string) loader.Filter {
+	return func(abspath string, info fs.FileInfo, depth int) bool {
+		for _, pattern := range ignore {
+			if loader.GlobExcludeName(pattern, 1)(abspath, info, depth) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// publish, copies the files of the source directory to a new version directory of the staging
+// directory, and flips the current link to it, the previous version directories are removed. The
+// policy is not published when the files of the current version are unchanged.
+func publish(source, staging string, filter loader.Filter) error {
+	files, err := sourceFiles(source, filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to publish policy")
+	}
+
+	link := filepath.Join(staging, currentLink)
+	if unchanged(link, files) {
+		return nil
+	}
+
+	version, err := os.MkdirTemp(staging, versionPrefix)
+	if err != nil {
+		return errors.Wrap(err, "failed to publish policy")
+	}
+
+	for rel, path := range files {
+		if err := copyFile(path, filepath.Join(version, rel)); err != nil {
+			_ = os.RemoveAll(version)
+			return errors.Wrap(err, "failed to publish policy")
+		}
+	}
+
+	if err := flip(link, version); err != nil {
+		_ = os.RemoveAll(version)
+		return errors.Wrap(err, "failed to publish policy")
+	}
+
+	return removeVersions(staging, version)
+}
+
+// ensureCurrent, publishes an empty version when the staging directory has no current version.
+func ensureCurrent(staging string) error {
+	link := filepath.Join(staging, currentLink)
+	if _, err := os.Lstat(link); err == nil {
+		return nil
+	}
+
+	version, err := os.MkdirTemp(staging, versionPrefix)
+	if err != nil {
+		return errors.Wrap(err, "failed to create policy staging directory")
+	}
+
+	return flip(link, version)
+}
+
+// sourceFiles, returns the regular files of the source directory, which are not ignored by the
+// filter, by their path relative to the source directory.
+func sourceFiles(source string, filter loader.Filter) (map[string]string, error) {
+	files := map[string]string{}
+
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if filter(path, info, strings.Count(rel, string(filepath.Separator))+1) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() || !info.Mode().IsRegular() {
+			return nil
+		}
+
+		files[rel] = path
+
+		return nil
+	})
+
+	return files, err
+}
+
+// unchanged, returns true when the version of the current link holds exactly the files, with the same content.
+func unchanged(link string, files map[string]string) bool {
+	count := 0
+
+	err := filepath.WalkDir(link+string(filepath.Separator), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(link, path)
+		if err != nil {
+			return err
+		}
+
+		src, ok := files[rel]
+		if !ok {
+			return fs.ErrNotExist
+		}
+
+		if !sameContent(src, path) {
+			return fs.ErrInvalid
+		}
+
+		count++
+
+		return nil
+	})
+
+	return err == nil && count == len(files)
+}
+
+func sameContent(a, b string) bool {
+	bufA, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+
+	bufB, err := os.ReadFile(b)
+	if err != nil {
+		return false
+	}
+
+	return slices.Equal(bufA, bufB)
+}
+
+func copyFile(src, dst string) error {
+	buf, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), dirMode); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, buf, fileMode)
+}
+
+// flip, atomically points the link to the version directory, by renaming a new link over the link.
+func flip(link, version string) error {
+	tmp := link + ".tmp"
+
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Symlink(filepath.Base(version), tmp); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, link)
+}
+
+// removeVersions, removes the version directories of the staging directory, except the current version.
+func removeVersions(staging, current string) error {
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove previous policy versions")
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), versionPrefix) || entry.Name() == filepath.Base(current) {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(staging, entry.Name())); err != nil {
+			return errors.Wrap(err, "failed to remove previous policy versions")
+		}
+	}
+
+	return nil
+}
+
+func addDirs(fsw *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return fsw.Add(path)
+		}
+
+		return nil
+	})
+}
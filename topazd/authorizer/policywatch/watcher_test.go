@@ -0,0 +1,186 @@
+package policywatch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins"
+	"github.com/aserto-dev/topaz/topazd/authorizer/builtins/ds"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const policy = `package policy.documents
+
+default allowed = false
+
+allowed {
+	obj := ds.object({"object_type": "document", "object_id": input.resource.id})
+	obj.properties.public
+}
+`
+
+const brokenPolicy = `package policy.documents
+
+default allowed = false
+
+allowed {
+	input.resource.id ==
+}
+`
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func newWatcher(t *testing.T) (*policywatch.Watcher, string) {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	source, staging := t.TempDir(), t.TempDir()
+
+	fn, _ := ds.RegisterObject(&logger, builtins.DSObject, nil)
+
+	return policywatch.New(&logger, "default", source, staging, []string{"*.md"}, []*rego.Function{fn}), source
+}
+
+func TestSyncPublishesPolicy(t *testing.T) {
+	w, source := newWatcher(t)
+
+	writeFile(t, source, "policies/documents.rego", policy)
+	writeFile(t, source, "README.md", "ignored")
+
+	status := w.Sync()
+	require.Equal(t, policywatch.StateOK, status.State, status.Errors)
+	assert.NotEmpty(t, status.Revision)
+	assert.False(t, status.LoadedAt.IsZero())
+
+	buf, err := os.ReadFile(filepath.Join(w.Staging(), "policies", "documents.rego"))
+	require.NoError(t, err)
+	assert.Equal(t, policy, string(buf))
+
+	assert.NoFileExists(t, filepath.Join(w.Staging(), "README.md"))
+}
+
+func TestSyncKeepsLastGoodPolicy(t *testing.T) {
+	w, source := newWatcher(t)
+
+	writeFile(t, source, "policies/documents.rego", policy)
+
+	good := w.Sync()
+	require.Equal(t, policywatch.StateOK, good.State, good.Errors)
+
+	writeFile(t, source, "policies/documents.rego", brokenPolicy)
+
+	status := w.Sync()
+	require.Equal(t, policywatch.StateError, status.State)
+	assert.Equal(t, good.Revision, status.Revision)
+	assert.Equal(t, good.LoadedAt, status.LoadedAt)
+
+	require.NotEmpty(t, status.Errors)
+	assert.Equal(t, "policies/documents.rego", status.Errors[0].File)
+	assert.Equal(t, 7, status.Errors[0].Row)
+
+	buf, err := os.ReadFile(filepath.Join(w.Staging(), "policies", "documents.rego"))
+	require.NoError(t, err)
+	assert.Equal(t, policy, string(buf))
+
+	writeFile(t, source, "policies/documents.rego", policy)
+
+	status = w.Sync()
+	require.Equal(t, policywatch.StateOK, status.State)
+	assert.Empty(t, status.Errors)
+	assert.Equal(t, good.Revision, status.Revision)
+}
+
+func TestSyncReportsCompileErrors(t *testing.T) {
+	w, source := newWatcher(t)
+
+	writeFile(t, source, "documents.rego", "package policy.documents\n\nallowed {\n\tds.unknown(input.resource)\n}\n")
+
+	status := w.Sync()
+	require.Equal(t, policywatch.StateError, status.State)
+	require.Len(t, status.Errors, 1)
+	assert.Equal(t, "documents.rego", status.Errors[0].File)
+	assert.Equal(t, 4, status.Errors[0].Row)
+	assert.Empty(t, status.Revision)
+}
+
+func TestSyncRemovesStaleFiles(t *testing.T) {
+	w, source := newWatcher(t)
+
+	writeFile(t, source, "policies/documents.rego", policy)
+	writeFile(t, source, "extra/data.json", `{"tenants": ["acme"]}`)
+
+	first := w.Sync()
+	require.Equal(t, policywatch.StateOK, first.State, first.Errors)
+	assert.FileExists(t, filepath.Join(w.Staging(), "extra", "data.json"))
+
+	require.NoError(t, os.RemoveAll(filepath.Join(source, "extra")))
+
+	second := w.Sync()
+	require.Equal(t, policywatch.StateOK, second.State, second.Errors)
+	assert.NotEqual(t, first.Revision, second.Revision)
+	assert.NoDirExists(t, filepath.Join(w.Staging(), "extra"))
+}
+
+func TestSyncFlipsCurrentVersion(t *testing.T) {
+	w, source := newWatcher(t)
+
+	writeFile(t, source, "policies/documents.rego", brokenPolicy)
+
+	status := w.Sync()
+	require.Equal(t, policywatch.StateError, status.State)
+
+	fi, err := os.Stat(w.Staging())
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+
+	writeFile(t, source, "policies/documents.rego", policy)
+
+	status = w.Sync()
+	require.Equal(t, policywatch.StateOK, status.State, status.Errors)
+
+	first, err := os.Readlink(w.Staging())
+	require.NoError(t, err)
+
+	writeFile(t, source, "extra/data.json", `{"tenants": ["acme"]}`)
+
+	status = w.Sync()
+	require.Equal(t, policywatch.StateOK, status.State, status.Errors)
+
+	second, err := os.Readlink(w.Staging())
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	staging := filepath.Dir(w.Staging())
+	assert.NoDirExists(t, filepath.Join(staging, first))
+	assert.FileExists(t, filepath.Join(staging, second, "extra", "data.json"))
+
+	status = w.Sync()
+	require.Equal(t, policywatch.StateOK, status.State, status.Errors)
+
+	unchanged, err := os.Readlink(w.Staging())
+	require.NoError(t, err)
+	assert.Equal(t, second, unchanged)
+}
+
+func TestStatusProto(t *testing.T) {
+	status := &policywatch.Status{
+		Instance: "policy",
+		State:    policywatch.StateError,
+		Errors:   []*policywatch.CompileError{{File: "policy.rego", Row: 3, Col: 1, Message: "rego_parse_error"}},
+	}
+
+	msg := status.Proto()
+	assert.Equal(t, "error", msg.GetState())
+	assert.Nil(t, msg.GetLoadedAt())
+	require.Len(t, msg.GetErrors(), 1)
+	assert.Equal(t, int32(3), msg.GetErrors()[0].GetRow())
+}
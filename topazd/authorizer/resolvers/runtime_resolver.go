@@ -4,8 +4,17 @@ import (
 	"context"
 
 	runtime "github.com/aserto-dev/runtime"
+	"github.com/aserto-dev/topaz/topazd/authorizer/policywatch"
 )
 
 type RuntimeResolver interface {
-	GetRuntime(ctx context.Context) (*runtime.Runtime, error)
+	// GetRuntime, returns the runtime of the named policy instance, when no name is provided,
+	// the runtime of the policy instance serving the policy path, or the default runtime.
+	GetRuntime(ctx context.Context, name, path string) (*runtime.Runtime, error)
+	// Instances, returns the names of the policy instances, starting with the default instance.
+	Instances() []string
+	// Shadow, returns the runtime of the shadow policy, nil when no shadow policy is configured.
+	Shadow() *runtime.Runtime
+	// PolicyStatus, returns the compile status of the watched local policy directories.
+	PolicyStatus() []*policywatch.Status
 }
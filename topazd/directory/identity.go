@@ -31,25 +31,44 @@ const (
 	userToIdentity
 )
 
-func resolutionDirection(ctx context.Context, client dsr.ReaderClient) identityResolutionDirection {
-	resp, _ := client.Check(ctx, &dsr.CheckRequest{
+// resolutionDirection, returns the identity resolution direction of the model, the error of the
+// directory is returned, instead of assuming a direction.
+func resolutionDirection(ctx context.Context, client dsr.ReaderClient) (identityResolutionDirection, error) {
+	resp, err := client.Check(ctx, &dsr.CheckRequest{
 		ObjectType:  User,
 		ObjectId:    DummyUser,
 		Relation:    Identifier,
 		SubjectType: Identity,
 		SubjectId:   DummyIdentity,
 	})
+	if err != nil {
+		return 0, err
+	}
 
 	reason, ok := resp.GetContext().GetFields()[Reason]
 	if ok && strings.HasPrefix(reason.GetStringValue(), derr.ErrRelationTypeNotFound.Code+" "+derr.ErrRelationTypeNotFound.Message) {
-		return identityToUser
+		return identityToUser, nil
 	}
 
-	return userToIdentity
+	return userToIdentity, nil
 }
 
 func ResolveIdentity(ctx context.Context, client dsr.ReaderClient, identity string) (*dsc.Object, error) {
-	switch resolutionDirection(ctx, client) {
+	direction, err := resolutionDirection(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveIdentityWithDirection(ctx, client, identity, direction)
+}
+
+func resolveIdentityWithDirection(
+	ctx context.Context,
+	client dsr.ReaderClient,
+	identity string,
+	direction identityResolutionDirection,
+) (*dsc.Object, error) {
+	switch direction {
 	// object_type:identity->subject_type:user (legacy).
 	case identityToUser:
 		return resolveIdentityLegacy(ctx, client, identity)
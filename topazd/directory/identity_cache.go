@@ -0,0 +1,181 @@
+package directory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/ds"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultIdentityCacheTTL, time to live of a cached identity, when no TTL is provided.
+const DefaultIdentityCacheTTL = 60 * time.Second
+
+var identityCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "topaz_identity_cache_requests_total",
+		Help: "Number of identity cache lookups, by cache (user, direction) and result (hit, miss).",
+	},
+	[]string{"cache", "result"},
+)
+
+// IdentityCacheCollectors, returns the identity cache metrics collectors.
+func IdentityCacheCollectors() []prometheus.Collector {
+	return []prometheus.Collector{identityCacheRequests}
+}
+
+// IdentityCache, a TTL and size bounded cache of the identity to user resolution,
+// and a memo of the resolution direction of the model.
+//
+// The cache is invalidated when identity relations, user or identity objects, or the manifest
+// of the in-process directory change, changes of a remote directory are observed after the TTL.
+type IdentityCache struct {
+	users    *expirable.LRU[string, *dsc.Object]
+	ttl      time.Duration
+	mu       sync.Mutex
+	dir      identityResolutionDirection
+	expires  time.Time
+	unsubscr func()
+
+	// usersMu, guards the generation, which is incremented by each purge of the users, a resolution
+	// which started before a purge is not cached, as it may have read the purged relations.
+	usersMu    sync.Mutex
+	generation uint64
+}
+
+// NewIdentityCache, returns an identity cache, a nil cache (size <= 0) resolves every identity using the directory.
+func NewIdentityCache(size int, ttl time.Duration) *IdentityCache {
+	if size <= 0 {
+		return nil
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultIdentityCacheTTL
+	}
+
+	c := &IdentityCache{
+		users: expirable.NewLRU[string, *dsc.Object](size, nil, ttl),
+		ttl:   ttl,
+	}
+
+	c.unsubscr = ds.OnChange(c.invalidate)
+
+	return c
+}
+
+// ResolveIdentity, resolves the user object of the identity.
+func (c *IdentityCache) ResolveIdentity(ctx context.Context, client dsr.ReaderClient, identity string) (*dsc.Object, error) {
+	if c == nil {
+		return ResolveIdentity(ctx, client, identity)
+	}
+
+	if user, ok := c.users.Get(identity); ok {
+		identityCacheRequests.WithLabelValues("user", "hit").Inc()
+		return user, nil
+	}
+
+	identityCacheRequests.WithLabelValues("user", "miss").Inc()
+
+	generation := c.currentGeneration()
+
+	direction, err := c.direction(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := resolveIdentityWithDirection(ctx, client, identity, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(generation, identity, user)
+
+	return user, nil
+}
+
+func (c *IdentityCache) currentGeneration() uint64 {
+	c.usersMu.Lock()
+	defer c.usersMu.Unlock()
+
+	return c.generation
+}
+
+// add, caches the user of the identity, unless the users were purged after the resolution started.
+func (c *IdentityCache) add(generation uint64, identity string, user *dsc.Object) {
+	c.usersMu.Lock()
+	defer c.usersMu.Unlock()
+
+	if generation == c.generation {
+		c.users.Add(identity, user)
+	}
+}
+
+// purge, removes the cached users, and invalidates the resolutions in progress.
+func (c *IdentityCache) purge() {
+	c.usersMu.Lock()
+	defer c.usersMu.Unlock()
+
+	c.generation++
+	c.users.Purge()
+}
+
+// Close, stops the invalidation of the cache.
+func (c *IdentityCache) Close() {
+	if c == nil || c.unsubscr == nil {
+		return
+	}
+
+	c.unsubscr()
+}
+
+// direction, returns the memoized resolution direction, which is determined once per model and TTL,
+// a failure to determine the direction is not memoized.
+func (c *IdentityCache) direction(ctx context.Context, client dsr.ReaderClient) (identityResolutionDirection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dir != 0 && time.Now().Before(c.expires) {
+		identityCacheRequests.WithLabelValues("direction", "hit").Inc()
+		return c.dir, nil
+	}
+
+	identityCacheRequests.WithLabelValues("direction", "miss").Inc()
+
+	dir, err := resolutionDirection(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+
+	c.dir = dir
+	c.expires = time.Now().Add(c.ttl)
+
+	return c.dir, nil
+}
+
+// invalidate, purges the cache on changes of the manifest, identity relations, and user or identity objects.
+func (c *IdentityCache) invalidate(change *ds.Change) {
+	switch {
+	case change.Manifest:
+		c.mu.Lock()
+		c.dir = 0
+		c.mu.Unlock()
+
+		c.purge()
+
+	case change.Object != nil && isIdentityType(change.Object.GetObjectType()):
+		c.purge()
+
+	case change.Relation != nil && change.Relation.GetRelation() == Identifier &&
+		isIdentityType(change.Relation.GetObjectType()) && isIdentityType(change.Relation.GetSubjectType()):
+		c.purge()
+	}
+}
+
+func isIdentityType(objType string) bool {
+	return objType == User || objType == Identity
+}
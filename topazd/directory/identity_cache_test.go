@@ -0,0 +1,196 @@
+package directory_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	dsc "github.com/aserto-dev/go-directory/aserto/directory/common/v3"
+	dsm "github.com/aserto-dev/go-directory/aserto/directory/model/v3"
+	dsr "github.com/aserto-dev/go-directory/aserto/directory/reader/v3"
+	dsw "github.com/aserto-dev/go-directory/aserto/directory/writer/v3"
+	eds "github.com/aserto-dev/topaz/internal/eds/pkg/directory"
+	"github.com/aserto-dev/topaz/internal/eds/pkg/server"
+	"github.com/aserto-dev/topaz/topazd/directory"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const manifest = `
+model:
+  version: 3
+
+types:
+  user:
+    relations:
+      identifier: identity
+  identity: {}
+`
+
+// newTestDirectory, returns the client of an in-process directory with the identity model, and a
+// function assigning an identity to a user.
+func newTestDirectory(t *testing.T) (*server.TestEdgeClient, func(userID, identity string)) {
+	t.Helper()
+
+	ctx := context.Background()
+	logger := zerolog.New(io.Discard)
+
+	client, closer := server.NewTestEdgeServer(ctx, &logger, &eds.Config{
+		DBPath:         filepath.Join(t.TempDir(), "test-eds.db"),
+		RequestTimeout: 2 * time.Second,
+		Seed:           true,
+	})
+	t.Cleanup(closer)
+
+	stream, err := client.V3.Model.SetManifest(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&dsm.SetManifestRequest{Msg: &dsm.SetManifestRequest_Body{Body: &dsm.Body{Data: []byte(manifest)}}}))
+
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	w := client.V3.Writer
+
+	setIdentity := func(userID, identity string) {
+		t.Helper()
+
+		_, err := w.SetObject(ctx, &dsw.SetObjectRequest{Object: &dsc.Object{Type: directory.User, Id: userID}})
+		require.NoError(t, err)
+
+		_, err = w.SetObject(ctx, &dsw.SetObjectRequest{Object: &dsc.Object{Type: directory.Identity, Id: identity}})
+		require.NoError(t, err)
+
+		_, err = w.SetRelation(ctx, &dsw.SetRelationRequest{Relation: &dsc.Relation{
+			ObjectType:  directory.User,
+			ObjectId:    userID,
+			Relation:    directory.Identifier,
+			SubjectType: directory.Identity,
+			SubjectId:   identity,
+		}})
+		require.NoError(t, err)
+	}
+
+	return client, setIdentity
+}
+
+func TestIdentityCache(t *testing.T) {
+	ctx := context.Background()
+	client, setIdentity := newTestDirectory(t)
+	w := client.V3.Writer
+
+	setIdentity("euang", "euang@acmecorp.com")
+
+	cache := directory.NewIdentityCache(10, time.Minute)
+	t.Cleanup(cache.Close)
+
+	user, err := cache.ResolveIdentity(ctx, client.V3.Reader, "euang@acmecorp.com")
+	require.NoError(t, err)
+	require.Equal(t, "euang", user.GetId())
+
+	// re-assigning the identity to another user invalidates the cached resolution.
+	_, err = w.DeleteRelation(ctx, &dsw.DeleteRelationRequest{
+		ObjectType:  directory.User,
+		ObjectId:    "euang",
+		Relation:    directory.Identifier,
+		SubjectType: directory.Identity,
+		SubjectId:   "euang@acmecorp.com",
+	})
+	require.NoError(t, err)
+
+	setIdentity("kris", "euang@acmecorp.com")
+
+	user, err = cache.ResolveIdentity(ctx, client.V3.Reader, "euang@acmecorp.com")
+	require.NoError(t, err)
+	require.Equal(t, "kris", user.GetId())
+
+	// a nil cache resolves using the directory.
+	var noCache *directory.IdentityCache
+
+	user, err = noCache.ResolveIdentity(ctx, client.V3.Reader, "euang@acmecorp.com")
+	require.NoError(t, err)
+	require.Equal(t, "kris", user.GetId())
+}
+
+// racingReader, calls the hook once, after the first GetRelation of the identity resolution.
+type racingReader struct {
+	dsr.ReaderClient
+
+	once sync.Once
+	hook func()
+}
+
+func (r *racingReader) GetRelation(ctx context.Context, in *dsr.GetRelationRequest, opts ...grpc.CallOption) (*dsr.GetRelationResponse, error) {
+	resp, err := r.ReaderClient.GetRelation(ctx, in, opts...)
+
+	r.once.Do(r.hook)
+
+	return resp, err
+}
+
+func TestIdentityCacheInvalidatedDuringResolve(t *testing.T) {
+	ctx := context.Background()
+	// the in-process directory is shared by the tests, each test uses its own identities.
+	client, setIdentity := newTestDirectory(t)
+
+	setIdentity("beth", "beth@the-smiths.com")
+
+	cache := directory.NewIdentityCache(10, time.Minute)
+	t.Cleanup(cache.Close)
+
+	// the identity is re-assigned after the directory was read, but before the resolution is cached.
+	reader := &racingReader{ReaderClient: client.V3.Reader, hook: func() {
+		_, err := client.V3.Writer.DeleteRelation(ctx, &dsw.DeleteRelationRequest{
+			ObjectType:  directory.User,
+			ObjectId:    "beth",
+			Relation:    directory.Identifier,
+			SubjectType: directory.Identity,
+			SubjectId:   "beth@the-smiths.com",
+		})
+		require.NoError(t, err)
+
+		setIdentity("morty", "beth@the-smiths.com")
+	}}
+
+	user, err := cache.ResolveIdentity(ctx, reader, "beth@the-smiths.com")
+	require.NoError(t, err)
+	require.Equal(t, "beth", user.GetId())
+
+	// the resolution which raced with the invalidation is not cached.
+	user, err = cache.ResolveIdentity(ctx, reader, "beth@the-smiths.com")
+	require.NoError(t, err)
+	require.Equal(t, "morty", user.GetId())
+}
+
+// failingReader, fails the checks of the resolution direction.
+type failingReader struct {
+	dsr.ReaderClient
+}
+
+func (failingReader) Check(context.Context, *dsr.CheckRequest, ...grpc.CallOption) (*dsr.CheckResponse, error) {
+	return nil, status.Error(codes.Unavailable, "directory unavailable")
+}
+
+func TestIdentityCacheDirectionError(t *testing.T) {
+	ctx := context.Background()
+	client, setIdentity := newTestDirectory(t)
+
+	setIdentity("rick", "rick@the-citadel.com")
+
+	cache := directory.NewIdentityCache(10, time.Minute)
+	t.Cleanup(cache.Close)
+
+	_, err := cache.ResolveIdentity(ctx, failingReader{client.V3.Reader}, "rick@the-citadel.com")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// the failure is not memoized, the next resolution determines the direction.
+	user, err := cache.ResolveIdentity(ctx, client.V3.Reader, "rick@the-citadel.com")
+	require.NoError(t, err)
+	require.Equal(t, "rick", user.GetId())
+}
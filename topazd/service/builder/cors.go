@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rs/cors"
+)
+
+// CORS, the CORS handler of a gateway, the allowed origins and methods can be replaced while the
+// gateway is serving, the allowed headers are also forwarded to the gRPC services, and are fixed.
+type CORS struct {
+	allowedHeaders []string
+	cors           atomic.Pointer[cors.Cors]
+}
+
+func newCORS(allowedHeaders, allowedOrigins, allowedMethods []string) *CORS {
+	c := &CORS{allowedHeaders: allowedHeaders}
+	c.Update(allowedOrigins, allowedMethods)
+
+	return c
+}
+
+// Update, replaces the allowed origins and methods, the defaults apply when empty.
+func (c *CORS) Update(allowedOrigins, allowedMethods []string) {
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = DefaultGatewayAllowedOrigins
+	}
+
+	if len(allowedMethods) == 0 {
+		allowedMethods = DefaultGatewayAllowedMethods
+	}
+
+	c.cors.Store(cors.New(cors.Options{
+		AllowedHeaders: c.allowedHeaders,
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: allowedMethods,
+		Debug:          false,
+	}))
+}
+
+// Handler, applies the current CORS options to the requests of the handler.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.cors.Load().ServeHTTP(w, r, next.ServeHTTP)
+	})
+}
@@ -19,7 +19,7 @@ type Health struct {
 func newGRPCHealthServer(certCfg *aserto.TLSConfig) *Health {
 	healthServer := health.NewServer()
 
-	grpcHealthServer, err := prepareGrpcServer(certCfg, nil)
+	grpcHealthServer, err := prepareGrpcServer(certCfg, nil, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -34,6 +34,7 @@ type Gateway struct {
 	Server *http.Server
 	Mux    *http.ServeMux
 	Certs  *aserto.TLSConfig
+	CORS   *CORS
 }
 
 type API struct {
@@ -2,23 +2,30 @@ package builder
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"math"
 	"net"
 	"net/http"
 	goruntime "runtime"
 	"strconv"
+	"strings"
 
 	"github.com/aserto-dev/go-aserto"
+	"github.com/aserto-dev/topaz/internal/certs"
+	"github.com/aserto-dev/topaz/internal/grpc/middlewares/ratelimit"
+	"github.com/aserto-dev/topaz/internal/telemetry"
+	"github.com/aserto-dev/topaz/topazd/authentication/mtls"
 	"github.com/samber/lo"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/rs/cors"
 	metrics "github.com/slok/go-http-metrics/metrics/prometheus"
 	"github.com/slok/go-http-metrics/middleware"
 	"github.com/slok/go-http-metrics/middleware/std"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
@@ -47,6 +54,9 @@ func NewServiceFactory() *ServiceFactory {
 type GRPCOptions struct {
 	ServerOptions []grpc.ServerOption
 	Registrations GRPCRegistrations
+	// ClientCAs, the CA bundles verifying client certificates, when set, the gRPC and gateway
+	// servers request client certificates, and verify them when given.
+	ClientCAs []string
 }
 
 type GatewayOptions struct {
@@ -60,7 +70,12 @@ func (f *ServiceFactory) CreateService(
 	gatewayOpts *GatewayOptions,
 	cleanup ...func(),
 ) (*Service, error) {
-	grpcServer, err := prepareGrpcServer(&config.GRPC.Certs, grpcOpts.ServerOptions)
+	clientCAs, err := clientCertPool(&config.GRPC.Certs, grpcOpts.ClientCAs)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer, err := prepareGrpcServer(&config.GRPC.Certs, grpcOpts.ServerOptions, clientCAs)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +91,7 @@ func (f *ServiceFactory) CreateService(
 	var gateway *Gateway
 
 	if gatewayOpts != nil && config.Gateway.ListenAddress != "" {
-		if gateway, err = f.prepareGateway(config, gatewayOpts); err != nil {
+		if gateway, err = f.prepareGateway(config, gatewayOpts, clientCAs); err != nil {
 			return nil, err
 		}
 	}
@@ -92,7 +107,7 @@ func (f *ServiceFactory) CreateService(
 }
 
 // prepareGateway provides a http server that will have the registrations pointed to the corresponding configured grpc server.
-func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions) (*Gateway, error) {
+func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions, clientCAs *x509.CertPool) (*Gateway, error) {
 	if len(config.Gateway.AllowedHeaders) == 0 {
 		config.Gateway.AllowedHeaders = DefaultGatewayAllowedHeaders
 	}
@@ -105,12 +120,7 @@ func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions
 		config.Gateway.AllowedMethods = DefaultGatewayAllowedMethods
 	}
 
-	c := cors.New(cors.Options{
-		AllowedHeaders: config.Gateway.AllowedHeaders,
-		AllowedOrigins: config.Gateway.AllowedOrigins,
-		AllowedMethods: config.Gateway.AllowedMethods,
-		Debug:          false,
-	})
+	c := newCORS(config.Gateway.AllowedHeaders, config.Gateway.AllowedOrigins, config.Gateway.AllowedMethods)
 
 	runtimeMux := f.gatewayMux(config.Gateway.AllowedHeaders, gatewayOpts.ErrorHandler)
 
@@ -127,6 +137,8 @@ func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	opts = append(opts, telemetry.DialOptions()...)
+
 	grpcEndpoint := "dns:///" + config.GRPC.ListenAddress
 
 	if err := gatewayOpts.HandlerRegistrations(context.Background(), runtimeMux, grpcEndpoint, opts); err != nil {
@@ -137,7 +149,7 @@ func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions
 	mux.Handle("/", runtimeMux)
 	mux.Handle("/api/", fieldsMaskHandler(runtimeMux))
 
-	gtwHandler := std.Handler("", mdlw, mux)
+	gtwHandler := telemetry.Handler(std.Handler("", mdlw, mux))
 
 	gtwServer := &http.Server{
 		Addr:              config.Gateway.ListenAddress,
@@ -151,7 +163,7 @@ func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions
 	config.Gateway.HTTP = !config.Gateway.Certs.HasCert()
 
 	if config.Gateway.HTTP {
-		return &Gateway{Server: gtwServer, Mux: mux, Certs: nil}, nil
+		return &Gateway{Server: gtwServer, Mux: mux, Certs: nil, CORS: c}, nil
 	}
 
 	tlsServerConfig, err := config.Gateway.Certs.ServerConfig()
@@ -159,22 +171,42 @@ func (f *ServiceFactory) prepareGateway(config *API, gatewayOpts *GatewayOptions
 		return nil, err
 	}
 
+	if clientCAs != nil {
+		tlsServerConfig.ClientCAs = clientCAs
+		tlsServerConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
 	gtwServer.TLSConfig = tlsServerConfig
 
-	return &Gateway{Server: gtwServer, Mux: mux, Certs: &config.Gateway.Certs}, nil
+	return &Gateway{Server: gtwServer, Mux: mux, Certs: &config.Gateway.Certs, CORS: c}, nil
 }
 
 // gatewayMux creates a gateway multiplexer for serving the API as an OpenAPI endpoint.
 func (f *ServiceFactory) gatewayMux(allowedHeaders []string, errorHandler runtime.ErrorHandlerFunc) *runtime.ServeMux {
 	opts := []runtime.ServeMuxOption{
 		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			// the forwarded client certificate is only set by the gateway, from the verified client certificate.
+			if strings.EqualFold(key, runtime.MetadataHeaderPrefix+mtls.MetadataClientCert) ||
+				strings.EqualFold(key, mtls.MetadataClientCert) {
+				return "", false
+			}
+
 			if lo.Contains(allowedHeaders, key) {
 				return key, true
 			}
 
 			return runtime.DefaultHeaderMatcher(key)
 		}),
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			// the retry-after header of the rate limited calls is returned as the standard HTTP header.
+			if key == ratelimit.HeaderRetryAfter {
+				return "Retry-After", true
+			}
+
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
 		runtime.WithMetadata(captureGatewayRoute),
+		runtime.WithMetadata(forwardClientCert),
 		runtime.WithMarshalerOption(
 			runtime.MIMEWildcard,
 			&runtime.JSONPb{
@@ -256,7 +288,7 @@ func httpResponseModifier(ctx context.Context, w http.ResponseWriter, p proto.Me
 // NumStreamWorkers is marked Experimental in grpc-go (server.go:609)
 // but has been stable since 2022; the option is what production gRPC
 // servers use to bound goroutine creation under high RPS.
-func prepareGrpcServer(certCfg *aserto.TLSConfig, opts []grpc.ServerOption) (*grpc.Server, error) {
+func prepareGrpcServer(certCfg *aserto.TLSConfig, opts []grpc.ServerOption, clientCAs *x509.CertPool) (*grpc.Server, error) {
 	opts = append(opts, grpc.NumStreamWorkers(numCPU()))
 
 	// NoTLS path.
@@ -266,17 +298,52 @@ func prepareGrpcServer(certCfg *aserto.TLSConfig, opts []grpc.ServerOption) (*gr
 	}
 
 	// TLS path.
-	tlsCreds, err := certCfg.ServerCredentials()
+	tlsConfig, err := certCfg.ServerConfig()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get TLS credentials")
 	}
 
-	tlsAuth := grpc.Creds(tlsCreds)
+	// mTLS, client certificates are verified when given, the authentication middleware
+	// decides whether a call requires a client certificate.
+	if clientCAs != nil {
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	tlsAuth := grpc.Creds(credentials.NewTLS(tlsConfig))
 	opts = append(opts, tlsAuth)
 
 	return grpc.NewServer(opts...), nil
 }
 
+// clientCertPool, returns the pool verifying client certificates, the CA of the service
+// certificates and the client CA bundles, nil when no client CA bundles are configured.
+func clientCertPool(certCfg *aserto.TLSConfig, clientCAs []string) (*x509.CertPool, error) {
+	if len(clientCAs) == 0 || !certCfg.HasCert() {
+		return nil, nil //nolint:nilnil // client certificates are not verified.
+	}
+
+	caPaths := append([]string{certCfg.CA}, clientCAs...)
+
+	// dev certs generated before client certificate authentication cannot verify client certificates.
+	for _, caPath := range caPaths {
+		if caPath == "" {
+			continue
+		}
+
+		if err := certs.CheckClientCA(caPath); err != nil {
+			return nil, err
+		}
+	}
+
+	pool, err := mtls.CertPool(caPaths...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load client CA certificates")
+	}
+
+	return pool, nil
+}
+
 // fieldsMaskHandler will set the Content-Type to "application/json+masked", which
 // will signal the marshaler to not emit unpopulated types, which is needed to
 // serialize the masked result set.
@@ -291,31 +358,22 @@ func fieldsMaskHandler(h http.Handler) http.Handler {
 	})
 }
 
-type key int
-
-var pathPatternKey key
-
-type gatewayPathPattern struct {
-	PathPattern string
-}
-
+// captureGatewayRoute, sets the path pattern matched by the gateway as the route of the request span.
 func captureGatewayRoute(ctx context.Context, r *http.Request) metadata.MD {
 	if pattern, ok := runtime.HTTPPathPattern(ctx); ok {
-		if gwPathPattern := gatewayContextValue(r); gwPathPattern != nil {
-			gwPathPattern.PathPattern = pattern
-		}
+		telemetry.SetRoute(r.Context(), pattern)
 	}
 
 	return nil
 }
 
-func gatewayContextValue(r *http.Request) *gatewayPathPattern {
-	gwPathPattern, ok := r.Context().Value(pathPatternKey).(*gatewayPathPattern)
-	if !ok {
-		return nil
+// forwardClientCert, forwards the verified client certificate of the gateway request to the gRPC endpoint.
+func forwardClientCert(_ context.Context, r *http.Request) metadata.MD {
+	if cert := mtls.Forward(r.TLS); cert != "" {
+		return metadata.Pairs(mtls.MetadataClientCert, cert)
 	}
 
-	return gwPathPattern
+	return nil
 }
 
 func numCPU() uint32 {
@@ -12,6 +12,7 @@ import (
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	go_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -29,6 +30,7 @@ type ServiceManager struct {
 	DependencyMap   map[string][]string
 	HealthServer    *Health
 	MetricServer    *http.Server
+	metricsMux      *http.ServeMux
 	shutdownTimeout int // timeout to force stop services in seconds
 }
 
@@ -52,6 +54,26 @@ func NewServiceManager(logger *zerolog.Logger) *ServiceManager {
 	}
 }
 
+// RegisterCollectors, registers additional metrics collectors, a collector which is already registered is ignored.
+func RegisterCollectors(collectors ...prometheus.Collector) error {
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if errors.As(err, &are) {
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *ServiceManager) AddGRPCServer(server *Service) error {
 	s.Servers[server.Config.GRPC.ListenAddress] = server
 	return nil
@@ -104,6 +126,7 @@ func (s *ServiceManager) SetupMetricsServer(address string, certCfg *aserto.TLSC
 
 	metric.Handler = mux
 	metric.Addr = address
+	s.metricsMux = mux
 
 	s.logger.Info().Msgf("Starting %s metric server", address)
 
@@ -135,6 +158,18 @@ func (s *ServiceManager) SetupMetricsServer(address string, certCfg *aserto.TLSC
 	return opts, nil
 }
 
+// HandleMetrics, registers an administrative handler on the metrics server, returns false when
+// the metrics server is not configured.
+func (s *ServiceManager) HandleMetrics(pattern string, handler http.Handler) bool {
+	if s.metricsMux == nil {
+		return false
+	}
+
+	s.metricsMux.Handle(pattern, handler)
+
+	return true
+}
+
 func (s *ServiceManager) StartServers(ctx context.Context) error {
 	for serverAddress, value := range s.Servers {
 		address := serverAddress
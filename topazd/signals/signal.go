@@ -44,3 +44,22 @@ func SetupSignalHandler() context.Context {
 
 	return ctx
 }
+
+// NotifyReload registers for SIGHUP. A channel is returned which receives the reload
+// signals until the context is done, no signal is received on platforms without SIGHUP.
+func NotifyReload(ctx context.Context) <-chan os.Signal {
+	c := make(chan os.Signal, 1)
+
+	if len(reloadSignals) == 0 {
+		return c
+	}
+
+	signal.Notify(c, reloadSignals...)
+
+	go func() {
+		<-ctx.Done()
+		signal.Stop(c)
+	}()
+
+	return c
+}
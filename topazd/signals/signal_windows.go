@@ -20,4 +20,7 @@ import (
 	"os"
 )
 
-var shutdownSignals = []os.Signal{os.Interrupt}
+var (
+	shutdownSignals = []os.Signal{os.Interrupt}
+	reloadSignals   = []os.Signal{}
+)
@@ -273,7 +273,7 @@ var BuiltinHelpTests = []struct {
 	"subject": {"type": "", "id": "", "properties": {}},
 	"action": {"name": "", "properties": {}},
 	"resource": {"type": "", "id": "", "properties": {}},
-	"context": {},
+	"context": {"include_relations": false},
 	"page": {
 		"limit": 100,
 		"token": ""
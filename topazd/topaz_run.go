@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"os"
+	"time"
 
+	"github.com/aserto-dev/topaz/internal/telemetry"
 	"github.com/aserto-dev/topaz/pkg/config"
 	"github.com/aserto-dev/topaz/topazd/app"
 	"github.com/aserto-dev/topaz/topazd/app/directory"
 	"github.com/aserto-dev/topaz/topazd/app/topaz"
 	"github.com/aserto-dev/topaz/topazd/debug"
+	"github.com/aserto-dev/topaz/topazd/signals"
 	"github.com/spf13/cobra"
 )
 
+const tracingShutdownTimeout = 5 * time.Second
+
 var (
 	flagRunConfigFile        string
 	flagRunBundleFiles       []string
@@ -35,10 +41,33 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	shutdownTracing, err := telemetry.Setup(topazApp.Context, &topazApp.Configuration.Tracing)
+	if err != nil {
+		return err
+	}
+
+	// deferred first, so the spans of the stopping servers are flushed as well.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			topazApp.Logger.Error().Err(err).Msg("failed to flush trace spans")
+		}
+	}()
+
 	defer topazApp.Manager.StopServers()
 
 	defer cleanup()
 
+	// the reloader records the configuration as loaded, before the services complete it.
+	reloader, err := topaz.NewReloader(topazApp, configPath, configOverrides)
+	if err != nil {
+		return err
+	}
+
+	reloadSignals := signals.NotifyReload(topazApp.Context)
+
 	if err := topazApp.ConfigServices(); err != nil {
 		return err
 	}
@@ -50,7 +79,7 @@ func run(cmd *cobra.Command, args []string) error {
 		defer debugService.Stop()
 	}
 
-	if _, ok := topazApp.Services["authorizer"]; ok {
+	if authorizer, ok := topazApp.Services["authorizer"].(*app.Authorizer); ok {
 		dirResolver, err := directory.NewResolver(topazApp.Logger, &topazApp.Configuration.DirectoryResolver)
 		if err != nil {
 			return err
@@ -63,6 +92,9 @@ func run(cmd *cobra.Command, args []string) error {
 			topazApp.Logger,
 			topazApp.Configuration,
 			dirResolver.GetConn(),
+			// the directory access service is replaced by the authorizer policies, when the directory
+			// shares the endpoint of the authorizer, the az.* built-ins call the directory in-process.
+			authorizer.DirectoryAccess(),
 		)
 		if err != nil {
 			return err
@@ -70,10 +102,8 @@ func run(cmd *cobra.Command, args []string) error {
 
 		defer runtimeCleanup()
 
-		if authorizer, ok := topazApp.Services["authorizer"].(*app.Authorizer); ok {
-			authorizer.Resolver.SetRuntimeResolver(runtime)
-			authorizer.Resolver.SetDirectoryResolver(dirResolver)
-		}
+		authorizer.Resolver.SetRuntimeResolver(runtime)
+		authorizer.Resolver.SetDirectoryResolver(dirResolver)
 	}
 
 	err = topazApp.Start()
@@ -81,6 +111,14 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if !topazApp.Manager.HandleMetrics(app.ConfigStatusPath, reloader.Handler()) {
+		topazApp.Logger.Info().Msg("metrics server not configured, configuration reload status not served")
+	}
+
+	if err := reloader.Watch(topazApp.Context, string(configPath), reloadSignals); err != nil {
+		return err
+	}
+
 	<-topazApp.Context.Done()
 
 	return nil